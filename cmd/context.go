@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// exitIncomplete is the exit code used when a scan is cancelled or times out
+// before finishing. Partial results are still written.
+const exitIncomplete = 3
+
+// newCommandContext returns the root context for a command.
+//
+// The context is cancelled on SIGINT or SIGTERM, and after timeout when it
+// is positive. Once cancelled, default signal handling is restored so that
+// a second Ctrl-C terminates the process immediately.
+func newCommandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	cancel := context.CancelFunc(stop)
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		cancel = func() {
+			cancelTimeout()
+			stop()
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, cancel
+}
+
+// cancellationReason describes why ctx was cancelled ("timed out" or "interrupted").
+func cancellationReason(ctx context.Context) string {
+	if ctx.Err() == context.DeadlineExceeded {
+		return "timed out"
+	}
+	return "interrupted"
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/inayathulla/cloudrift/internal/common"
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/importgen"
+	"github.com/inayathulla/cloudrift/internal/snapshot"
+)
+
+// Command-line flags for the import-gen command.
+var (
+	importGenConfigPath string   // Path to cloudrift config file
+	importGenServices   string   // Comma-separated services to inspect
+	importGenOutput     string   // File to write (stdout if empty)
+	importGenSkeletons  bool     // Also emit skeleton resource blocks
+	importGenSnapshot   string   // Read live state from a snapshot file instead of AWS
+	importGenExclusions []string // Exclusion rules for unmanaged resources
+)
+
+// importGenCmd implements the "cloudrift import-gen" subcommand.
+//
+// It finds live resources that are not in the Terraform plan, the same way
+// "cloudrift scan --detect-unmanaged" does, and writes Terraform import
+// blocks (and optionally resource skeletons) to adopt them.
+var importGenCmd = &cobra.Command{
+	Use:   "import-gen",
+	Short: "Generate Terraform import blocks for unmanaged AWS resources",
+	Long: `Import-gen finds live AWS resources that no resource in the Terraform plan
+refers to and writes Terraform 1.5+ import blocks for them. With --skeleton,
+each import block is followed by a resource block populated from the live
+state. Resource names are derived from the AWS names.
+
+Progress is written to stderr, so the generated configuration can be
+redirected to a file.
+
+Flags:
+  --config, -c         Path to cloudrift config file (plan path, AWS profile and region)
+  --service, -s        Comma-separated services to inspect (supports: s3, ec2, iam, all)
+  --output, -o         Write the configuration to a file instead of stdout
+  --skeleton           Also generate skeleton resource blocks
+  --live-snapshot      Read live state from a snapshot file (no AWS credentials needed)
+  --exclude-unmanaged  Exclude resources by name:<glob> or tag:<key>[=<glob>] (repeatable)
+
+Example:
+  cloudrift import-gen --config=config/cloudrift-s3.yml --service=s3 --output=imports.tf
+  cloudrift import-gen --service=iam --skeleton > iam_imports.tf
+  terraform plan   # review the imports before applying`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initIcons()
+
+		ctx, cancel := newCommandContext(0)
+		defer cancel()
+
+		services, err := parseServiceList(importGenServices)
+		if err != nil {
+			exitWithError("%s %v", icons.Cross, err)
+		}
+
+		profile, region, planPath, err := common.LoadAppConfig(importGenConfigPath)
+		if err != nil {
+			exitWithError("%s Failed to load config: %v", icons.Cross, err)
+		}
+		if planPath == "" {
+			exitWithError("%s 'plan_path' not found in config", icons.Cross)
+		}
+		exclusions, err := loadExclusions(importGenExclusions)
+		if err != nil {
+			exitWithError("%s Invalid unmanaged resource exclusion: %v", icons.Cross, err)
+		}
+
+		var cfg sdkaws.Config
+		var snap *snapshot.Snapshot
+		if importGenSnapshot != "" {
+			if snap, err = snapshot.Load(importGenSnapshot); err != nil {
+				exitWithError("%s Failed to load live snapshot: %v", icons.Cross, err)
+			}
+		} else if cfg, err = common.InitAWS(ctx, profile, region); err != nil {
+			exitWithError("%s Failed to load AWS config: %v", icons.Cross, err)
+		}
+
+		gen := importgen.New(importGenSkeletons)
+		for _, svc := range services {
+			plan, err := loadServicePlan(svc, planPath)
+			if err != nil {
+				exitWithError("%s Failed to load plan: %v", icons.Cross, err)
+			}
+
+			var live interface{}
+			if snap != nil {
+				live, err = snap.LiveState(svc)
+			} else {
+				var det DriftDetector
+				if det, err = newDriftDetector(svc, cfg); err == nil {
+					live, err = det.FetchLiveState(ctx)
+				}
+			}
+			if ctx.Err() != nil {
+				exitWithError("%s Import generation %s; nothing written", icons.Cross, cancellationReason(ctx))
+			}
+			if err != nil {
+				exitWithError("%s Failed to fetch live %s state: %v", icons.Cross, strings.ToUpper(svc), err)
+			}
+			if errs := detector.FetchErrors(live); len(errs) > 0 {
+				fmt.Fprintln(os.Stderr, color.YellowString("%s Could not fetch %d %s resources; they may be missing from the output", icons.Warn, len(errs), strings.ToUpper(svc)))
+			}
+
+			unmanaged, err := detector.FindUnmanaged(plan, live, exclusions)
+			if err != nil {
+				exitWithError("%s %v", icons.Cross, err)
+			}
+			if err := gen.Add(unmanaged, live); err != nil {
+				exitWithError("%s %v", icons.Cross, err)
+			}
+			fmt.Fprintln(os.Stderr, color.YellowString("%s Found %d unmanaged %s resources", icons.Check, len(unmanaged), strings.ToUpper(svc)))
+		}
+
+		if gen.Count() == 0 {
+			fmt.Fprintln(os.Stderr, color.GreenString("%s No unmanaged resources found; nothing to import", icons.Check))
+			return
+		}
+
+		if importGenOutput == "" {
+			os.Stdout.Write(gen.Bytes())
+			return
+		}
+		if err := os.WriteFile(importGenOutput, gen.Bytes(), 0o644); err != nil {
+			exitWithError("%s Failed to write %s: %v", icons.Cross, importGenOutput, err)
+		}
+		fmt.Fprintln(os.Stderr, color.GreenString("%s %d import blocks written to %s", icons.Doc, gen.Count(), importGenOutput))
+	},
+}
+
+// loadServicePlan loads the planned resources for a service from the plan JSON.
+func loadServicePlan(svc, planPath string) (interface{}, error) {
+	switch svc {
+	case "s3":
+		return common.LoadPlan(planPath)
+	case "ec2":
+		return common.LoadEC2Plan(planPath)
+	case "iam":
+		return common.LoadIAMPlan(planPath)
+	default:
+		return nil, fmt.Errorf("unsupported service: %s (supported: %s)", svc, strings.Join(supportedServices, ", "))
+	}
+}
+
+// exitWithError prints a red message to stderr and exits with code 1.
+func exitWithError(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, color.RedString(format, args...))
+	os.Exit(1)
+}
+
+func init() {
+	importGenCmd.Flags().StringVarP(&importGenConfigPath, "config", "c", "cloudrift-s3.yml", "Path to Cloudrift config file")
+	importGenCmd.Flags().StringVarP(&importGenServices, "service", "s", "s3", "Comma-separated services to inspect (s3, ec2, iam, all)")
+	importGenCmd.Flags().StringVarP(&importGenOutput, "output", "o", "", "Write the configuration to a file instead of stdout")
+	importGenCmd.Flags().BoolVar(&importGenSkeletons, "skeleton", false, "Also generate skeleton resource blocks from the live state")
+	importGenCmd.Flags().StringVar(&importGenSnapshot, "live-snapshot", "", "Read live state from a snapshot file instead of querying AWS")
+	importGenCmd.Flags().StringArrayVar(&importGenExclusions, "exclude-unmanaged", nil, "Exclude resources by name:<glob> or tag:<key>[=<glob>] (repeatable)")
+	rootCmd.AddCommand(importGenCmd)
+}
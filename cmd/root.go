@@ -3,6 +3,9 @@
 // The CLI is built using Cobra and provides subcommands for different operations.
 // Currently supported commands:
 //   - scan: Detect drift between Terraform plans and live AWS state
+//   - snapshot: Record live AWS state to a file for offline scans
+//   - import-gen: Generate Terraform import blocks for unmanaged resources
+//   - schema: Print or validate against the JSON output schema
 //
 // Usage:
 //
@@ -3,40 +3,55 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/inayathulla/cloudrift/internal/aws"
 	"github.com/inayathulla/cloudrift/internal/common"
 	"github.com/inayathulla/cloudrift/internal/detector"
 	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/inayathulla/cloudrift/internal/output"
 	"github.com/inayathulla/cloudrift/internal/policy"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+	"github.com/inayathulla/cloudrift/internal/snapshot"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
 )
 
 // Command-line flags for the scan command.
 var (
-	configPath       string // Path to cloudrift-s3.yml configuration file
-	service          string // AWS service to scan (e.g., "s3", "ec2")
-	outputFormat     string // Output format (console, json, sarif)
-	outputFile       string // Output file path (optional)
-	policyDir        string // Directory containing custom OPA policies
-	failOnViolation  bool   // Exit with non-zero code if policy violations found
-	skipPolicies     bool   // Skip policy evaluation
-	noEmoji          bool   // Use ASCII characters instead of emojis
-	frameworksFilter string // Comma-separated compliance frameworks to evaluate
+	configPath       string        // Path to cloudrift-s3.yml configuration file
+	service          string        // AWS service to scan (e.g., "s3", "ec2")
+	outputFormat     string        // Output format (console, json, ndjson, sarif, remediation, html, junit, markdown, asff, ocsf, openmetrics, template)
+	outputFile       string        // Output file path (optional)
+	templatePath     string        // Template file or built-in template name for --format=template
+	extraOutputs     []string      // Additional outputs written from the same scan (<format>:<path>)
+	policyDir        string        // Directory containing custom OPA policies
+	failOnViolation  bool          // Exit with non-zero code if policy violations found
+	skipPolicies     bool          // Skip policy evaluation
+	noEmoji          bool          // Use ASCII characters instead of emojis
+	frameworksFilter string        // Comma-separated compliance frameworks to evaluate
+	liveSnapshotPath string        // Replay live state from a snapshot file instead of AWS
+	scanTimeout      time.Duration // Upper bound for the whole scan (0 = no limit)
+	detectUnmanaged  bool          // Report live resources that are not in the plan
+	excludeUnmanaged []string      // Exclusion rules for unmanaged resources (name:<glob>, tag:<key>[=<glob>])
+	tfDir            string        // Root module directory containing the .tf files
+	applyRemediation bool          // Rewrite the .tf files in --tf-dir to match the live state
 )
 
 // icons holds the characters used for status indicators (emoji or ASCII)
 var icons struct {
-	Rocket, Check, Cross, Lock, Doc, Warn, Gear, Pin, Msg string
+	Rocket, Check, Cross, Lock, Doc, Warn string
 }
 
 func initIcons() {
@@ -47,9 +62,6 @@ func initIcons() {
 		icons.Lock = "[>]"
 		icons.Doc = "[i]"
 		icons.Warn = "[!]"
-		icons.Gear = "[#]"
-		icons.Pin = "[-]"
-		icons.Msg = "[-]"
 	} else {
 		icons.Rocket = "🚀"
 		icons.Check = "✔️ "
@@ -57,9 +69,6 @@ func initIcons() {
 		icons.Lock = "🔐"
 		icons.Doc = "📄"
 		icons.Warn = "⚠️ "
-		icons.Gear = "🔧"
-		icons.Pin = "📍"
-		icons.Msg = "💬"
 	}
 }
 
@@ -69,7 +78,8 @@ func initIcons() {
 // to provide consistent drift detection behavior across services.
 type DriftDetector interface {
 	// FetchLiveState retrieves the current state of resources from AWS.
-	FetchLiveState() (interface{}, error)
+	// Implementations must return promptly once ctx is cancelled.
+	FetchLiveState(ctx context.Context) (interface{}, error)
 
 	// DetectDrift compares planned state against live state and returns differences.
 	DetectDrift(plan interface{}, live interface{}) ([]detector.DriftResult, error)
@@ -98,13 +108,25 @@ security and compliance violations.
 Flags:
   --config, -c         Path to cloudrift config file (e.g., cloudrift-s3.yml)
   --service, -s        AWS service to scan (supports: s3, ec2, iam)
-  --format, -f         Output format: console, json, sarif (default: console)
+  --format, -f         Output format: console, json, ndjson, sarif, remediation, html, junit, markdown, asff, ocsf, openmetrics, template (default: console)
   --output, -o         Write output to file instead of stdout
+  --template           Go template file, or built-in template (csv, table), for --format=template
+  --output-format      Also write <format>:<path> from the same scan, e.g. sarif:drift.sarif (repeatable)
   --policy-dir, -p     Directory containing custom OPA policies (.rego files)
   --fail-on-violation  Exit with non-zero code if policy violations are found
   --skip-policies      Skip policy evaluation (drift detection only)
   --no-emoji           Use ASCII characters instead of emojis
   --frameworks         Comma-separated compliance frameworks to evaluate (e.g., hipaa,soc2,gdpr)
+  --live-snapshot      Replay live state from a snapshot file (no AWS credentials needed)
+  --timeout            Abort the scan after this duration (e.g., 5m); partial results are still written
+  --detect-unmanaged   Also report live resources that no planned resource refers to
+  --exclude-unmanaged  Exclude unmanaged resources by name:<glob> or tag:<key>[=<glob>] (repeatable)
+  --tf-dir             Root module directory of the Terraform configuration, used to locate resources in SARIF/JSON
+  --apply-remediation  Rewrite the .tf files in --tf-dir so drifted arguments match AWS
+
+Pressing Ctrl-C (or sending SIGTERM) cancels in-flight AWS calls. Resources
+that were not fetched are reported as unknown, the partial results are
+written, and the command exits with code 3.
 
 Example:
   cloudrift scan --config=config/cloudrift-s3.yml --service=s3
@@ -112,10 +134,33 @@ Example:
   cloudrift scan --service=s3 --format=sarif --output=drift-report.sarif
   cloudrift scan --service=s3 --policy-dir=./my-policies --fail-on-violation
   cloudrift scan --service=iam --format=json
-  cloudrift scan --service=s3 --frameworks=hipaa,soc2`,
+  cloudrift scan --service=s3 --frameworks=hipaa,soc2
+  cloudrift scan --service=s3 --live-snapshot=s3-live.json
+  cloudrift scan --service=iam --timeout=2m
+  cloudrift scan --service=s3 --detect-unmanaged --exclude-unmanaged='name:cdk-*'
+  cloudrift scan --service=ec2 --format=remediation --output=remediation.tf
+  cloudrift scan --service=s3 --format=ndjson --output=drift.ndjson
+  cloudrift scan --service=s3 --format=openmetrics --output=/var/lib/node_exporter/textfile/cloudrift_s3.prom
+  cloudrift scan --service=s3 --format=html --output=report.html
+  cloudrift scan --service=s3 --format=junit --output=cloudrift-junit.xml
+  cloudrift scan --service=s3 --format=markdown --output=drift-comment.md
+  cloudrift scan --service=s3 --format=asff --output=findings.json
+  cloudrift scan --service=s3 --format=template --template=csv --output=drift.csv
+  cloudrift scan --service=s3 --output-format=sarif:drift.sarif --output-format=json:report.json
+  cloudrift scan --service=ec2 --tf-dir=./infra --apply-remediation`,
 	Run: func(cmd *cobra.Command, args []string) {
 		initIcons()
 
+		// NDJSON records on stdout are piped to other tools, so progress
+		// goes to stderr instead
+		ndjsonStdout := output.FormatType(strings.ToLower(outputFormat)) == output.FormatNDJSON && outputFile == ""
+		if ndjsonStdout {
+			color.Output = os.Stderr
+		}
+
+		ctx, cancel := newCommandContext(scanTimeout)
+		defer cancel()
+
 		// Parse and validate --frameworks flag
 		var selectedFrameworks []string
 		if frameworksFilter != "" {
@@ -156,62 +201,122 @@ Example:
 			color.Red("%s 'plan_path' not found in config", icons.Cross)
 			os.Exit(1)
 		}
-
-		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-		s.Color("cyan")
-
-		// 1. Loading AWS config
-		s.Suffix = " Loading AWS config..."
-		start := time.Now()
-		s.Start()
-		cfg, err := common.InitAWS(profile, region)
-		s.Stop()
-		if err != nil {
-			color.Red("%s Failed to load AWS config: %v", icons.Cross, err)
+		if applyRemediation && tfDir == "" {
+			color.Red("%s --apply-remediation requires --tf-dir", icons.Cross)
 			os.Exit(1)
 		}
-		color.Yellow("%s AWS config loaded in %s", icons.Check, time.Since(start).Round(time.Millisecond))
-
-		// 2. Validating credentials
-		s.Suffix = " Validating AWS credentials..."
-		start = time.Now()
-		s.Start()
-		err = common.ValidateCredentials(cfg)
-		s.Stop()
+		outputSpecs, err := output.ParseOutputSpecs(extraOutputs)
 		if err != nil {
-			color.Red("%s Invalid AWS credentials: %v", icons.Cross, err)
+			color.Red("%s Invalid --output-format: %v", icons.Cross, err)
 			os.Exit(1)
 		}
-		color.Yellow("%s Credentials valided in %s", icons.Check, time.Since(start).Round(time.Millisecond))
-
-		// 3. Fetching AWS identity
-		s.Suffix = " Fetching AWS identity..."
-		start = time.Now()
-		s.Start()
-		identity, err := common.GetCallerIdentity(cfg)
-		s.Stop()
-		if err != nil {
-			color.Red("%s Failed to retrieve AWS identity: %v", icons.Cross, err)
+		usesTemplate := output.FormatType(strings.ToLower(outputFormat)) == output.FormatTemplate
+		for _, spec := range outputSpecs {
+			if outputFile != "" && filepath.Clean(spec.Path) == filepath.Clean(outputFile) {
+				color.Red("%s --output-format %s:%s writes to the --output file", icons.Cross, spec.Format, spec.Path)
+				os.Exit(1)
+			}
+			if spec.Format == output.FormatTemplate {
+				usesTemplate = true
+			}
+		}
+		// Load the template before scanning so a bad template fails fast
+		var templateFormatter *output.TemplateFormatter
+		if usesTemplate {
+			if templatePath == "" {
+				color.Red("%s The template format requires --template (a file or one of: %s)", icons.Cross, strings.Join(output.BuiltinTemplates(), ", "))
+				os.Exit(1)
+			}
+			templateFormatter, err = output.LoadTemplate(templatePath)
+			if err != nil {
+				color.Red("%s Invalid template: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+		} else if templatePath != "" {
+			color.Red("%s --template requires --format=template or --output-format=template:<path>", icons.Cross)
 			os.Exit(1)
 		}
-		color.Green("%s Connected as: %s (%s) [%s] in %s", icons.Lock, *identity.Arn, *identity.Account, region, time.Since(start).Round(time.Millisecond))
+		var exclusions []detector.Exclusion
+		if detectUnmanaged {
+			exclusions, err = loadExclusions(excludeUnmanaged)
+			if err != nil {
+				color.Red("%s Invalid unmanaged resource exclusion: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+		}
 
-		// 4. Select the appropriate detector and printer, load plan
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		if ndjsonStdout {
+			s.Writer, s.WriterFile = os.Stderr, os.Stderr
+		}
+		s.Color("cyan")
+
+		var cfg sdkaws.Config
+		var accountID string
+		var snap *snapshot.Snapshot
+		if liveSnapshotPath != "" {
+			// Offline mode: account and region come from the snapshot
+			snap, err = snapshot.Load(liveSnapshotPath)
+			if err != nil {
+				color.Red("%s Failed to load live snapshot: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			accountID = snap.AccountID
+			region = snap.Region
+			color.Green("%s Replaying live state from %s (%s) [%s], recorded %s", icons.Lock, liveSnapshotPath, accountID, region, snap.CreatedAt)
+		} else {
+			// 1. Loading AWS config
+			s.Suffix = " Loading AWS config..."
+			start := time.Now()
+			s.Start()
+			cfg, err = common.InitAWS(ctx, profile, region)
+			s.Stop()
+			if err != nil {
+				color.Red("%s Failed to load AWS config: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			color.Yellow("%s AWS config loaded in %s", icons.Check, time.Since(start).Round(time.Millisecond))
+
+			// 2. Validating credentials
+			s.Suffix = " Validating AWS credentials..."
+			start = time.Now()
+			s.Start()
+			err = common.ValidateCredentials(ctx, cfg)
+			s.Stop()
+			if err != nil {
+				color.Red("%s Invalid AWS credentials: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			color.Yellow("%s Credentials valided in %s", icons.Check, time.Since(start).Round(time.Millisecond))
+
+			// 3. Fetching AWS identity
+			s.Suffix = " Fetching AWS identity..."
+			start = time.Now()
+			s.Start()
+			identity, err := common.GetCallerIdentity(ctx, cfg)
+			s.Stop()
+			if err != nil {
+				color.Red("%s Failed to retrieve AWS identity: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			accountID = *identity.Account
+			color.Green("%s Connected as: %s (%s) [%s] in %s", icons.Lock, *identity.Arn, accountID, region, time.Since(start).Round(time.Millisecond))
+		}
+
+		// 4. Select the appropriate detector and load the plan
 		var det DriftDetector
-		var printer detector.DriftResultPrinter
 		var serviceName string
 		var planResources interface{}
 		var liveResources interface{}
 		var planCount int
 
 		s.Suffix = " Loading Terraform plan..."
-		start = time.Now()
+		start := time.Now()
 		s.Start()
 
 		switch service {
 		case "s3":
 			det = detector.NewS3DriftDetector(cfg)
-			printer = detector.S3DriftResultPrinter{}
 			serviceName = "S3"
 			pr, err := common.LoadPlan(planPath)
 			if err != nil {
@@ -224,7 +329,6 @@ Example:
 
 		case "ec2":
 			det = detector.NewEC2DriftDetector(cfg)
-			printer = detector.EC2DriftResultPrinter{}
 			serviceName = "EC2"
 			pr, err := common.LoadEC2Plan(planPath)
 			if err != nil {
@@ -237,7 +341,6 @@ Example:
 
 		case "iam":
 			det = detector.NewIAMDriftDetector(cfg)
-			printer = detector.IAMDriftResultPrinter{}
 			serviceName = "IAM"
 			pr, err := common.LoadIAMPlan(planPath)
 			if err != nil {
@@ -257,18 +360,110 @@ Example:
 		s.Stop()
 		color.Yellow("%s Plan loaded from json in %s", icons.Doc, time.Since(start).Round(time.Millisecond))
 
-		// 5. Fetching live state
-		s.Suffix = fmt.Sprintf(" Fetching live %s state...", serviceName)
-		start = time.Now()
-		s.Start()
-		rawLive, err := det.FetchLiveState()
-		s.Stop()
-		if err != nil {
-			color.Red("%s Failed to fetch live state: %v", icons.Cross, err)
+		formatType := output.FormatType(strings.ToLower(outputFormat))
+		formatter, ok := output.Get(formatType)
+		switch formatType {
+		case output.FormatConsole:
+			// Colors only make sense on a terminal
+			formatter = &output.ConsoleFormatter{NoColor: outputFile != "", NoEmoji: noEmoji}
+		case output.FormatTemplate:
+			formatter, ok = templateFormatter, true
+		}
+		if !ok {
+			color.Red("%s Unsupported output format: %s (supported: console, json, ndjson, sarif, remediation, html, junit, markdown, asff, ocsf, openmetrics, template)", icons.Cross, outputFormat)
 			os.Exit(1)
 		}
-		liveResources = rawLive
-		color.Yellow("%s Live %s state fetched in %s", icons.Check, serviceName, time.Since(start).Round(time.Millisecond))
+
+		// Determine output writer
+		var writer *os.File = os.Stdout
+		if outputFile != "" {
+			writer, err = os.Create(outputFile)
+			if err != nil {
+				color.Red("%s Failed to create output file: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			defer writer.Close()
+		}
+
+		// NDJSON outputs are streamed record by record while the scan runs
+		var ndjsonWriters []io.Writer
+		if formatType == output.FormatNDJSON {
+			ndjsonWriters = append(ndjsonWriters, writer)
+		}
+		for _, spec := range outputSpecs {
+			if spec.Format != output.FormatNDJSON {
+				continue
+			}
+			f, err := os.Create(spec.Path)
+			if err != nil {
+				color.Red("%s Failed to create %s output: %v", icons.Cross, spec.Format, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			ndjsonWriters = append(ndjsonWriters, f)
+		}
+		var stream *output.NDJSONWriter
+		if len(ndjsonWriters) > 0 {
+			stream = output.NewNDJSONWriter(io.MultiWriter(ndjsonWriters...), serviceName, accountID)
+		}
+
+		// Point findings at the resource blocks that declare them
+		var tfIndex tfconfig.Index
+		if tfDir != "" {
+			tfIndex, err = tfconfig.Load(tfDir)
+			if err != nil {
+				color.Yellow("%s Could not map resources to %s: %v", icons.Warn, tfDir, err)
+			}
+		}
+
+		// Drifts and fetch errors are streamed per resource as the fetch goes
+		var streamer *driftStreamer
+		if stream != nil {
+			streamer = newDriftStreamer(stream, det, planResources, serviceName, tfIndex)
+		}
+
+		// 5. Fetching live state (or replaying it from a snapshot)
+		if snap != nil {
+			rawLive, err := snap.LiveState(service)
+			if err != nil {
+				color.Red("%s Failed to read live state from snapshot: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			liveResources = rawLive
+			color.Yellow("%s Live %s state loaded from snapshot", icons.Check, serviceName)
+		} else {
+			s.Suffix = fmt.Sprintf(" Fetching live %s state...", serviceName)
+			start = time.Now()
+			s.Start()
+			fetchCtx := ctx
+			if streamer != nil {
+				fetchCtx = aws.WithFetchTrace(ctx, streamer.trace())
+			}
+			rawLive, err := det.FetchLiveState(fetchCtx)
+			s.Stop()
+			if err != nil && ctx.Err() != nil {
+				// Cancelled mid-fetch: keep going so partial results are written
+				rawLive, _ = detector.UnfetchedLiveState(planResources, err)
+			} else if err != nil {
+				color.Red("%s Failed to fetch live state: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			liveResources = rawLive
+			if ctx.Err() == nil {
+				color.Yellow("%s Live %s state fetched in %s", icons.Check, serviceName, time.Since(start).Round(time.Millisecond))
+			}
+		}
+		incomplete := ctx.Err() != nil
+		if incomplete {
+			color.Yellow("%s Scan %s; writing partial results", icons.Warn, cancellationReason(ctx))
+		}
+		fetchErrors := detector.FetchErrors(liveResources)
+		if len(fetchErrors) > 0 {
+			color.Yellow("%s Could not fetch live state for %d resources; they will be reported as unknown", icons.Warn, len(fetchErrors))
+		}
+		if streamer != nil {
+			streamer.flushErrors(fetchErrors)
+		}
 
 		// 6. Detect drift
 		results, err := det.DetectDrift(planResources, liveResources)
@@ -278,9 +473,57 @@ Example:
 		}
 		color.Green("%s Drift detection completed", icons.Check)
 
+		// Unmanaged resources: live resources that no planned resource refers to
+		var unmanaged []detector.UnmanagedResource
+		if detectUnmanaged && incomplete {
+			color.Yellow("%s Unmanaged resource detection skipped: scan %s", icons.Warn, cancellationReason(ctx))
+		} else if detectUnmanaged {
+			unmanaged, err = detector.FindUnmanaged(planResources, liveResources, exclusions)
+			if err != nil {
+				color.Red("%s Unmanaged resource detection failed: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			color.Yellow("%s Found %d unmanaged %s resources", icons.Check, len(unmanaged), serviceName)
+		}
+
+		// Remediation: the configuration changes that would accept the live state
+		var patches []remediation.Patch
+		if !incomplete {
+			patches, err = remediation.Build(planResources, liveResources)
+			if err != nil {
+				color.Red("%s Remediation failed: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+		}
+		if applyRemediation {
+			applyPatches(patches, incomplete)
+			if len(patches) > 0 && !incomplete {
+				// Applied patches can move the resource blocks down
+				if index, err := tfconfig.Load(tfDir); err == nil {
+					tfIndex = index
+				}
+			}
+		}
+
+		drifts := convertDrifts(results, serviceName)
+		locateDrifts(drifts, tfIndex)
+
+		if stream != nil {
+			streamer.flushDrifts(drifts)
+			for _, u := range unmanaged {
+				stream.WriteUnmanaged(u)
+			}
+			for _, p := range patches {
+				stream.WriteRemediation(p)
+			}
+		}
+
 		// 7. Policy evaluation
 		var policyResult *policy.EvaluationResult
-		if !skipPolicies {
+		var policyRules []policy.PolicyInfo
+		if incomplete && !skipPolicies {
+			color.Yellow("%s Policy evaluation skipped: scan %s", icons.Warn, cancellationReason(ctx))
+		} else if !skipPolicies {
 			s.Suffix = " Evaluating policies..."
 			start = time.Now()
 			s.Start()
@@ -302,10 +545,17 @@ Example:
 				// Build policy inputs from plan resources
 				inputs := buildPolicyInputs(service, planResources, liveResources, results)
 
-				policyResult, err = engine.EvaluateAll(context.Background(), inputs)
-				if err != nil {
+				policyResult, err = engine.EvaluateAll(ctx, inputs)
+				if ctx.Err() != nil {
+					// Cancelled while evaluating: the scan is incomplete and
+					// any partial result is dropped.
+					incomplete = true
+					policyResult = nil
+					color.Yellow("%s Policy evaluation stopped: scan %s; writing partial results", icons.Warn, cancellationReason(ctx))
+				} else if err != nil {
 					color.Yellow("%s Policy evaluation failed: %v", icons.Warn, err)
 				} else {
+					policyRules = engine.Policies()
 					color.Yellow("%s Evaluated %d policies in %s", icons.Check, engine.PolicyCount(), time.Since(start).Round(time.Millisecond))
 					if policyResult.HasViolations() {
 						color.Red("%s Found %d policy violations", icons.Warn, len(policyResult.Violations))
@@ -324,29 +574,15 @@ Example:
 
 		scanDuration := time.Since(startScan)
 		color.Green("%s Scan completed in %s!", icons.Check, scanDuration.Round(time.Millisecond))
-		fmt.Println()
+		fmt.Fprintln(color.Output)
 
 		// 8. Format and output results
-		formatType := output.FormatType(strings.ToLower(outputFormat))
-		formatter, ok := output.Get(formatType)
-		if !ok {
-			color.Red("%s Unsupported output format: %s (supported: console, json, sarif)", icons.Cross, outputFormat)
-			os.Exit(1)
-		}
-
-		// Convert results to output.ScanResult
-		scanResult := convertToScanResult(results, serviceName, *identity.Account, region, planCount, scanDuration)
-
-		// Determine output writer
-		var writer *os.File = os.Stdout
-		if outputFile != "" {
-			writer, err = os.Create(outputFile)
-			if err != nil {
-				color.Red("%s Failed to create output file: %v", icons.Cross, err)
-				os.Exit(1)
-			}
-			defer writer.Close()
-		}
+		scanResult := convertToScanResult(drifts, serviceName, accountID, region, planCount, scanDuration)
+		scanResult.Errors = fetchErrors
+		scanResult.Incomplete = incomplete
+		scanResult.Unmanaged = unmanaged
+		scanResult.Remediation = patches
+		scanResult.Details = &output.ServiceDetails{Results: results, Plan: planResources, Live: liveResources}
 
 		// Build the filtered registry for compliance scoring (if --frameworks is set)
 		var filteredRegistry *policy.PolicyRegistry
@@ -354,7 +590,7 @@ Example:
 			filteredRegistry = getPolicyRegistry().FilterByFrameworks(selectedFrameworks)
 		}
 
-		// Attach policy results to scan output for non-console formats
+		// Attach policy results to the scan output
 		if policyResult != nil {
 			po := &output.PolicyOutput{
 				Passed: policyResult.Passed,
@@ -386,31 +622,76 @@ Example:
 					Frameworks:      w.Frameworks,
 				})
 			}
+			for _, r := range policyRules {
+				if len(selectedFrameworks) > 0 && !matchesAnyFramework(r.Frameworks, selectedFrameworks) {
+					continue
+				}
+				po.Rules = append(po.Rules, output.PolicyRuleOutput{
+					ID:          r.ID,
+					Name:        r.Name,
+					Severity:    r.Severity,
+					Remediation: r.Remediation,
+					Category:    r.Category,
+					Frameworks:  r.Frameworks,
+				})
+			}
 			compliance := computeCompliance(policyResult, filteredRegistry)
 			if len(selectedFrameworks) > 0 {
 				compliance.ActiveFrameworks = selectedFrameworks
 			}
 			po.ComplianceResult = compliance
+			locateFindings(po, tfIndex)
 			scanResult.PolicyResult = po
+
+			if stream != nil {
+				for _, v := range po.Violations {
+					stream.WriteViolation(v)
+				}
+				for _, w := range po.Warnings {
+					stream.WriteWarning(w)
+				}
+			}
 		}
 
-		// For non-console formats, suppress the colorized output
-		if formatType != output.FormatConsole {
+		// NDJSON outputs already hold every record; finish them with the totals
+		if stream != nil {
+			if err := stream.WriteSummary(scanResult); err != nil {
+				color.Red("%s Failed to write ndjson output: %v", icons.Cross, err)
+				os.Exit(1)
+			}
+		}
+		if formatType != output.FormatNDJSON {
 			if err := formatter.Format(writer, scanResult); err != nil {
 				color.Red("%s Failed to format output: %v", icons.Cross, err)
 				os.Exit(1)
 			}
-			if outputFile != "" {
-				color.Green("%s Output written to %s", icons.Doc, outputFile)
-			}
-		} else {
-			// Use the legacy printer for console output (for now)
-			printer.PrintDrift(results, planResources, liveResources)
+		}
+		if outputFile != "" {
+			color.Green("%s Output written to %s", icons.Doc, outputFile)
+		}
 
-			// Print policy violations if present
-			if policyResult != nil && (len(policyResult.Violations) > 0 || len(policyResult.Warnings) > 0) {
-				printPolicyResults(policyResult, filteredRegistry, selectedFrameworks)
+		// Write the additional outputs from the same scan result
+		for _, spec := range outputSpecs {
+			formatter, _ := output.Get(spec.Format)
+			switch spec.Format {
+			case output.FormatNDJSON:
+				color.Green("%s Output written to %s", icons.Doc, spec.Path)
+				continue
+			case output.FormatConsole:
+				formatter = &output.ConsoleFormatter{NoColor: true, NoEmoji: noEmoji}
+			case output.FormatTemplate:
+				formatter = templateFormatter
+			}
+			if err := writeOutputFile(spec.Path, formatter, scanResult); err != nil {
+				color.Red("%s Failed to write %s output: %v", icons.Cross, spec.Format, err)
+				os.Exit(1)
 			}
+			color.Green("%s Output written to %s", icons.Doc, spec.Path)
+		}
+
+		// Partial results have been written; signal that the scan did not finish
+		if incomplete {
+			os.Exit(exitIncomplete)
 		}
 
 		// Exit with error if --fail-on-violation is set and violations exist
@@ -431,21 +712,24 @@ func getPolicyRegistry() *policy.PolicyRegistry {
 	return policyRegistry
 }
 
+// matchesAnyFramework reports whether a policy maps to at least one of the
+// selected frameworks.
+func matchesAnyFramework(policyFrameworks, selected []string) bool {
+	for _, fw := range policyFrameworks {
+		for _, sel := range selected {
+			if fw == sel {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // filterByFrameworks returns a new EvaluationResult containing only violations
 // and warnings whose policies map to at least one of the selected frameworks.
 func filterByFrameworks(result *policy.EvaluationResult, frameworks []string) *policy.EvaluationResult {
-	fwSet := make(map[string]bool, len(frameworks))
-	for _, fw := range frameworks {
-		fwSet[fw] = true
-	}
-
 	matches := func(v policy.Violation) bool {
-		for _, fw := range v.Frameworks {
-			if fwSet[fw] {
-				return true
-			}
-		}
-		return false
+		return matchesAnyFramework(v.Frameworks, frameworks)
 	}
 
 	filtered := &policy.EvaluationResult{}
@@ -546,109 +830,129 @@ func computeCompliance(result *policy.EvaluationResult, reg *policy.PolicyRegist
 	}
 }
 
-// printPolicyResults outputs policy evaluation results to console.
-// reg is the filtered registry when --frameworks is set, or nil for the full registry.
-// activeFrameworks lists the selected frameworks (empty when no filter is active).
-func printPolicyResults(result *policy.EvaluationResult, reg *policy.PolicyRegistry, activeFrameworks []string) {
-	fmt.Println()
-	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	color.Cyan("              POLICY EVALUATION                   ")
-	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	if len(result.Violations) > 0 {
-		fmt.Println()
-		color.Red("%s VIOLATIONS (%d)", icons.Cross, len(result.Violations))
-		for _, v := range result.Violations {
-			fmt.Println()
-			color.Red("  [%s] %s", v.Severity, v.PolicyID)
-			fmt.Printf("  %s Resource: %s\n", icons.Pin, color.CyanString(v.ResourceAddress))
-			fmt.Printf("  %s %s\n", icons.Msg, v.Message)
-			if v.Remediation != "" {
-				fmt.Printf("  %s %s\n", icons.Gear, color.YellowString(v.Remediation))
-			}
-		}
-	}
-
-	if len(result.Warnings) > 0 {
-		fmt.Println()
-		color.Yellow("%s WARNINGS (%d)", icons.Warn, len(result.Warnings))
-		for _, w := range result.Warnings {
-			fmt.Println()
-			color.Yellow("  [%s] %s", w.Severity, w.PolicyID)
-			fmt.Printf("  %s Resource: %s\n", icons.Pin, color.CyanString(w.ResourceAddress))
-			fmt.Printf("  %s %s\n", icons.Msg, w.Message)
-			if w.Remediation != "" {
-				fmt.Printf("  %s %s\n", icons.Gear, color.YellowString(w.Remediation))
-			}
-		}
-	}
-
-	// Print compliance summary
-	compliance := computeCompliance(result, reg)
-	fmt.Println()
-	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	if len(activeFrameworks) > 0 {
-		color.Cyan("      COMPLIANCE SUMMARY (%s)", strings.ToUpper(strings.Join(activeFrameworks, ", ")))
-	} else {
-		color.Cyan("            COMPLIANCE SUMMARY                    ")
-	}
-	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println()
-
-	overallColor := color.GreenString
-	if compliance.OverallPercentage < 80 {
-		overallColor = color.RedString
-	} else if compliance.OverallPercentage < 100 {
-		overallColor = color.YellowString
-	}
-	fmt.Printf("  Overall: %s (%d/%d policies passing)\n",
-		overallColor("%.1f%%", compliance.OverallPercentage),
-		compliance.PassingPolicies, compliance.TotalPolicies)
-	fmt.Println()
-
-	// Categories (sorted for deterministic output)
-	fmt.Println("  Categories:")
-	catKeys := make([]string, 0, len(compliance.Categories))
-	for k := range compliance.Categories {
-		catKeys = append(catKeys, k)
-	}
-	sort.Strings(catKeys)
-	for _, cat := range catKeys {
-		score := compliance.Categories[cat]
-		pctStr := fmt.Sprintf("%.1f%%", score.Percentage)
-		if score.Failed > 0 {
-			pctStr = color.RedString(pctStr)
-		} else {
-			pctStr = color.GreenString(pctStr)
+// applyPatches rewrites the .tf files in --tf-dir with the remediation
+// patches and reports the outcome. Patches are not applied to an incomplete
+// scan, since resources that were not fetched cannot be reconciled.
+func applyPatches(patches []remediation.Patch, incomplete bool) {
+	if incomplete {
+		color.Yellow("%s Remediation not applied: scan did not complete", icons.Warn)
+		return
+	}
+	if len(patches) == 0 {
+		color.Green("%s No remediation needed; %s left unchanged", icons.Check, tfDir)
+		return
+	}
+	res, err := remediation.Apply(tfDir, patches)
+	if err != nil {
+		color.Red("%s Failed to apply remediation: %v", icons.Cross, err)
+		os.Exit(1)
+	}
+	for _, f := range res.Files {
+		color.Green("%s Updated %s", icons.Doc, f)
+	}
+	color.Green("%s Applied remediation to %d resources", icons.Check, len(res.Applied))
+	skipped := make([]string, 0, len(res.Skipped))
+	for addr := range res.Skipped {
+		skipped = append(skipped, addr)
+	}
+	sort.Strings(skipped)
+	for _, addr := range skipped {
+		color.Yellow("%s Not applied to %s: %s", icons.Warn, addr, res.Skipped[addr])
+	}
+}
+
+// writeOutputFile formats a scan result into a new file at path.
+func writeOutputFile(path string, formatter output.Formatter, result output.ScanResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := formatter.Format(f, result); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadExclusions combines the unmanaged_exclusions rules from the config
+// file with the rules given on the command line, and validates them.
+func loadExclusions(flags []string) ([]detector.Exclusion, error) {
+	var exclusions []detector.Exclusion
+	if err := viper.UnmarshalKey("unmanaged_exclusions", &exclusions); err != nil {
+		return nil, fmt.Errorf("unmanaged_exclusions: %w", err)
+	}
+	for i, e := range exclusions {
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("unmanaged_exclusions[%d]: %w", i, err)
+		}
+	}
+	for _, f := range flags {
+		e, err := detector.ParseExclusion(f)
+		if err != nil {
+			return nil, err
 		}
-		fmt.Printf("    %-12s %s (%d/%d)\n", cat, pctStr, score.Passed, score.Total)
+		exclusions = append(exclusions, e)
 	}
-	fmt.Println()
+	return exclusions, nil
+}
 
-	// Frameworks (sorted for deterministic output)
-	fmt.Println("  Frameworks:")
-	fwKeys := make([]string, 0, len(compliance.Frameworks))
-	for k := range compliance.Frameworks {
-		fwKeys = append(fwKeys, k)
+// addressType returns the resource type of a Terraform address, or "" if
+// the address is empty or malformed.
+func addressType(address string) string {
+	parts := strings.Split(tfconfig.StripInstanceKeys(address), ".")
+	if len(parts) < 2 {
+		return ""
 	}
-	sort.Strings(fwKeys)
-	for _, fw := range fwKeys {
-		score := compliance.Frameworks[fw]
-		pctStr := fmt.Sprintf("%.1f%%", score.Percentage)
-		if score.Failed > 0 {
-			pctStr = color.RedString(pctStr)
-		} else {
-			pctStr = color.GreenString(pctStr)
+	return parts[len(parts)-2]
+}
+
+// locateDrifts sets the configuration location of drift results from the
+// resource blocks in idx. A nil index leaves them unchanged.
+func locateDrifts(drifts []detector.DriftInfo, idx tfconfig.Index) {
+	for i, d := range drifts {
+		if loc, ok := idx.Lookup(d.ResourceAddress); ok {
+			drifts[i].Location = &loc
+		}
+	}
+}
+
+// locateFindings sets the configuration location of policy findings from
+// the resource blocks in idx. A nil index leaves them unchanged.
+func locateFindings(po *output.PolicyOutput, idx tfconfig.Index) {
+	for _, findings := range [][]output.PolicyViolationOutput{po.Violations, po.Warnings} {
+		for i, v := range findings {
+			if loc, ok := idx.Lookup(v.ResourceAddress); ok {
+				findings[i].Location = &loc
+			}
+		}
+	}
+}
+
+// convertToScanResult builds the output.ScanResult of a service scan from
+// its converted drift results.
+func convertToScanResult(drifts []detector.DriftInfo, service, accountID, region string, totalResources int, duration time.Duration) output.ScanResult {
+	driftCount := 0
+	for _, d := range drifts {
+		if d.HasDrift() {
+			driftCount++
 		}
-		fmt.Printf("    %-12s %s (%d/%d)\n", fw, pctStr, score.Passed, score.Total)
 	}
 
-	fmt.Println()
-	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	return output.ScanResult{
+		SchemaVersion:  output.SchemaVersion,
+		Service:        service,
+		AccountID:      accountID,
+		Region:         region,
+		TotalResources: totalResources,
+		DriftCount:     driftCount,
+		Drifts:         drifts,
+		ScanDuration:   duration.Milliseconds(),
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+	}
 }
 
-// convertToScanResult converts legacy DriftResult to the new output.ScanResult format.
-func convertToScanResult(results []detector.DriftResult, service, accountID, region string, totalResources int, duration time.Duration) output.ScanResult {
+// convertDrifts converts legacy DriftResult to the output DriftInfo format.
+func convertDrifts(results []detector.DriftResult, service string) []detector.DriftInfo {
 	drifts := make([]detector.DriftInfo, 0, len(results))
 
 	// Determine the default resource type based on service
@@ -662,12 +966,25 @@ func convertToScanResult(results []detector.DriftResult, service, accountID, reg
 
 	for _, r := range results {
 		resourceType := defaultResourceType
+		if r.ResourceType != "" {
+			resourceType = r.ResourceType
+		}
+		if t := addressType(r.TerraformAddress); t != "" {
+			resourceType = t
+		}
+
+		resourceID := r.BucketName
+		if r.ResourceID != "" {
+			resourceID = r.ResourceID
+		}
 
 		info := detector.DriftInfo{
-			ResourceID:      r.BucketName,
+			ResourceID:      resourceID,
 			ResourceType:    resourceType,
 			ResourceName:    r.BucketName,
+			ResourceAddress: r.TerraformAddress,
 			Missing:         r.Missing,
+			Unknown:         r.Unknown,
 			Diffs:           make(map[string][2]interface{}),
 			ExtraAttributes: make(map[string]interface{}),
 			Severity:        "warning",
@@ -676,6 +993,9 @@ func convertToScanResult(results []detector.DriftResult, service, accountID, reg
 		if r.AclDiff {
 			info.Diffs["attributes"] = [2]interface{}{"<planned>", "<actual>"}
 		}
+		for k, v := range r.Diffs {
+			info.Diffs[k] = v
+		}
 		if r.VersioningDiff {
 			info.Diffs["versioning_enabled"] = [2]interface{}{"<planned>", "<actual>"}
 		}
@@ -701,36 +1021,26 @@ func convertToScanResult(results []detector.DriftResult, service, accountID, reg
 		if r.Missing {
 			info.Severity = "critical"
 		}
-
-		drifts = append(drifts, info)
-	}
-
-	driftCount := 0
-	for _, d := range drifts {
-		if d.HasDrift() {
-			driftCount++
+		if r.Unknown {
+			info.Severity = "info"
 		}
-	}
 
-	return output.ScanResult{
-		Service:        service,
-		AccountID:      accountID,
-		Region:         region,
-		TotalResources: totalResources,
-		DriftCount:     driftCount,
-		Drifts:         drifts,
-		ScanDuration:   duration.Milliseconds(),
-		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		drifts = append(drifts, info)
 	}
+	return drifts
 }
 
 // buildPolicyInputs creates policy inputs from scan resources.
 func buildPolicyInputs(service string, planResources, liveResources interface{}, results []detector.DriftResult) []*policy.PolicyInput {
 	var inputs []*policy.PolicyInput
 
-	// Build a map of drift results by resource name for quick lookup
+	// Build a map of drift results by resource name for quick lookup.
+	// Unknown results are skipped: their drift could not be determined.
 	driftMap := make(map[string]detector.DriftResult)
 	for _, r := range results {
+		if r.Unknown {
+			continue
+		}
 		driftMap[r.BucketName] = r
 	}
 
@@ -745,7 +1055,13 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 					"tags":                 b.Tags,
 					"versioning_enabled":   b.VersioningEnabled,
 					"encryption_algorithm": b.EncryptionAlgorithm,
+					"kms_key_id":           b.KMSKeyID,
+					"bucket_key_enabled":   b.BucketKeyEnabled,
 					"logging_enabled":      b.LoggingEnabled,
+					"policy":               b.Policy,
+					"object_ownership":     b.ObjectOwnership,
+					"object_lock_enabled":  b.ObjectLock.Enabled,
+					"replication_enabled":  len(b.Replication.Rules) > 0,
 					"public_access_block": map[string]interface{}{
 						"block_public_acls":       b.PublicAccessBlock.BlockPublicAcls,
 						"block_public_policy":     b.PublicAccessBlock.BlockPublicPolicy,
@@ -782,8 +1098,17 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 					"root_block_device": map[string]interface{}{
 						"volume_type": inst.RootBlockDevice.VolumeType,
 						"volume_size": inst.RootBlockDevice.VolumeSize,
-						"encrypted":   inst.RootBlockDevice.Encrypted,
+						"encrypted":   inst.RootBlockDevice.Encrypted != nil && *inst.RootBlockDevice.Encrypted,
+					},
+					"metadata_options": map[string]interface{}{
+						"http_tokens":                 inst.MetadataOptions.HTTPTokens,
+						"http_endpoint":               inst.MetadataOptions.HTTPEndpoint,
+						"http_put_response_hop_limit": inst.MetadataOptions.HTTPPutResponseHopLimit,
+						"instance_metadata_tags":      inst.MetadataOptions.InstanceMetadataTags,
 					},
+					"user_data":               inst.UserDataHash,
+					"disable_api_termination": inst.DisableAPITermination,
+					"disable_api_stop":        inst.DisableAPIStop,
 				}
 
 				// Add drift info if present
@@ -810,6 +1135,9 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 					"description":          role.Description,
 					"path":                 role.Path,
 					"tags":                 role.Tags,
+					"permissions_boundary": role.PermissionsBoundary,
+					"managed_policy_arns":  role.AttachedPolicies,
+					"inline_policies":      role.InlinePolicies,
 				}
 				if dr, ok := driftMap[role.RoleName]; ok {
 					input.Resource.Drift = &policy.DriftInput{
@@ -824,9 +1152,12 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 			for _, user := range iamPlan.Users {
 				input := policy.NewPolicyInput("aws_iam_user", user.TerraformAddress)
 				input.Resource.Planned = map[string]interface{}{
-					"name": user.UserName,
-					"path": user.Path,
-					"tags": user.Tags,
+					"name":                 user.UserName,
+					"path":                 user.Path,
+					"tags":                 user.Tags,
+					"permissions_boundary": user.PermissionsBoundary,
+					"managed_policy_arns":  user.AttachedPolicies,
+					"inline_policies":      user.InlinePolicies,
 				}
 				if dr, ok := driftMap[user.UserName]; ok {
 					input.Resource.Drift = &policy.DriftInput{
@@ -860,8 +1191,10 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 			for _, group := range iamPlan.Groups {
 				input := policy.NewPolicyInput("aws_iam_group", group.TerraformAddress)
 				input.Resource.Planned = map[string]interface{}{
-					"name": group.GroupName,
-					"path": group.Path,
+					"name":                group.GroupName,
+					"path":                group.Path,
+					"managed_policy_arns": group.AttachedPolicies,
+					"inline_policies":     group.InlinePolicies,
 				}
 				if dr, ok := driftMap[group.GroupName]; ok {
 					input.Resource.Drift = &policy.DriftInput{
@@ -871,6 +1204,24 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 				}
 				inputs = append(inputs, input)
 			}
+
+			// IAM Instance Profiles
+			for _, profile := range iamPlan.InstanceProfiles {
+				input := policy.NewPolicyInput("aws_iam_instance_profile", profile.TerraformAddress)
+				input.Resource.Planned = map[string]interface{}{
+					"name":  profile.InstanceProfileName,
+					"path":  profile.Path,
+					"roles": profile.Roles,
+					"tags":  profile.Tags,
+				}
+				if dr, ok := driftMap[profile.InstanceProfileName]; ok {
+					input.Resource.Drift = &policy.DriftInput{
+						HasDrift: true,
+						Missing:  dr.Missing,
+					}
+				}
+				inputs = append(inputs, input)
+			}
 		}
 	}
 
@@ -880,12 +1231,20 @@ func buildPolicyInputs(service string, planResources, liveResources interface{},
 func init() {
 	scanCmd.Flags().StringVarP(&configPath, "config", "c", "cloudrift-s3.yml", "Path to Cloudrift config file")
 	scanCmd.Flags().StringVarP(&service, "service", "s", "s3", "AWS service to scan (e.g., s3)")
-	scanCmd.Flags().StringVarP(&outputFormat, "format", "f", "console", "Output format: console, json, sarif")
+	scanCmd.Flags().StringVarP(&outputFormat, "format", "f", "console", "Output format: console, json, ndjson, sarif, remediation, html, junit, markdown, asff, ocsf, openmetrics, template")
 	scanCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write output to file instead of stdout")
+	scanCmd.Flags().StringVar(&templatePath, "template", "", "Go template file, or built-in template (csv, table), for --format=template")
+	scanCmd.Flags().StringArrayVar(&extraOutputs, "output-format", nil, "Also write <format>:<path> from the same scan, e.g. sarif:drift.sarif (repeatable)")
 	scanCmd.Flags().StringVarP(&policyDir, "policy-dir", "p", "", "Directory containing custom OPA policies")
 	scanCmd.Flags().BoolVar(&failOnViolation, "fail-on-violation", false, "Exit with non-zero code if policy violations found")
 	scanCmd.Flags().BoolVar(&skipPolicies, "skip-policies", false, "Skip policy evaluation")
 	scanCmd.Flags().BoolVar(&noEmoji, "no-emoji", false, "Use ASCII characters instead of emojis")
 	scanCmd.Flags().StringVar(&frameworksFilter, "frameworks", "", "Comma-separated compliance frameworks to evaluate (e.g., hipaa,soc2,gdpr)")
+	scanCmd.Flags().StringVar(&liveSnapshotPath, "live-snapshot", "", "Replay live state from a snapshot file instead of querying AWS")
+	scanCmd.Flags().DurationVar(&scanTimeout, "timeout", 0, "Abort the scan after this duration and write partial results (e.g., 5m; 0 = no limit)")
+	scanCmd.Flags().BoolVar(&detectUnmanaged, "detect-unmanaged", false, "Report live resources that are not in the Terraform plan")
+	scanCmd.Flags().StringArrayVar(&excludeUnmanaged, "exclude-unmanaged", nil, "Exclude unmanaged resources by name:<glob> or tag:<key>[=<glob>] (repeatable)")
+	scanCmd.Flags().StringVar(&tfDir, "tf-dir", "", "Root module directory of the Terraform configuration (locates resources in SARIF/JSON output)")
+	scanCmd.Flags().BoolVar(&applyRemediation, "apply-remediation", false, "Rewrite the .tf files in --tf-dir so drifted arguments match AWS")
 	rootCmd.AddCommand(scanCmd)
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+)
+
+// Command-line flags for the schema command.
+var (
+	schemaOutput   string // File to write the schema to (stdout if empty)
+	schemaValidate string // JSON output file to validate against the schema
+)
+
+// schemaCmd implements the "cloudrift schema" subcommand.
+//
+// It prints the JSON Schema of the JSON output embedded in the binary, or
+// validates a JSON output file against it.
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the JSON output",
+	Long: `Schema prints the JSON Schema (draft-07) that describes the output of
+"cloudrift scan --format=json". Every JSON result carries a schema_version
+field; the schema printed is the one for the version of this binary.
+
+With --validate, the given JSON output file is checked against the schema
+instead, and every violation is listed.
+
+Flags:
+  --output, -o   Write the schema to a file instead of stdout
+  --validate     Validate a JSON output file against the schema
+
+Example:
+  cloudrift schema > cloudrift.schema.json
+  cloudrift scan --service=s3 --format=json --output=drift.json
+  cloudrift schema --validate=drift.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initIcons()
+
+		if schemaValidate != "" {
+			data, err := os.ReadFile(schemaValidate)
+			if err != nil {
+				color.Red("%s Failed to read %s: %v", icons.Cross, schemaValidate, err)
+				os.Exit(1)
+			}
+			if err := output.ValidateJSON(data); err != nil {
+				color.Red("%s %s %v", icons.Cross, schemaValidate, err)
+				os.Exit(1)
+			}
+			color.Green("%s %s matches schema %s", icons.Check, schemaValidate, output.SchemaVersion)
+			return
+		}
+
+		if schemaOutput == "" {
+			os.Stdout.Write(output.Schema())
+			return
+		}
+		if err := os.WriteFile(schemaOutput, output.Schema(), 0o644); err != nil {
+			color.Red("%s Failed to write schema: %v", icons.Cross, err)
+			os.Exit(1)
+		}
+		color.Green("%s Schema written to %s", icons.Check, schemaOutput)
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+	schemaCmd.Flags().StringVar(&schemaValidate, "validate", "", "Validate a JSON output file against the schema")
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/inayathulla/cloudrift/internal/common"
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/snapshot"
+)
+
+// Command-line flags for the snapshot command.
+var (
+	snapshotConfigPath string // Path to cloudrift config file
+	snapshotServices   string // Comma-separated services to record
+	snapshotOutput     string // Snapshot file to write
+)
+
+// supportedServices lists the services that can be scanned or recorded.
+var supportedServices = []string{"s3", "ec2", "iam"}
+
+// snapshotCmd implements the "cloudrift snapshot" subcommand.
+//
+// It fetches live state for the selected services and writes it to a
+// versioned JSON file that "cloudrift scan --live-snapshot" can replay
+// later without AWS credentials.
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record live AWS state to a file for offline scans",
+	Long: `Snapshot fetches the live state of the selected AWS services and writes
+it to a versioned JSON file. The file can be replayed later with
+"cloudrift scan --live-snapshot=<file>" to run drift detection and policy
+evaluation without AWS credentials.
+
+Flags:
+  --config, -c    Path to cloudrift config file (AWS profile and region)
+  --service, -s   Comma-separated services to record (supports: s3, ec2, iam, all)
+  --output, -o    Snapshot file to write (required)
+
+Example:
+  cloudrift snapshot --config=config/cloudrift-s3.yml --service=s3 --output=s3-live.json
+  cloudrift snapshot --service=all --output=live.json
+  cloudrift scan --service=s3 --live-snapshot=s3-live.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initIcons()
+
+		ctx, cancel := newCommandContext(0)
+		defer cancel()
+
+		services, err := parseServiceList(snapshotServices)
+		if err != nil {
+			color.Red("%s %v", icons.Cross, err)
+			os.Exit(1)
+		}
+
+		profile, region, _, err := common.LoadAppConfig(snapshotConfigPath)
+		if err != nil {
+			color.Red("%s Failed to load config: %v", icons.Cross, err)
+			os.Exit(1)
+		}
+
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		s.Color("cyan")
+
+		s.Suffix = " Loading AWS config..."
+		s.Start()
+		cfg, err := common.InitAWS(ctx, profile, region)
+		s.Stop()
+		if err != nil {
+			color.Red("%s Failed to load AWS config: %v", icons.Cross, err)
+			os.Exit(1)
+		}
+
+		s.Suffix = " Fetching AWS identity..."
+		s.Start()
+		identity, err := common.GetCallerIdentity(ctx, cfg)
+		s.Stop()
+		if err != nil {
+			color.Red("%s Failed to retrieve AWS identity: %v", icons.Cross, err)
+			os.Exit(1)
+		}
+		color.Green("%s Connected as: %s (%s) [%s]", icons.Lock, *identity.Arn, *identity.Account, region)
+
+		snap := snapshot.New(*identity.Account, region)
+		for _, svc := range services {
+			det, err := newDriftDetector(svc, cfg)
+			if err != nil {
+				color.Red("%s %v", icons.Cross, err)
+				os.Exit(1)
+			}
+
+			s.Suffix = fmt.Sprintf(" Fetching live %s state...", strings.ToUpper(svc))
+			start := time.Now()
+			s.Start()
+			live, err := det.FetchLiveState(ctx)
+			s.Stop()
+			if ctx.Err() != nil {
+				// A partially fetched snapshot would replay as false unknowns
+				color.Red("%s Snapshot %s; no file written", icons.Cross, cancellationReason(ctx))
+				os.Exit(1)
+			}
+			if err != nil {
+				color.Red("%s Failed to fetch live %s state: %v", icons.Cross, strings.ToUpper(svc), err)
+				os.Exit(1)
+			}
+			if err := snap.Add(svc, live); err != nil {
+				color.Red("%s %v", icons.Cross, err)
+				os.Exit(1)
+			}
+			color.Yellow("%s Live %s state fetched in %s", icons.Check, strings.ToUpper(svc), time.Since(start).Round(time.Millisecond))
+		}
+
+		if err := snapshot.Save(snapshotOutput, snap); err != nil {
+			color.Red("%s %v", icons.Cross, err)
+			os.Exit(1)
+		}
+		color.Green("%s Snapshot written to %s", icons.Doc, snapshotOutput)
+	},
+}
+
+// newDriftDetector returns the drift detector for a service.
+func newDriftDetector(svc string, cfg sdkaws.Config) (DriftDetector, error) {
+	switch svc {
+	case "s3":
+		return detector.NewS3DriftDetector(cfg), nil
+	case "ec2":
+		return detector.NewEC2DriftDetector(cfg), nil
+	case "iam":
+		return detector.NewIAMDriftDetector(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported service: %s (supported: %s)", svc, strings.Join(supportedServices, ", "))
+	}
+}
+
+// parseServiceList splits a comma-separated --service value.
+// The special value "all" expands to every supported service.
+func parseServiceList(value string) ([]string, error) {
+	var services []string
+	seen := make(map[string]bool)
+	for _, svc := range strings.Split(value, ",") {
+		svc = strings.TrimSpace(strings.ToLower(svc))
+		if svc == "" {
+			continue
+		}
+		if svc == "all" {
+			return supportedServices, nil
+		}
+		if _, err := newDriftDetector(svc, sdkaws.Config{}); err != nil {
+			return nil, err
+		}
+		if !seen[svc] {
+			seen[svc] = true
+			services = append(services, svc)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services selected (supported: %s)", strings.Join(supportedServices, ", "))
+	}
+	return services, nil
+}
+
+func init() {
+	snapshotCmd.Flags().StringVarP(&snapshotConfigPath, "config", "c", "cloudrift-s3.yml", "Path to Cloudrift config file")
+	snapshotCmd.Flags().StringVarP(&snapshotServices, "service", "s", "s3", "Comma-separated services to record (s3, ec2, iam, all)")
+	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "Snapshot file to write")
+	snapshotCmd.MarkFlagRequired("output")
+	rootCmd.AddCommand(snapshotCmd)
+}
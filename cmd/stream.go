@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/inayathulla/cloudrift/internal/aws"
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
+)
+
+// driftStreamer writes the NDJSON records of a scan resource by resource
+// while the live state is being fetched: a fetch error as soon as a fetch
+// fails, and a drift as soon as a drifted resource has been fetched.
+//
+// Records that depend on the whole account, such as missing or unknown
+// resources, are written afterwards by flushErrors and flushDrifts, which
+// skip what was already streamed. Fetchers report from several goroutines,
+// so the streamer is safe for concurrent use.
+type driftStreamer struct {
+	stream  *output.NDJSONWriter
+	det     DriftDetector
+	plan    interface{}
+	service string
+	tfIndex tfconfig.Index
+
+	mu      sync.Mutex
+	errors  map[models.FetchError]bool // fetch errors already written
+	drifted map[string]bool            // ResourceKey of drifts already written
+}
+
+// newDriftStreamer creates a streamer writing to stream.
+//
+// Parameters:
+//   - stream: destination of the records
+//   - det: detector comparing each fetched resource with the plan
+//   - plan: the planned resources of the scanned service
+//   - service: the service name used for converted drifts (e.g., "S3")
+//   - tfIndex: resource locations for drift records, may be nil
+//
+// Returns:
+//   - *driftStreamer: streamer ready to be attached to a fetch with trace
+func newDriftStreamer(stream *output.NDJSONWriter, det DriftDetector, plan interface{}, service string, tfIndex tfconfig.Index) *driftStreamer {
+	return &driftStreamer{
+		stream:  stream,
+		det:     det,
+		plan:    plan,
+		service: service,
+		tfIndex: tfIndex,
+		errors:  make(map[models.FetchError]bool),
+		drifted: make(map[string]bool),
+	}
+}
+
+// trace returns the fetch hooks that feed the streamer.
+func (s *driftStreamer) trace() *aws.FetchTrace {
+	return &aws.FetchTrace{Fetched: s.fetched, Failed: s.failed}
+}
+
+// failed writes the record of a fetch error.
+func (s *driftStreamer) failed(e models.FetchError) {
+	s.mu.Lock()
+	s.errors[e] = true
+	s.mu.Unlock()
+	s.stream.WriteError(e)
+}
+
+// fetched compares a fetched resource with its planned counterpart and
+// writes its drift record if it drifted.
+func (s *driftStreamer) fetched(resource interface{}) {
+	plan, live, ok := detector.ResourceScope(s.plan, resource)
+	if !ok {
+		return
+	}
+	results, err := s.det.DetectDrift(plan, live)
+	if err != nil || len(results) == 0 {
+		return
+	}
+	drifts := convertDrifts(results, s.service)
+	locateDrifts(drifts, s.tfIndex)
+
+	s.mu.Lock()
+	for _, d := range drifts {
+		s.drifted[models.ResourceKey(d.ResourceType, d.ResourceName)] = true
+	}
+	s.mu.Unlock()
+	for _, d := range drifts {
+		s.stream.WriteDrift(d)
+	}
+}
+
+// flushErrors writes the fetch errors that were not streamed, such as those
+// of a cancelled fetch or a replayed snapshot.
+func (s *driftStreamer) flushErrors(errs []models.FetchError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range errs {
+		if !s.errors[e] {
+			s.errors[e] = true
+			s.stream.WriteError(e)
+		}
+	}
+}
+
+// flushDrifts writes the drifts that were not streamed, such as missing and
+// unknown resources. A resource streamed during the fetch is not written
+// again.
+func (s *driftStreamer) flushDrifts(drifts []detector.DriftInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range drifts {
+		key := models.ResourceKey(d.ResourceType, d.ResourceName)
+		if !s.drifted[key] {
+			s.drifted[key] = true
+			s.stream.WriteDrift(d)
+		}
+	}
+}
@@ -14,38 +14,49 @@ import (
 	"time"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	v2config "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/fatih/color"
 )
 
 const (
 	// maxRetries is the number of times to retry loading AWS config on failure.
 	maxRetries = 3
+
+	// requestTimeout bounds each individual HTTP request made by the SDK, so a
+	// hung connection fails that call instead of stalling the whole scan.
+	requestTimeout = 30 * time.Second
 )
 
 // LoadAWSConfig initializes and returns an AWS SDK configuration.
 //
 // The function loads credentials using the standard AWS credential chain,
 // optionally overriding the profile and region. It implements retry logic
-// with exponential backoff for transient failures.
+// with exponential backoff for transient failures. Every SDK request made with
+// the returned config is bounded by a per-request timeout. Progress messages
+// are written to color.Output, which commands redirect to stderr when stdout
+// carries machine-readable output.
 //
 // Parameters:
+//   - ctx: context for cancellation; retries stop as soon as it is done
 //   - profile: AWS credentials profile name (empty string uses default)
 //   - region: AWS region (empty string uses default from config/environment)
 //
 // Returns:
 //   - aws.Config: configured AWS SDK client configuration
 //   - error: if configuration cannot be loaded after retries
-func LoadAWSConfig(profile, region string) (sdkaws.Config, error) {
-	ctx := context.Background()
-	var opts []func(*v2config.LoadOptions) error
+func LoadAWSConfig(ctx context.Context, profile, region string) (sdkaws.Config, error) {
+	opts := []func(*v2config.LoadOptions) error{
+		v2config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(requestTimeout)),
+	}
 	if profile != "" {
 		opts = append(opts, v2config.WithSharedConfigProfile(profile))
 	}
 	if region != "" {
 		opts = append(opts, v2config.WithRegion(region))
 	}
-	fmt.Printf("🔧 AWS Profile=%s Region=%s\n", profile, region)
+	fmt.Fprintf(color.Output, "🔧 AWS Profile=%s Region=%s\n", profile, region)
 
 	var cfg sdkaws.Config
 	var err error
@@ -54,8 +65,12 @@ func LoadAWSConfig(profile, region string) (sdkaws.Config, error) {
 		if err == nil {
 			return cfg, nil
 		}
-		fmt.Printf("⚠️ Retry %d: %v\n", i, err)
-		time.Sleep(time.Duration(i) * time.Second)
+		fmt.Fprintf(color.Output, "⚠️ Retry %d: %v\n", i, err)
+		select {
+		case <-ctx.Done():
+			return sdkaws.Config{}, fmt.Errorf("could not load AWS config: %w", ctx.Err())
+		case <-time.After(time.Duration(i) * time.Second):
+		}
 	}
 	return sdkaws.Config{}, fmt.Errorf("could not load AWS config: %w", err)
 }
@@ -67,13 +82,14 @@ func LoadAWSConfig(profile, region string) (sdkaws.Config, error) {
 // that validates credentials without making resource-specific API calls.
 //
 // Parameters:
+//   - ctx: context for cancellation
 //   - cfg: AWS SDK configuration to validate
 //
 // Returns:
 //   - error: if credentials are invalid or the API call fails
-func ValidateAWSCredentials(cfg sdkaws.Config) error {
+func ValidateAWSCredentials(ctx context.Context, cfg sdkaws.Config) error {
 	client := sts.NewFromConfig(cfg)
-	_, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+	_, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		return fmt.Errorf("invalid AWS credentials: %w", err)
 	}
@@ -2,33 +2,49 @@ package aws
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"sort"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
+// volumeBatchSize is the number of volume IDs requested per DescribeVolumes call.
+const volumeBatchSize = 200
+
 // FetchEC2Instances retrieves all EC2 instances and their configurations from AWS.
 //
 // This function lists all instances in the account/region and fetches detailed metadata
-// including instance type, security groups, tags, and block device mappings.
+// including instance type, security groups, tags, metadata options, network interfaces,
+// attached EBS volumes (via DescribeVolumes) and the userData, disableApiTermination
+// and disableApiStop attributes (via DescribeInstanceAttribute).
 //
-// Terminated instances are excluded from the results.
+// Terminated instances are excluded from the results. Instances whose volumes or
+// attributes cannot be fetched are kept and recorded in the returned state's Errors.
+// Each instance is reported to the FetchTrace of ctx, if any, as soon as its
+// attributes have been fetched.
 //
 // Parameters:
+//   - ctx: context for cancellation
 //   - cfg: AWS SDK configuration for API calls
 //
 // Returns:
-//   - []models.EC2Instance: slice of instance configurations
+//   - *models.EC2LiveState: instance configurations and per-instance fetch errors
 //   - error: if the DescribeInstances call fails
-func FetchEC2Instances(cfg sdkaws.Config) ([]models.EC2Instance, error) {
-	ctx := context.Background()
-	client := ec2.NewFromConfig(cfg)
+func FetchEC2Instances(ctx context.Context, cfg sdkaws.Config) (*models.EC2LiveState, error) {
+	return FetchEC2InstancesWithClient(ctx, ec2.NewFromConfig(cfg))
+}
 
-	var instances []models.EC2Instance
+// FetchEC2InstancesWithClient retrieves all EC2 instances using the provided client.
+//
+// This is the injectable form of FetchEC2Instances; tests pass a fake EC2API.
+func FetchEC2InstancesWithClient(ctx context.Context, client EC2API) (*models.EC2LiveState, error) {
+	state := &models.EC2LiveState{}
 	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
 
 	for paginator.HasMorePages() {
@@ -45,15 +61,189 @@ func FetchEC2Instances(cfg sdkaws.Config) ([]models.EC2Instance, error) {
 				}
 
 				instance := convertEC2Instance(inst)
-				instances = append(instances, instance)
+				state.Instances = append(state.Instances, instance)
+			}
+		}
+	}
+
+	volumeErrs := fetchVolumeDetails(ctx, client, state.Instances)
+
+	trace := contextFetchTrace(ctx)
+	for i := range state.Instances {
+		inst := &state.Instances[i]
+		var errs []models.FetchError
+		if e, ok := volumeErrs[i]; ok {
+			errs = append(errs, e)
+		}
+		if err := fetchInstanceAttributes(ctx, client, inst); err != nil {
+			errs = append(errs, newFetchError("ec2", "aws_instance", inst.InstanceID, "", err))
+		}
+		state.Errors = append(state.Errors, errs...)
+		trace.report(*inst, errs)
+	}
+
+	return state, nil
+}
+
+// fetchVolumeDetails fills in type, size, encryption, IOPS and throughput for
+// every attached EBS volume using batched DescribeVolumes calls.
+//
+// DescribeInstances only reports volume IDs and DeleteOnTermination, so the
+// remaining attributes require this second lookup. If a batch fails, every
+// instance with a volume in that batch is recorded as a fetch error, except
+// when a volume was not found: the batch is then retried volume by volume,
+// so only the instances of the missing volumes are recorded.
+//
+// Parameters:
+//   - ctx: context for cancellation
+//   - client: EC2 API client
+//   - instances: instances whose block devices are updated in place
+//
+// Returns:
+//   - map[int]models.FetchError: the error of each instance, by index, whose
+//     volumes could not be described
+func fetchVolumeDetails(ctx context.Context, client EC2API, instances []models.EC2Instance) map[int]models.FetchError {
+	devices := make(map[string][]*models.BlockDevice)
+	owners := make(map[string][]int)
+	var ids []string
+	track := func(idx int, dev *models.BlockDevice) {
+		if dev.VolumeID == "" {
+			return
+		}
+		if _, ok := devices[dev.VolumeID]; !ok {
+			ids = append(ids, dev.VolumeID)
+		}
+		devices[dev.VolumeID] = append(devices[dev.VolumeID], dev)
+		owners[dev.VolumeID] = append(owners[dev.VolumeID], idx)
+	}
+	for i := range instances {
+		track(i, &instances[i].RootBlockDevice)
+		for j := range instances[i].EBSBlockDevices {
+			track(i, &instances[i].EBSBlockDevices[j])
+		}
+	}
+
+	errs := make(map[int]models.FetchError)
+	for start := 0; start < len(ids); start += volumeBatchSize {
+		batch := ids[start:min(start+volumeBatchSize, len(ids))]
+
+		fail := func(id string, err error) {
+			for _, idx := range owners[id] {
+				if _, ok := errs[idx]; !ok {
+					errs[idx] = newFetchError("ec2", "aws_instance", instances[idx].InstanceID, "", err)
+				}
+			}
+		}
+
+		volumes, err := describeVolumes(ctx, client, batch)
+		if isErrorCode(err, "InvalidVolume.NotFound") {
+			// A volume detached and deleted since DescribeInstances fails
+			// the whole batch; describe each volume so only its instance
+			// is affected
+			volumes = nil
+			for _, id := range batch {
+				vols, err := describeVolumes(ctx, client, []string{id})
+				if err != nil {
+					fail(id, err)
+					continue
+				}
+				volumes = append(volumes, vols...)
+			}
+		} else if err != nil {
+			for _, id := range batch {
+				fail(id, err)
+			}
+			continue
+		}
+
+		for _, vol := range volumes {
+			for _, dev := range devices[safeString(vol.VolumeId)] {
+				dev.VolumeType = string(vol.VolumeType)
+				dev.VolumeSize = int(safeInt32(vol.Size))
+				dev.Encrypted = sdkaws.Bool(safeBool(vol.Encrypted))
+				dev.IOPS = int(safeInt32(vol.Iops))
+				dev.Throughput = int(safeInt32(vol.Throughput))
 			}
 		}
 	}
+	return errs
+}
+
+// describeVolumes returns all volumes with the given IDs, following pagination.
+func describeVolumes(ctx context.Context, client EC2API, ids []string) ([]types.Volume, error) {
+	var volumes []types.Volume
+	paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{VolumeIds: ids})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, wrapCall("DescribeVolumes", err)
+		}
+		volumes = append(volumes, page.Volumes...)
+	}
+	return volumes, nil
+}
+
+// fetchInstanceAttributes retrieves the attributes DescribeInstances does not
+// return: the user data (stored as a hash), termination protection and stop
+// protection.
+//
+// The three DescribeInstanceAttribute calls are made in parallel. Any error is
+// tagged with the failing operation so the caller can report it.
+func fetchInstanceAttributes(ctx context.Context, client EC2API, inst *models.EC2Instance) error {
+	var (
+		userData    *ec2.DescribeInstanceAttributeOutput
+		termination *ec2.DescribeInstanceAttributeOutput
+		stop        *ec2.DescribeInstanceAttributeOutput
+	)
+
+	describe := func(ctx context.Context, attr types.InstanceAttributeName) (*ec2.DescribeInstanceAttributeOutput, error) {
+		out, err := client.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+			InstanceId: &inst.InstanceID,
+			Attribute:  attr,
+		})
+		return out, wrapCall("DescribeInstanceAttribute", err)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		userData, err = describe(ctx, types.InstanceAttributeNameUserData)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		termination, err = describe(ctx, types.InstanceAttributeNameDisableApiTermination)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		stop, err = describe(ctx, types.InstanceAttributeNameDisableApiStop)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
-	return instances, nil
+	if userData.UserData != nil && userData.UserData.Value != nil {
+		raw, err := base64.StdEncoding.DecodeString(*userData.UserData.Value)
+		if err != nil {
+			return wrapCall("DescribeInstanceAttribute", fmt.Errorf("decode user data: %w", err))
+		}
+		inst.UserDataHash = models.HashUserData(raw)
+	}
+	if termination.DisableApiTermination != nil {
+		inst.DisableAPITermination = safeBool(termination.DisableApiTermination.Value)
+	}
+	if stop.DisableApiStop != nil {
+		inst.DisableAPIStop = safeBool(stop.DisableApiStop.Value)
+	}
+	return nil
 }
 
 // convertEC2Instance converts an AWS SDK EC2 instance to our model.
+//
+// Block devices carry only their device name, volume ID and DeleteOnTermination
+// here; fetchVolumeDetails fills in the rest.
 func convertEC2Instance(inst types.Instance) models.EC2Instance {
 	instance := models.EC2Instance{
 		InstanceID:       safeString(inst.InstanceId),
@@ -104,18 +294,58 @@ func convertEC2Instance(inst types.Instance) models.EC2Instance {
 		}
 	}
 
-	// Root Block Device
-	for _, mapping := range inst.BlockDeviceMappings {
-		if mapping.DeviceName != nil && *mapping.DeviceName == safeString(inst.RootDeviceName) {
-			if mapping.Ebs != nil {
-				instance.RootBlockDevice = models.BlockDevice{
-					DeleteOnTermination: safeBool(mapping.Ebs.DeleteOnTermination),
-				}
-				// Note: Volume details like size, type, encryption require additional DescribeVolumes call
+	// Metadata Options (IMDS)
+	if mo := inst.MetadataOptions; mo != nil {
+		instance.MetadataOptions = models.MetadataOptions{
+			HTTPTokens:              string(mo.HttpTokens),
+			HTTPEndpoint:            string(mo.HttpEndpoint),
+			HTTPPutResponseHopLimit: int(safeInt32(mo.HttpPutResponseHopLimit)),
+			InstanceMetadataTags:    string(mo.InstanceMetadataTags),
+		}
+	}
+
+	// Network Interfaces
+	for _, eni := range inst.NetworkInterfaces {
+		ni := models.NetworkInterface{
+			NetworkInterfaceID: safeString(eni.NetworkInterfaceId),
+			SubnetID:           safeString(eni.SubnetId),
+			PrivateIP:          safeString(eni.PrivateIpAddress),
+		}
+		if eni.Attachment != nil {
+			ni.DeviceIndex = int(safeInt32(eni.Attachment.DeviceIndex))
+			ni.DeleteOnTermination = safeBool(eni.Attachment.DeleteOnTermination)
+		}
+		for _, sg := range eni.Groups {
+			if sg.GroupId != nil {
+				ni.SecurityGroupIDs = append(ni.SecurityGroupIDs, *sg.GroupId)
 			}
-			break
 		}
+		instance.NetworkInterfaces = append(instance.NetworkInterfaces, ni)
 	}
+	sort.Slice(instance.NetworkInterfaces, func(i, j int) bool {
+		return instance.NetworkInterfaces[i].DeviceIndex < instance.NetworkInterfaces[j].DeviceIndex
+	})
+
+	// Block Devices
+	rootDevice := safeString(inst.RootDeviceName)
+	for _, mapping := range inst.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+		dev := models.BlockDevice{
+			DeviceName:          safeString(mapping.DeviceName),
+			VolumeID:            safeString(mapping.Ebs.VolumeId),
+			DeleteOnTermination: safeBool(mapping.Ebs.DeleteOnTermination),
+		}
+		if dev.DeviceName == rootDevice {
+			instance.RootBlockDevice = dev
+		} else {
+			instance.EBSBlockDevices = append(instance.EBSBlockDevices, dev)
+		}
+	}
+	sort.Slice(instance.EBSBlockDevices, func(i, j int) bool {
+		return instance.EBSBlockDevices[i].DeviceName < instance.EBSBlockDevices[j].DeviceName
+	})
 
 	return instance
 }
@@ -128,6 +358,14 @@ func safeString(s *string) string {
 	return *s
 }
 
+// safeInt32 safely dereferences an int32 pointer, returning 0 if nil.
+func safeInt32(i *int32) int32 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
 // safeBool safely dereferences a bool pointer, returning false if nil.
 func safeBool(b *bool) bool {
 	if b == nil {
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// apiCallError tags an error with the AWS API operation that produced it.
+type apiCallError struct {
+	Operation string
+	Err       error
+}
+
+func (e *apiCallError) Error() string {
+	return e.Operation + ": " + e.Err.Error()
+}
+
+func (e *apiCallError) Unwrap() error {
+	return e.Err
+}
+
+// wrapCall tags err with the operation name. A nil error is returned unchanged.
+func wrapCall(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &apiCallError{Operation: operation, Err: err}
+}
+
+// isErrorCode reports whether err is an AWS API error with the given code.
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// newFetchError converts an error into a structured FetchError.
+//
+// The operation is taken from an apiCallError in the chain when present,
+// falling back to the supplied default. The AWS error code is extracted
+// from any smithy.APIError in the chain.
+func newFetchError(service, resourceType, resource, operation string, err error) models.FetchError {
+	fe := models.FetchError{
+		Service:      service,
+		ResourceType: resourceType,
+		Resource:     resource,
+		Operation:    operation,
+		Message:      err.Error(),
+	}
+	var callErr *apiCallError
+	if errors.As(err, &callErr) {
+		fe.Operation = callErr.Operation
+		fe.Message = callErr.Err.Error()
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		fe.ErrorCode = apiErr.ErrorCode()
+	}
+	return fe
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -13,53 +14,73 @@ import (
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
-// FetchIAMResources retrieves all IAM resources (roles, users, policies, groups) from AWS.
+// FetchIAMResources retrieves all IAM resources (roles, users, policies, groups,
+// instance profiles) from AWS.
 //
-// The four resource types are fetched in parallel using errgroup. AWS-managed roles,
+// The five resource types are fetched in parallel using errgroup. AWS-managed roles,
 // users, and policies (those with paths starting with /aws-service-role/ or /aws-reserved/)
 // are excluded to focus on customer-managed resources.
 //
+// Failures of per-resource calls (tags, policy documents, inline policies,
+// attachments, members) are recorded in the returned state's Errors; only list-call failures are fatal.
+// Each resource is reported to the FetchTrace of ctx, if any, as soon as it
+// has been fetched.
+//
 // Parameters:
+//   - ctx: context for cancellation
 //   - cfg: AWS SDK configuration for API calls
 //
 // Returns:
 //   - *models.IAMLiveState: all IAM resources
-//   - error: if any AWS API call fails
-func FetchIAMResources(cfg sdkaws.Config) (*models.IAMLiveState, error) {
-	ctx := context.Background()
-	client := iam.NewFromConfig(cfg)
+//   - error: if any list call fails
+func FetchIAMResources(ctx context.Context, cfg sdkaws.Config) (*models.IAMLiveState, error) {
+	return FetchIAMResourcesWithClient(ctx, iam.NewFromConfig(cfg))
+}
 
+// FetchIAMResourcesWithClient retrieves all IAM resources using the provided client.
+//
+// This is the injectable form of FetchIAMResources; tests pass a fake IAMAPI.
+func FetchIAMResourcesWithClient(ctx context.Context, client IAMAPI) (*models.IAMLiveState, error) {
 	var (
 		roles    []models.IAMRole
 		users    []models.IAMUser
 		policies []models.IAMPolicy
 		groups   []models.IAMGroup
+		profiles []models.IAMInstanceProfile
+
+		roleErrs, userErrs, policyErrs, groupErrs, profileErrs []models.FetchError
 	)
 
-	// Fetch all four IAM resource types in parallel
+	// Fetch all five IAM resource types in parallel
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
 		var err error
-		roles, err = fetchIAMRoles(ctx, client)
+		roles, roleErrs, err = fetchIAMRoles(ctx, client)
 		return err
 	})
 
 	g.Go(func() error {
 		var err error
-		users, err = fetchIAMUsers(ctx, client)
+		users, userErrs, err = fetchIAMUsers(ctx, client)
 		return err
 	})
 
 	g.Go(func() error {
 		var err error
-		policies, err = fetchIAMPolicies(ctx, client)
+		policies, policyErrs, err = fetchIAMPolicies(ctx, client)
 		return err
 	})
 
 	g.Go(func() error {
 		var err error
-		groups, err = fetchIAMGroups(ctx, client)
+		groups, groupErrs, err = fetchIAMGroups(ctx, client)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		profiles, profileErrs, err = fetchIAMInstanceProfiles(ctx, client)
 		return err
 	})
 
@@ -67,47 +88,76 @@ func FetchIAMResources(cfg sdkaws.Config) (*models.IAMLiveState, error) {
 		return nil, err
 	}
 
+	var fetchErrs []models.FetchError
+	fetchErrs = append(fetchErrs, roleErrs...)
+	fetchErrs = append(fetchErrs, userErrs...)
+	fetchErrs = append(fetchErrs, policyErrs...)
+	fetchErrs = append(fetchErrs, groupErrs...)
+	fetchErrs = append(fetchErrs, profileErrs...)
+
 	return &models.IAMLiveState{
-		Roles:    roles,
-		Users:    users,
-		Policies: policies,
-		Groups:   groups,
+		Roles:            roles,
+		Users:            users,
+		Policies:         policies,
+		Groups:           groups,
+		InstanceProfiles: profiles,
+		Errors:           fetchErrs,
 	}, nil
 }
 
-// fetchIAMRoles lists all customer-managed IAM roles with their trust policies and attached policies.
-func fetchIAMRoles(ctx context.Context, client *iam.Client) ([]models.IAMRole, error) {
+// fetchIAMRoles lists all customer-managed IAM roles with their trust policies,
+// permissions boundaries, attached policies and inline policies.
+func fetchIAMRoles(ctx context.Context, client IAMAPI) ([]models.IAMRole, []models.FetchError, error) {
 	var roles []models.IAMRole
+	var fetchErrs []models.FetchError
+	trace := contextFetchTrace(ctx)
 	paginator := iam.NewListRolesPaginator(client, &iam.ListRolesInput{})
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("ListRoles: %w", err)
+			return nil, nil, fmt.Errorf("ListRoles: %w", err)
 		}
 
 		for _, r := range page.Roles {
 			// Skip AWS service-linked roles
-			path := safeString(r.Path)
-			if path == "/aws-service-role/" || len(path) > 1 && path[:len("/aws-service-role/")] == "/aws-service-role/" {
+			if strings.HasPrefix(safeString(r.Path), "/aws-service-role/") {
 				continue
 			}
 
 			role := convertIAMRole(r)
+			start := len(fetchErrs)
+
+			// ListRoles omits the permissions boundary and tags; GetRole has both
+			roleResp, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: r.RoleName})
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_role", role.RoleName, "GetRole", err))
+			} else if roleResp.Role != nil {
+				role = convertIAMRole(*roleResp.Role)
+			}
 
 			// Fetch attached managed policies
 			attached, err := fetchAttachedRolePolicies(ctx, client, role.RoleName)
 			if err != nil {
-				fmt.Printf("  warning: listing attached policies for role %s: %v\n", role.RoleName, err)
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_role", role.RoleName, "ListAttachedRolePolicies", err))
 			} else {
 				role.AttachedPolicies = attached
 			}
 
+			// Fetch inline policies
+			inline, err := fetchRoleInlinePolicies(ctx, client, role.RoleName)
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_role", role.RoleName, "ListRolePolicies", err))
+			} else {
+				role.InlinePolicies = inline
+			}
+
 			roles = append(roles, role)
+			trace.report(role, fetchErrs[start:])
 		}
 	}
 
-	return roles, nil
+	return roles, fetchErrs, nil
 }
 
 // convertIAMRole converts an AWS SDK IAM role to our model.
@@ -123,12 +173,11 @@ func convertIAMRole(r types.Role) models.IAMRole {
 
 	// Trust policy document is URL-encoded
 	if r.AssumeRolePolicyDocument != nil {
-		decoded, err := url.QueryUnescape(*r.AssumeRolePolicyDocument)
-		if err == nil {
-			role.AssumeRolePolicy = decoded
-		} else {
-			role.AssumeRolePolicy = *r.AssumeRolePolicyDocument
-		}
+		role.AssumeRolePolicy = decodePolicyDocument(*r.AssumeRolePolicyDocument)
+	}
+
+	if r.PermissionsBoundary != nil {
+		role.PermissionsBoundary = safeString(r.PermissionsBoundary.PermissionsBoundaryArn)
 	}
 
 	if r.MaxSessionDuration != nil {
@@ -149,7 +198,7 @@ func convertIAMRole(r types.Role) models.IAMRole {
 }
 
 // fetchAttachedRolePolicies lists managed policy ARNs attached to a role.
-func fetchAttachedRolePolicies(ctx context.Context, client *iam.Client, roleName string) ([]string, error) {
+func fetchAttachedRolePolicies(ctx context.Context, client IAMAPI, roleName string) ([]string, error) {
 	var arns []string
 	paginator := iam.NewListAttachedRolePoliciesPaginator(client, &iam.ListAttachedRolePoliciesInput{
 		RoleName: &roleName,
@@ -170,25 +219,39 @@ func fetchAttachedRolePolicies(ctx context.Context, client *iam.Client, roleName
 	return arns, nil
 }
 
-// fetchIAMUsers lists all IAM users with their tags and attached policies.
-func fetchIAMUsers(ctx context.Context, client *iam.Client) ([]models.IAMUser, error) {
+// fetchIAMUsers lists all IAM users with their tags, permissions boundaries,
+// attached policies and inline policies.
+func fetchIAMUsers(ctx context.Context, client IAMAPI) ([]models.IAMUser, []models.FetchError, error) {
 	var users []models.IAMUser
+	var fetchErrs []models.FetchError
+	trace := contextFetchTrace(ctx)
 	paginator := iam.NewListUsersPaginator(client, &iam.ListUsersInput{})
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("ListUsers: %w", err)
+			return nil, nil, fmt.Errorf("ListUsers: %w", err)
 		}
 
 		for _, u := range page.Users {
 			user := convertIAMUser(u)
+			start := len(fetchErrs)
+
+			// ListUsers omits the permissions boundary; GetUser has it
+			userResp, err := client.GetUser(ctx, &iam.GetUserInput{UserName: u.UserName})
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_user", user.UserName, "GetUser", err))
+			} else if userResp.User != nil {
+				user = convertIAMUser(*userResp.User)
+			}
 
 			// Fetch tags
 			tagResp, err := client.ListUserTags(ctx, &iam.ListUserTagsInput{
 				UserName: u.UserName,
 			})
-			if err == nil {
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_user", user.UserName, "ListUserTags", err))
+			} else {
 				for _, tag := range tagResp.Tags {
 					if tag.Key != nil && tag.Value != nil {
 						user.Tags[*tag.Key] = *tag.Value
@@ -199,31 +262,46 @@ func fetchIAMUsers(ctx context.Context, client *iam.Client) ([]models.IAMUser, e
 			// Fetch attached managed policies
 			attached, err := fetchAttachedUserPolicies(ctx, client, user.UserName)
 			if err != nil {
-				fmt.Printf("  warning: listing attached policies for user %s: %v\n", user.UserName, err)
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_user", user.UserName, "ListAttachedUserPolicies", err))
 			} else {
 				user.AttachedPolicies = attached
 			}
 
+			// Fetch inline policies
+			inline, err := fetchUserInlinePolicies(ctx, client, user.UserName)
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_user", user.UserName, "ListUserPolicies", err))
+			} else {
+				user.InlinePolicies = inline
+			}
+
 			users = append(users, user)
+			trace.report(user, fetchErrs[start:])
 		}
 	}
 
-	return users, nil
+	return users, fetchErrs, nil
 }
 
 // convertIAMUser converts an AWS SDK IAM user to our model.
 func convertIAMUser(u types.User) models.IAMUser {
-	return models.IAMUser{
+	user := models.IAMUser{
 		UserName:         safeString(u.UserName),
 		Arn:              safeString(u.Arn),
 		Path:             safeString(u.Path),
 		Tags:             make(map[string]string),
 		AttachedPolicies: make([]string, 0),
 	}
+
+	if u.PermissionsBoundary != nil {
+		user.PermissionsBoundary = safeString(u.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+
+	return user
 }
 
 // fetchAttachedUserPolicies lists managed policy ARNs attached to a user.
-func fetchAttachedUserPolicies(ctx context.Context, client *iam.Client, userName string) ([]string, error) {
+func fetchAttachedUserPolicies(ctx context.Context, client IAMAPI, userName string) ([]string, error) {
 	var arns []string
 	paginator := iam.NewListAttachedUserPoliciesPaginator(client, &iam.ListAttachedUserPoliciesInput{
 		UserName: &userName,
@@ -245,8 +323,10 @@ func fetchAttachedUserPolicies(ctx context.Context, client *iam.Client, userName
 }
 
 // fetchIAMPolicies lists all customer-managed IAM policies with their policy documents.
-func fetchIAMPolicies(ctx context.Context, client *iam.Client) ([]models.IAMPolicy, error) {
+func fetchIAMPolicies(ctx context.Context, client IAMAPI) ([]models.IAMPolicy, []models.FetchError, error) {
 	var policies []models.IAMPolicy
+	var fetchErrs []models.FetchError
+	trace := contextFetchTrace(ctx)
 	paginator := iam.NewListPoliciesPaginator(client, &iam.ListPoliciesInput{
 		Scope: types.PolicyScopeTypeLocal, // Customer-managed only
 	})
@@ -254,11 +334,12 @@ func fetchIAMPolicies(ctx context.Context, client *iam.Client) ([]models.IAMPoli
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("ListPolicies: %w", err)
+			return nil, nil, fmt.Errorf("ListPolicies: %w", err)
 		}
 
 		for _, p := range page.Policies {
 			pol := convertIAMPolicy(p)
+			start := len(fetchErrs)
 
 			// Fetch the policy document from the default version
 			if p.DefaultVersionId != nil && p.Arn != nil {
@@ -266,13 +347,10 @@ func fetchIAMPolicies(ctx context.Context, client *iam.Client) ([]models.IAMPoli
 					PolicyArn: p.Arn,
 					VersionId: p.DefaultVersionId,
 				})
-				if err == nil && versionResp.PolicyVersion != nil && versionResp.PolicyVersion.Document != nil {
-					decoded, err := url.QueryUnescape(*versionResp.PolicyVersion.Document)
-					if err == nil {
-						pol.PolicyDocument = decoded
-					} else {
-						pol.PolicyDocument = *versionResp.PolicyVersion.Document
-					}
+				if err != nil {
+					fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_policy", pol.PolicyName, "GetPolicyVersion", err))
+				} else if versionResp.PolicyVersion != nil && versionResp.PolicyVersion.Document != nil {
+					pol.PolicyDocument = decodePolicyDocument(*versionResp.PolicyVersion.Document)
 				}
 			}
 
@@ -281,7 +359,9 @@ func fetchIAMPolicies(ctx context.Context, client *iam.Client) ([]models.IAMPoli
 				tagResp, err := client.ListPolicyTags(ctx, &iam.ListPolicyTagsInput{
 					PolicyArn: p.Arn,
 				})
-				if err == nil {
+				if err != nil {
+					fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_policy", pol.PolicyName, "ListPolicyTags", err))
+				} else {
 					for _, tag := range tagResp.Tags {
 						if tag.Key != nil && tag.Value != nil {
 							pol.Tags[*tag.Key] = *tag.Value
@@ -291,10 +371,11 @@ func fetchIAMPolicies(ctx context.Context, client *iam.Client) ([]models.IAMPoli
 			}
 
 			policies = append(policies, pol)
+			trace.report(pol, fetchErrs[start:])
 		}
 	}
 
-	return policies, nil
+	return policies, fetchErrs, nil
 }
 
 // convertIAMPolicy converts an AWS SDK IAM policy to our model.
@@ -313,19 +394,23 @@ func convertIAMPolicy(p types.Policy) models.IAMPolicy {
 	return pol
 }
 
-// fetchIAMGroups lists all IAM groups with their attached policies and members.
-func fetchIAMGroups(ctx context.Context, client *iam.Client) ([]models.IAMGroup, error) {
+// fetchIAMGroups lists all IAM groups with their attached policies, inline
+// policies and members.
+func fetchIAMGroups(ctx context.Context, client IAMAPI) ([]models.IAMGroup, []models.FetchError, error) {
 	var groups []models.IAMGroup
+	var fetchErrs []models.FetchError
+	trace := contextFetchTrace(ctx)
 	paginator := iam.NewListGroupsPaginator(client, &iam.ListGroupsInput{})
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("ListGroups: %w", err)
+			return nil, nil, fmt.Errorf("ListGroups: %w", err)
 		}
 
 		for _, g := range page.Groups {
 			group := convertIAMGroup(g)
+			start := len(fetchErrs)
 
 			// Fetch attached managed policies
 			attachedPaginator := iam.NewListAttachedGroupPoliciesPaginator(client, &iam.ListAttachedGroupPoliciesInput{
@@ -334,7 +419,7 @@ func fetchIAMGroups(ctx context.Context, client *iam.Client) ([]models.IAMGroup,
 			for attachedPaginator.HasMorePages() {
 				ap, err := attachedPaginator.NextPage(ctx)
 				if err != nil {
-					fmt.Printf("  warning: listing attached policies for group %s: %v\n", group.GroupName, err)
+					fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_group", group.GroupName, "ListAttachedGroupPolicies", err))
 					break
 				}
 				for _, p := range ap.AttachedPolicies {
@@ -348,7 +433,9 @@ func fetchIAMGroups(ctx context.Context, client *iam.Client) ([]models.IAMGroup,
 			groupResp, err := client.GetGroup(ctx, &iam.GetGroupInput{
 				GroupName: g.GroupName,
 			})
-			if err == nil {
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_group", group.GroupName, "GetGroup", err))
+			} else {
 				for _, u := range groupResp.Users {
 					if u.UserName != nil {
 						group.Members = append(group.Members, *u.UserName)
@@ -356,11 +443,20 @@ func fetchIAMGroups(ctx context.Context, client *iam.Client) ([]models.IAMGroup,
 				}
 			}
 
+			// Fetch inline policies
+			inline, err := fetchGroupInlinePolicies(ctx, client, group.GroupName)
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_group", group.GroupName, "ListGroupPolicies", err))
+			} else {
+				group.InlinePolicies = inline
+			}
+
 			groups = append(groups, group)
+			trace.report(group, fetchErrs[start:])
 		}
 	}
 
-	return groups, nil
+	return groups, fetchErrs, nil
 }
 
 // convertIAMGroup converts an AWS SDK IAM group to our model.
@@ -373,3 +469,152 @@ func convertIAMGroup(g types.Group) models.IAMGroup {
 		Members:          make([]string, 0),
 	}
 }
+
+// fetchIAMInstanceProfiles lists all IAM instance profiles with their roles and tags.
+func fetchIAMInstanceProfiles(ctx context.Context, client IAMAPI) ([]models.IAMInstanceProfile, []models.FetchError, error) {
+	var profiles []models.IAMInstanceProfile
+	var fetchErrs []models.FetchError
+	trace := contextFetchTrace(ctx)
+	paginator := iam.NewListInstanceProfilesPaginator(client, &iam.ListInstanceProfilesInput{})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ListInstanceProfiles: %w", err)
+		}
+
+		for _, ip := range page.InstanceProfiles {
+			profile := convertIAMInstanceProfile(ip)
+			start := len(fetchErrs)
+
+			// Fetch tags
+			tagResp, err := client.ListInstanceProfileTags(ctx, &iam.ListInstanceProfileTagsInput{
+				InstanceProfileName: ip.InstanceProfileName,
+			})
+			if err != nil {
+				fetchErrs = append(fetchErrs, newFetchError("iam", "aws_iam_instance_profile", profile.InstanceProfileName, "ListInstanceProfileTags", err))
+			} else {
+				for _, tag := range tagResp.Tags {
+					if tag.Key != nil && tag.Value != nil {
+						profile.Tags[*tag.Key] = *tag.Value
+					}
+				}
+			}
+
+			profiles = append(profiles, profile)
+			trace.report(profile, fetchErrs[start:])
+		}
+	}
+
+	return profiles, fetchErrs, nil
+}
+
+// convertIAMInstanceProfile converts an AWS SDK instance profile to our model.
+func convertIAMInstanceProfile(ip types.InstanceProfile) models.IAMInstanceProfile {
+	profile := models.IAMInstanceProfile{
+		InstanceProfileName: safeString(ip.InstanceProfileName),
+		Arn:                 safeString(ip.Arn),
+		Path:                safeString(ip.Path),
+		Roles:               make([]string, 0, len(ip.Roles)),
+		Tags:                make(map[string]string),
+	}
+
+	for _, r := range ip.Roles {
+		if r.RoleName != nil {
+			profile.Roles = append(profile.Roles, *r.RoleName)
+		}
+	}
+
+	return profile
+}
+
+// fetchRoleInlinePolicies returns a role's inline policy documents keyed by policy name.
+func fetchRoleInlinePolicies(ctx context.Context, client IAMAPI, roleName string) (map[string]string, error) {
+	var names []string
+	paginator := iam.NewListRolePoliciesPaginator(client, &iam.ListRolePoliciesInput{RoleName: &roleName})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.PolicyNames...)
+	}
+
+	return fetchInlineDocuments(names, func(name string) (*string, error) {
+		out, err := client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{RoleName: &roleName, PolicyName: &name})
+		if err != nil {
+			return nil, wrapCall("GetRolePolicy", err)
+		}
+		return out.PolicyDocument, nil
+	})
+}
+
+// fetchUserInlinePolicies returns a user's inline policy documents keyed by policy name.
+func fetchUserInlinePolicies(ctx context.Context, client IAMAPI, userName string) (map[string]string, error) {
+	var names []string
+	paginator := iam.NewListUserPoliciesPaginator(client, &iam.ListUserPoliciesInput{UserName: &userName})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.PolicyNames...)
+	}
+
+	return fetchInlineDocuments(names, func(name string) (*string, error) {
+		out, err := client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{UserName: &userName, PolicyName: &name})
+		if err != nil {
+			return nil, wrapCall("GetUserPolicy", err)
+		}
+		return out.PolicyDocument, nil
+	})
+}
+
+// fetchGroupInlinePolicies returns a group's inline policy documents keyed by policy name.
+func fetchGroupInlinePolicies(ctx context.Context, client IAMAPI, groupName string) (map[string]string, error) {
+	var names []string
+	paginator := iam.NewListGroupPoliciesPaginator(client, &iam.ListGroupPoliciesInput{GroupName: &groupName})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, page.PolicyNames...)
+	}
+
+	return fetchInlineDocuments(names, func(name string) (*string, error) {
+		out, err := client.GetGroupPolicy(ctx, &iam.GetGroupPolicyInput{GroupName: &groupName, PolicyName: &name})
+		if err != nil {
+			return nil, wrapCall("GetGroupPolicy", err)
+		}
+		return out.PolicyDocument, nil
+	})
+}
+
+// fetchInlineDocuments fetches the document of each named inline policy with
+// get and returns the decoded documents keyed by name. It returns nil if
+// there are no inline policies.
+func fetchInlineDocuments(names []string, get func(name string) (*string, error)) (map[string]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	docs := make(map[string]string, len(names))
+	for _, name := range names {
+		doc, err := get(name)
+		if err != nil {
+			return nil, err
+		}
+		docs[name] = decodePolicyDocument(safeString(doc))
+	}
+	return docs, nil
+}
+
+// decodePolicyDocument decodes a URL-encoded policy document as returned by
+// the IAM API. The document is returned unchanged if it is not URL-encoded.
+func decodePolicyDocument(doc string) string {
+	decoded, err := url.QueryUnescape(doc)
+	if err != nil {
+		return doc
+	}
+	return decoded
+}
@@ -15,14 +15,15 @@ import (
 // information and verifying the correct account is being accessed.
 //
 // Parameters:
+//   - ctx: context for cancellation
 //   - cfg: AWS SDK configuration with valid credentials
 //
 // Returns:
 //   - *sts.GetCallerIdentityOutput: identity details (ARN, Account, UserId)
 //   - error: if the STS API call fails
-func GetCallerIdentity(cfg sdkaws.Config) (*sts.GetCallerIdentityOutput, error) {
+func GetCallerIdentity(ctx context.Context, cfg sdkaws.Config) (*sts.GetCallerIdentityOutput, error) {
 	client := sts.NewFromConfig(cfg)
-	out, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+	out, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get caller identity: %w", err)
 	}
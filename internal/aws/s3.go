@@ -2,13 +2,13 @@ package aws
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
-	"github.com/aws/smithy-go"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/inayathulla/cloudrift/internal/models"
@@ -17,46 +17,67 @@ import (
 // FetchS3Buckets retrieves all S3 buckets and their configurations from AWS.
 //
 // This function lists all buckets in the account and fetches detailed metadata
-// for each bucket including ACL, tags, versioning, encryption, logging,
-// public access block settings, and lifecycle rules.
+// for each bucket including ACL, object ownership, tags, versioning, encryption,
+// logging, public access block settings, lifecycle rules, bucket policy, CORS,
+// replication and object lock configuration.
 //
-// Buckets that fail to fetch (e.g., due to permissions) are logged and skipped
-// rather than causing the entire operation to fail.
+// Buckets that fail to fetch (e.g., due to permissions) are recorded in the
+// returned state's Errors rather than causing the entire operation to fail.
+// Each bucket is reported to the FetchTrace of ctx, if any, as soon as it
+// has been fetched.
 //
 // Parameters:
+//   - ctx: context for cancellation; buckets not fetched before it is done
+//     are recorded as fetch errors
 //   - cfg: AWS SDK configuration for API calls
 //
 // Returns:
-//   - []models.S3Bucket: slice of bucket configurations
+//   - *models.S3LiveState: bucket configurations and per-bucket fetch errors
 //   - error: if the ListBuckets call fails
-func FetchS3Buckets(cfg sdkaws.Config) ([]models.S3Bucket, error) {
-	ctx := context.Background()
-	client := s3.NewFromConfig(cfg)
+func FetchS3Buckets(ctx context.Context, cfg sdkaws.Config) (*models.S3LiveState, error) {
+	return FetchS3BucketsWithClient(ctx, s3.NewFromConfig(cfg))
+}
 
-	lst, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		return nil, fmt.Errorf("ListBuckets: %w", err)
+// FetchS3BucketsWithClient retrieves all S3 buckets using the provided client.
+//
+// This is the injectable form of FetchS3Buckets; tests pass a fake S3API.
+func FetchS3BucketsWithClient(ctx context.Context, client S3API) (*models.S3LiveState, error) {
+	var names []string
+	paginator := s3.NewListBucketsPaginator(client, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListBuckets: %w", err)
+		}
+		for _, b := range page.Buckets {
+			if b.Name != nil {
+				names = append(names, *b.Name)
+			}
+		}
 	}
 
 	// Pre-allocate output slice
-	out := make([]models.S3Bucket, 0, len(lst.Buckets))
-	for _, b := range lst.Buckets {
-		if b.Name == nil {
-			continue
-		}
-		st, err := fetchBucketState(ctx, *b.Name, client)
+	state := &models.S3LiveState{
+		Buckets: make([]models.S3Bucket, 0, len(names)),
+	}
+	trace := contextFetchTrace(ctx)
+	for _, name := range names {
+		st, err := fetchBucketState(ctx, name, client)
 		if err != nil {
-			fmt.Printf("⚠️ bucket %s: %v\n", *b.Name, err)
+			fe := newFetchError("s3", "aws_s3_bucket", name, "", err)
+			state.Errors = append(state.Errors, fe)
+			trace.report(nil, []models.FetchError{fe})
 			continue
 		}
-		out = append(out, *st)
+		state.Buckets = append(state.Buckets, *st)
+		trace.report(*st, nil)
 	}
-	return out, nil
+	return state, nil
 }
 
 // fetchBucketState retrieves all configuration attributes for a single S3 bucket.
 //
-// This function makes 7 parallel API calls to fetch:
+// This function makes 12 parallel API calls to fetch:
 //   - ACL (GetBucketAcl)
 //   - Tags (GetBucketTagging)
 //   - Versioning (GetBucketVersioning)
@@ -64,10 +85,16 @@ func FetchS3Buckets(cfg sdkaws.Config) ([]models.S3Bucket, error) {
 //   - Logging (GetBucketLogging)
 //   - Public Access Block (GetPublicAccessBlock)
 //   - Lifecycle Rules (GetBucketLifecycleConfiguration)
+//   - Object Ownership (GetBucketOwnershipControls)
+//   - Bucket Policy (GetBucketPolicy)
+//   - CORS Rules (GetBucketCors)
+//   - Replication (GetBucketReplication)
+//   - Object Lock (GetObjectLockConfiguration)
 //
 // Expected "not found" errors (e.g., NoSuchTagSet, NoSuchLifecycleConfiguration)
-// are gracefully handled and don't cause failures.
-func fetchBucketState(ctx context.Context, name string, client *s3.Client) (*models.S3Bucket, error) {
+// are gracefully handled and don't cause failures. Any other error is tagged
+// with the failing operation so the caller can report it.
+func fetchBucketState(ctx context.Context, name string, client S3API) (*models.S3Bucket, error) {
 	var (
 		aclResp *s3.GetBucketAclOutput
 		tagResp *s3.GetBucketTaggingOutput
@@ -76,6 +103,11 @@ func fetchBucketState(ctx context.Context, name string, client *s3.Client) (*mod
 		logResp *s3.GetBucketLoggingOutput
 		pabResp *s3.GetPublicAccessBlockOutput
 		lcResp  *s3.GetBucketLifecycleConfigurationOutput
+		ownResp *s3.GetBucketOwnershipControlsOutput
+		polResp *s3.GetBucketPolicyOutput
+		corResp *s3.GetBucketCorsOutput
+		repResp *s3.GetBucketReplicationOutput
+		olcResp *s3.GetObjectLockConfigurationOutput
 	)
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -84,65 +116,111 @@ func fetchBucketState(ctx context.Context, name string, client *s3.Client) (*mod
 	g.Go(func() error {
 		var err error
 		aclResp, err = client.GetBucketAcl(ctx, &s3.GetBucketAclInput{Bucket: &name})
-		return err
+		return wrapCall("GetBucketAcl", err)
 	})
 
 	// 2) Tags (ignore missing tag-set)
 	g.Go(func() error {
 		var err error
 		tagResp, err = client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: &name})
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+		if isErrorCode(err, "NoSuchTagSet") {
 			return nil
 		}
-		return err
+		return wrapCall("GetBucketTagging", err)
 	})
 
 	// 3) Versioning
 	g.Go(func() error {
 		var err error
 		verResp, err = client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: &name})
-		return err
+		return wrapCall("GetBucketVersioning", err)
 	})
 
 	// 4) Encryption (ignore if not configured)
 	g.Go(func() error {
 		var err error
 		encResp, err = client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: &name})
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ServerSideEncryptionConfigurationNotFoundError" {
+		if isErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
 			return nil
 		}
-		return err
+		return wrapCall("GetBucketEncryption", err)
 	})
 
 	// 5) Logging
 	g.Go(func() error {
 		var err error
 		logResp, err = client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: &name})
-		return err
+		return wrapCall("GetBucketLogging", err)
 	})
 
 	// 6) Public Access Block (ignore if absent)
 	g.Go(func() error {
 		var err error
 		pabResp, err = client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: &name})
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchPublicAccessBlockConfiguration" {
+		if isErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
 			return nil
 		}
-		return err
+		return wrapCall("GetPublicAccessBlock", err)
 	})
 
 	// 7) Lifecycle Rules (ignore if absent)
 	g.Go(func() error {
 		var err error
 		lcResp, err = client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: &name})
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration" {
+		if isErrorCode(err, "NoSuchLifecycleConfiguration") {
+			return nil
+		}
+		return wrapCall("GetBucketLifecycleConfiguration", err)
+	})
+
+	// 8) Object Ownership (ignore if not configured)
+	g.Go(func() error {
+		var err error
+		ownResp, err = client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{Bucket: &name})
+		if isErrorCode(err, "OwnershipControlsNotFoundError") {
+			return nil
+		}
+		return wrapCall("GetBucketOwnershipControls", err)
+	})
+
+	// 9) Bucket Policy (ignore if absent)
+	g.Go(func() error {
+		var err error
+		polResp, err = client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: &name})
+		if isErrorCode(err, "NoSuchBucketPolicy") {
+			return nil
+		}
+		return wrapCall("GetBucketPolicy", err)
+	})
+
+	// 10) CORS (ignore if absent)
+	g.Go(func() error {
+		var err error
+		corResp, err = client.GetBucketCors(ctx, &s3.GetBucketCorsInput{Bucket: &name})
+		if isErrorCode(err, "NoSuchCORSConfiguration") {
+			return nil
+		}
+		return wrapCall("GetBucketCors", err)
+	})
+
+	// 11) Replication (ignore if absent)
+	g.Go(func() error {
+		var err error
+		repResp, err = client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: &name})
+		if isErrorCode(err, "ReplicationConfigurationNotFoundError") {
+			return nil
+		}
+		return wrapCall("GetBucketReplication", err)
+	})
+
+	// 12) Object Lock (ignore if absent)
+	g.Go(func() error {
+		var err error
+		olcResp, err = client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{Bucket: &name})
+		if isErrorCode(err, "ObjectLockConfigurationNotFoundError") {
 			return nil
 		}
-		return err
+		return wrapCall("GetObjectLockConfiguration", err)
 	})
 
 	// Wait for all calls to complete
@@ -166,26 +244,41 @@ func fetchBucketState(ctx context.Context, name string, client *s3.Client) (*mod
 	// Assemble the bucket model
 	bucket := &models.S3Bucket{
 		Name: name,
-		Acl:  aclToString(aclResp.Grants),
 		Tags: tags,
 	}
 
+	// Object Ownership and ACL
+	if ownResp != nil && ownResp.OwnershipControls != nil && len(ownResp.OwnershipControls.Rules) > 0 {
+		bucket.ObjectOwnership = string(ownResp.OwnershipControls.Rules[0].ObjectOwnership)
+	}
+	var ownerID string
+	if aclResp.Owner != nil {
+		ownerID = safeString(aclResp.Owner.ID)
+	}
+	bucket.AclGrants = normalizeGrants(aclResp.Grants)
+	bucket.Acl = cannedACL(ownerID, bucket.AclGrants, bucket.ObjectOwnership)
+
 	// Versioning
 	if verResp != nil && verResp.Status == types.BucketVersioningStatusEnabled {
 		bucket.VersioningEnabled = true
 	}
 
 	// Encryption
-	if encResp != nil && len(encResp.ServerSideEncryptionConfiguration.Rules) > 0 {
+	if encResp != nil && encResp.ServerSideEncryptionConfiguration != nil &&
+		len(encResp.ServerSideEncryptionConfiguration.Rules) > 0 {
 		rule := encResp.ServerSideEncryptionConfiguration.Rules[0]
-		bucket.EncryptionAlgorithm = string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		if rule.ApplyServerSideEncryptionByDefault != nil {
+			bucket.EncryptionAlgorithm = string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+			bucket.KMSKeyID = safeString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)
+		}
+		bucket.BucketKeyEnabled = safeBool(rule.BucketKeyEnabled)
 	}
 
 	// Logging
 	if logResp != nil && logResp.LoggingEnabled != nil {
 		bucket.LoggingEnabled = true
-		bucket.LoggingTargetBucket = *logResp.LoggingEnabled.TargetBucket
-		bucket.LoggingTargetPrefix = *logResp.LoggingEnabled.TargetPrefix
+		bucket.LoggingTargetBucket = safeString(logResp.LoggingEnabled.TargetBucket)
+		bucket.LoggingTargetPrefix = safeString(logResp.LoggingEnabled.TargetPrefix)
 	}
 
 	// Public Access Block
@@ -202,28 +295,216 @@ func fetchBucketState(ctx context.Context, name string, client *s3.Client) (*mod
 	// Lifecycle Rules
 	if lcResp != nil {
 		for _, r := range lcResp.Rules {
-			if r.ID != nil {
-				bucket.LifecycleRules = append(bucket.LifecycleRules, models.LifecycleRuleSummary{
-					ID:     *r.ID,
-					Status: string(r.Status),
-				})
+			bucket.LifecycleRules = append(bucket.LifecycleRules, convertLifecycleRule(r))
+		}
+	}
+
+	// Bucket Policy
+	if polResp != nil {
+		bucket.Policy = safeString(polResp.Policy)
+	}
+
+	// CORS Rules
+	if corResp != nil {
+		for _, r := range corResp.CORSRules {
+			bucket.CORSRules = append(bucket.CORSRules, models.CORSRule{
+				ID:             safeString(r.ID),
+				AllowedHeaders: r.AllowedHeaders,
+				AllowedMethods: r.AllowedMethods,
+				AllowedOrigins: r.AllowedOrigins,
+				ExposeHeaders:  r.ExposeHeaders,
+				MaxAgeSeconds:  int(safeInt32(r.MaxAgeSeconds)),
+			})
+		}
+	}
+
+	// Replication
+	if repResp != nil && repResp.ReplicationConfiguration != nil {
+		rc := repResp.ReplicationConfiguration
+		bucket.Replication.Role = safeString(rc.Role)
+		for _, r := range rc.Rules {
+			rule := models.ReplicationRule{
+				ID:       safeString(r.ID),
+				Status:   string(r.Status),
+				Priority: int(safeInt32(r.Priority)),
+				Prefix:   safeString(r.Prefix),
+			}
+			if r.Filter != nil && r.Filter.Prefix != nil {
+				rule.Prefix = *r.Filter.Prefix
+			}
+			if r.Destination != nil {
+				rule.DestinationBucket = safeString(r.Destination.Bucket)
+				rule.StorageClass = string(r.Destination.StorageClass)
+			}
+			if r.DeleteMarkerReplication != nil {
+				rule.DeleteMarkerReplication = r.DeleteMarkerReplication.Status == types.DeleteMarkerReplicationStatusEnabled
 			}
+			bucket.Replication.Rules = append(bucket.Replication.Rules, rule)
+		}
+	}
+
+	// Object Lock
+	if olcResp != nil && olcResp.ObjectLockConfiguration != nil {
+		olc := olcResp.ObjectLockConfiguration
+		bucket.ObjectLock.Enabled = olc.ObjectLockEnabled == types.ObjectLockEnabledEnabled
+		if olc.Rule != nil && olc.Rule.DefaultRetention != nil {
+			bucket.ObjectLock.Mode = string(olc.Rule.DefaultRetention.Mode)
+			bucket.ObjectLock.Days = int(safeInt32(olc.Rule.DefaultRetention.Days))
+			bucket.ObjectLock.Years = int(safeInt32(olc.Rule.DefaultRetention.Years))
 		}
 	}
 
 	return bucket, nil
 }
 
-// aclToString converts S3 grants to a simplified ACL string.
+// cannedACLs maps each canned bucket ACL to the grants it adds on top of the
+// owner's FULL_CONTROL grant, in models.SortAclGrants order.
+var cannedACLs = []struct {
+	name   string
+	grants []models.AclGrant
+}{
+	{"private", nil},
+	{"public-read", []models.AclGrant{
+		{Grantee: "group:AllUsers", Permission: "READ"},
+	}},
+	{"public-read-write", []models.AclGrant{
+		{Grantee: "group:AllUsers", Permission: "READ"},
+		{Grantee: "group:AllUsers", Permission: "WRITE"},
+	}},
+	{"authenticated-read", []models.AclGrant{
+		{Grantee: "group:AuthenticatedUsers", Permission: "READ"},
+	}},
+	{"log-delivery-write", []models.AclGrant{
+		{Grantee: "group:LogDelivery", Permission: "READ_ACP"},
+		{Grantee: "group:LogDelivery", Permission: "WRITE"},
+	}},
+}
+
+// convertLifecycleRule converts an SDK lifecycle rule into the model,
+// flattening its filter. The prefix is taken from the filter (or its And
+// operator), falling back to the deprecated rule-level prefix.
+func convertLifecycleRule(r types.LifecycleRule) models.LifecycleRuleSummary {
+	rule := models.LifecycleRuleSummary{
+		ID:     safeString(r.ID),
+		Status: string(r.Status),
+		Prefix: safeString(r.Prefix),
+	}
+
+	if f := r.Filter; f != nil {
+		if f.Prefix != nil {
+			rule.Prefix = *f.Prefix
+		}
+		if f.Tag != nil {
+			rule.FilterTags = map[string]string{safeString(f.Tag.Key): safeString(f.Tag.Value)}
+		}
+		rule.ObjectSizeGreaterThan = sdkaws.ToInt64(f.ObjectSizeGreaterThan)
+		rule.ObjectSizeLessThan = sdkaws.ToInt64(f.ObjectSizeLessThan)
+		if and := f.And; and != nil {
+			if and.Prefix != nil {
+				rule.Prefix = *and.Prefix
+			}
+			if len(and.Tags) > 0 {
+				rule.FilterTags = make(map[string]string, len(and.Tags))
+				for _, t := range and.Tags {
+					rule.FilterTags[safeString(t.Key)] = safeString(t.Value)
+				}
+			}
+			rule.ObjectSizeGreaterThan = sdkaws.ToInt64(and.ObjectSizeGreaterThan)
+			rule.ObjectSizeLessThan = sdkaws.ToInt64(and.ObjectSizeLessThan)
+		}
+	}
+
+	if e := r.Expiration; e != nil {
+		rule.ExpirationDays = int(safeInt32(e.Days))
+		if e.Date != nil {
+			rule.ExpirationDate = e.Date.UTC().Format(time.DateOnly)
+		}
+		rule.ExpiredObjectDeleteMarker = safeBool(e.ExpiredObjectDeleteMarker)
+	}
+	if e := r.NoncurrentVersionExpiration; e != nil {
+		rule.NoncurrentExpirationDays = int(safeInt32(e.NoncurrentDays))
+		rule.NewerNoncurrentVersions = int(safeInt32(e.NewerNoncurrentVersions))
+	}
+	for _, t := range r.Transitions {
+		tr := models.LifecycleTransition{
+			Days:         int(safeInt32(t.Days)),
+			StorageClass: string(t.StorageClass),
+		}
+		if t.Date != nil {
+			tr.Date = t.Date.UTC().Format(time.DateOnly)
+		}
+		rule.Transitions = append(rule.Transitions, tr)
+	}
+	for _, t := range r.NoncurrentVersionTransitions {
+		rule.NoncurrentTransitions = append(rule.NoncurrentTransitions, models.LifecycleTransition{
+			Days:         int(safeInt32(t.NoncurrentDays)),
+			StorageClass: string(t.StorageClass),
+		})
+	}
+	models.SortLifecycleTransitions(rule.Transitions)
+	models.SortLifecycleTransitions(rule.NoncurrentTransitions)
+	if a := r.AbortIncompleteMultipartUpload; a != nil {
+		rule.AbortIncompleteMultipartDays = int(safeInt32(a.DaysAfterInitiation))
+	}
+
+	return rule
+}
+
+// normalizeGrants converts S3 grants to sorted models.AclGrant values.
+func normalizeGrants(grants []types.Grant) []models.AclGrant {
+	out := make([]models.AclGrant, 0, len(grants))
+	for _, g := range grants {
+		if g.Grantee == nil {
+			continue
+		}
+		out = append(out, models.NewAclGrant(
+			string(g.Grantee.Type),
+			safeString(g.Grantee.ID),
+			safeString(g.Grantee.URI),
+			safeString(g.Grantee.EmailAddress),
+			string(g.Permission),
+		))
+	}
+	models.SortAclGrants(out)
+	return out
+}
+
+// cannedACL returns the canned ACL that produces exactly the given grants,
+// or "" if none does (e.g., cross-account or per-user grants).
+//
+// With BucketOwnerEnforced object ownership ACLs are disabled and have no
+// effect, so the bucket is reported as "private" whatever grants remain.
+//
+// Parameters:
+//   - ownerID: canonical user ID of the bucket owner
+//   - grants: normalized, sorted grants from GetBucketAcl
+//   - ownership: the bucket's object ownership setting
 //
-// This is a simplified interpretation: if any grant has FULL_CONTROL permission,
-// the bucket is considered "private"; otherwise, it's "public-read".
-// This heuristic works for common cases but may not capture all ACL nuances.
-func aclToString(grants []types.Grant) string {
+// Returns:
+//   - string: the matching canned ACL name, or ""
+func cannedACL(ownerID string, grants []models.AclGrant, ownership string) string {
+	if ownership == string(types.ObjectOwnershipBucketOwnerEnforced) {
+		return "private"
+	}
+
+	owner := models.AclGrant{Grantee: "id:" + ownerID, Permission: string(types.PermissionFullControl)}
+	var rest []models.AclGrant
+	hasOwner := false
 	for _, g := range grants {
-		if g.Permission == types.PermissionFullControl {
-			return "private"
+		if g == owner {
+			hasOwner = true
+			continue
+		}
+		rest = append(rest, g)
+	}
+	if !hasOwner {
+		return ""
+	}
+
+	for _, c := range cannedACLs {
+		if slices.Equal(rest, c.grants) {
+			return c.name
 		}
 	}
-	return "public-read"
+	return ""
 }
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// FetchTrace is a set of hooks the fetchers call as soon as the live state of
+// a single resource is settled, so callers can report it before the rest of
+// the account has been fetched. Like net/http/httptrace, it is attached to the
+// context passed to a fetcher with WithFetchTrace. Any hook may be nil.
+//
+// The IAM fetcher reports its five resource types from parallel goroutines,
+// so hooks must be safe for concurrent use.
+type FetchTrace struct {
+	// Fetched is called with the live model of each resource fetched
+	// without errors: a models.S3Bucket, models.EC2Instance, models.IAMRole,
+	// models.IAMUser, models.IAMPolicy, models.IAMGroup or
+	// models.IAMInstanceProfile.
+	Fetched func(resource interface{})
+
+	// Failed is called with each per-resource fetch error. A resource with
+	// a fetch error is never passed to Fetched.
+	Failed func(err models.FetchError)
+}
+
+// fetchTraceKey is the context key of a FetchTrace.
+type fetchTraceKey struct{}
+
+// WithFetchTrace returns a copy of ctx whose fetches report to trace.
+func WithFetchTrace(ctx context.Context, trace *FetchTrace) context.Context {
+	return context.WithValue(ctx, fetchTraceKey{}, trace)
+}
+
+// contextFetchTrace returns the FetchTrace attached to ctx, or nil.
+func contextFetchTrace(ctx context.Context) *FetchTrace {
+	trace, _ := ctx.Value(fetchTraceKey{}).(*FetchTrace)
+	return trace
+}
+
+// report passes each of errs to Failed, or resource to Fetched if there are
+// no errors. A nil trace or resource is ignored.
+func (t *FetchTrace) report(resource interface{}, errs []models.FetchError) {
+	if t == nil {
+		return
+	}
+	if len(errs) > 0 {
+		if t.Failed != nil {
+			for _, e := range errs {
+				t.Failed(e)
+			}
+		}
+		return
+	}
+	if resource != nil && t.Fetched != nil {
+		t.Fetched(resource)
+	}
+}
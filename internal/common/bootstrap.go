@@ -6,6 +6,8 @@
 package common
 
 import (
+	"context"
+
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/spf13/viper"
@@ -43,20 +45,20 @@ func LoadAppConfig(configPath string) (profile, region, planPath string, err err
 
 // InitAWS initializes and returns an AWS SDK configuration.
 // This is a convenience wrapper around aws.LoadAWSConfig.
-func InitAWS(profile, region string) (cfg sdkaws.Config, err error) {
-	return aws.LoadAWSConfig(profile, region)
+func InitAWS(ctx context.Context, profile, region string) (cfg sdkaws.Config, err error) {
+	return aws.LoadAWSConfig(ctx, profile, region)
 }
 
 // ValidateCredentials verifies AWS credentials are valid.
 // This is a convenience wrapper around aws.ValidateAWSCredentials.
-func ValidateCredentials(cfg sdkaws.Config) error {
-	return aws.ValidateAWSCredentials(cfg)
+func ValidateCredentials(ctx context.Context, cfg sdkaws.Config) error {
+	return aws.ValidateAWSCredentials(ctx, cfg)
 }
 
 // GetCallerIdentity retrieves the current AWS IAM identity.
 // This is a convenience wrapper around aws.GetCallerIdentity.
-func GetCallerIdentity(cfg sdkaws.Config) (*sts.GetCallerIdentityOutput, error) {
-	return aws.GetCallerIdentity(cfg)
+func GetCallerIdentity(ctx context.Context, cfg sdkaws.Config) (*sts.GetCallerIdentityOutput, error) {
+	return aws.GetCallerIdentity(ctx, cfg)
 }
 
 // LoadPlan reads and parses a Terraform plan JSON file for S3 buckets.
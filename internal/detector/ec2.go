@@ -1,6 +1,7 @@
 package detector
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -56,6 +57,47 @@ type EC2DriftResult struct {
 
 	// RootVolumeDiff is true if root volume configuration differs.
 	RootVolumeDiff bool
+
+	// EBSVolumesDiff is true if any planned additional EBS volume is missing
+	// or configured differently.
+	EBSVolumesDiff bool
+
+	// MetadataOptionsDiff is true if IMDS settings (e.g., http_tokens) differ.
+	MetadataOptionsDiff bool
+
+	// NetworkInterfacesDiff is true if planned network interface attachments differ.
+	NetworkInterfacesDiff bool
+
+	// UserDataDiff is true if the user data hash differs.
+	UserDataDiff bool
+
+	// TerminationProtectionDiff is true if disable_api_termination differs.
+	TerminationProtectionDiff bool
+
+	// StopProtectionDiff is true if disable_api_stop differs.
+	StopProtectionDiff bool
+}
+
+// HasAnyDrift returns true if any drift was detected for this instance.
+func (r EC2DriftResult) HasAnyDrift() bool {
+	return r.Missing ||
+		r.InstanceTypeDiff ||
+		r.AMIDiff ||
+		r.SubnetDiff ||
+		r.SecurityGroupsDiff ||
+		len(r.TagDiffs) > 0 ||
+		len(r.ExtraTags) > 0 ||
+		r.EBSOptimizedDiff ||
+		r.MonitoringDiff ||
+		r.KeyNameDiff ||
+		r.IAMProfileDiff ||
+		r.RootVolumeDiff ||
+		r.EBSVolumesDiff ||
+		r.MetadataOptionsDiff ||
+		r.NetworkInterfacesDiff ||
+		r.UserDataDiff ||
+		r.TerminationProtectionDiff ||
+		r.StopProtectionDiff
 }
 
 // EC2DriftDetector implements drift detection for AWS EC2 instances.
@@ -68,31 +110,60 @@ func NewEC2DriftDetector(cfg sdkaws.Config) *EC2DriftDetector {
 	return &EC2DriftDetector{cfg: cfg}
 }
 
-// FetchLiveState retrieves the current state of all EC2 instances from AWS.
-func (d *EC2DriftDetector) FetchLiveState() (interface{}, error) {
-	return aws.FetchEC2Instances(d.cfg)
+// FetchLiveState retrieves the current state of all EC2 instances from AWS
+// as a *models.EC2LiveState.
+func (d *EC2DriftDetector) FetchLiveState(ctx context.Context) (interface{}, error) {
+	return aws.FetchEC2Instances(ctx, d.cfg)
 }
 
 // DetectDrift compares Terraform-planned instance configurations against live AWS state.
+// Instances whose live state could not be fetched are reported as Unknown.
 func (d *EC2DriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, error) {
 	plans, ok := plan.([]models.EC2Instance)
 	if !ok {
 		return nil, fmt.Errorf("plan type mismatch: expected []models.EC2Instance")
 	}
-	lives, ok := live.([]models.EC2Instance)
+	lives, errs, ok := EC2LiveInstances(live)
 	if !ok {
-		return nil, fmt.Errorf("live type mismatch: expected []models.EC2Instance")
+		return nil, fmt.Errorf("live type mismatch: expected *models.EC2LiveState")
 	}
 
-	ec2Results := DetectAllEC2Drift(plans, lives)
+	// Fetch errors are recorded by instance ID, so an instance is Unknown
+	// when the live instance it pairs with failed. Instances without a live
+	// counterpart can only fail as a whole fetch, recorded by planned ID or
+	// name (see UnfetchedLiveState).
+	failed := models.FailedResources(errs)
+	results := make([]DriftResult, 0, len(plans))
+	for i, live := range pairEC2Instances(plans, lives) {
+		p := plans[i]
+		id := p.InstanceID
+		if live != nil {
+			id = live.InstanceID
+		}
+		if (id != "" && failed[models.ResourceKey("aws_instance", id)]) ||
+			(id == "" && failed[models.ResourceKey("aws_instance", p.Name())]) {
+			results = append(results, DriftResult{
+				BucketName:       p.Name(),
+				ResourceType:     "aws_instance",
+				ResourceID:       id,
+				TerraformAddress: p.TerraformAddress,
+				Unknown:          true,
+			})
+			continue
+		}
+
+		r := DetectEC2Drift(p, live)
+		if !r.HasAnyDrift() {
+			continue
+		}
 
-	// Convert EC2DriftResult to generic DriftResult for compatibility
-	results := make([]DriftResult, 0, len(ec2Results))
-	for _, r := range ec2Results {
 		// Create a generic DriftResult with bucket name field (reusing existing struct)
 		// This is a temporary compatibility layer until we fully migrate to the new interface
 		dr := DriftResult{
-			BucketName: r.InstanceName, // Reuse BucketName field for resource name
+			BucketName:       r.InstanceName, // Reuse BucketName field for resource name
+			ResourceType:     "aws_instance",
+			ResourceID:       id,
+			TerraformAddress: r.TerraformAddress,
 		}
 		if r.Missing {
 			dr.Missing = true
@@ -104,16 +175,29 @@ func (d *EC2DriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, e
 		// Use other diff fields to indicate drift (hacky but maintains compatibility)
 		if r.InstanceTypeDiff || r.AMIDiff || r.SubnetDiff || r.SecurityGroupsDiff ||
 			r.EBSOptimizedDiff || r.MonitoringDiff || r.KeyNameDiff || r.IAMProfileDiff ||
-			r.RootVolumeDiff {
+			r.RootVolumeDiff || r.EBSVolumesDiff || r.MetadataOptionsDiff || r.NetworkInterfacesDiff ||
+			r.UserDataDiff || r.TerminationProtectionDiff || r.StopProtectionDiff {
 			dr.AclDiff = true // Indicates "other diffs exist"
 		}
 
 		results = append(results, dr)
 	}
-
 	return results, nil
 }
 
+// EC2LiveInstances unpacks EC2 live state, accepting both *models.EC2LiveState
+// and a plain []models.EC2Instance.
+func EC2LiveInstances(live interface{}) ([]models.EC2Instance, []models.FetchError, bool) {
+	switch l := live.(type) {
+	case *models.EC2LiveState:
+		return l.Instances, l.Errors, true
+	case []models.EC2Instance:
+		return l, nil, true
+	default:
+		return nil, nil, false
+	}
+}
+
 // DetectEC2Drift compares a single planned instance against its actual AWS state.
 func DetectEC2Drift(plan models.EC2Instance, actual *models.EC2Instance) EC2DriftResult {
 	res := EC2DriftResult{
@@ -145,7 +229,7 @@ func DetectEC2Drift(plan models.EC2Instance, actual *models.EC2Instance) EC2Drif
 	}
 
 	// Security groups (compare as sets)
-	if !stringSlicesEqual(plan.SecurityGroupIDs, actual.SecurityGroupIDs) {
+	if !StringSlicesEqual(plan.SecurityGroupIDs, actual.SecurityGroupIDs) {
 		res.SecurityGroupsDiff = true
 	}
 
@@ -170,12 +254,36 @@ func DetectEC2Drift(plan models.EC2Instance, actual *models.EC2Instance) EC2Drif
 	}
 
 	// Root block device
-	if plan.RootBlockDevice.VolumeType != "" {
-		if plan.RootBlockDevice.VolumeType != actual.RootBlockDevice.VolumeType ||
-			(plan.RootBlockDevice.VolumeSize > 0 && plan.RootBlockDevice.VolumeSize != actual.RootBlockDevice.VolumeSize) ||
-			plan.RootBlockDevice.Encrypted != actual.RootBlockDevice.Encrypted {
-			res.RootVolumeDiff = true
-		}
+	if plan.RootBlockDevice.VolumeType != "" && BlockDeviceDrift(plan.RootBlockDevice, actual.RootBlockDevice) {
+		res.RootVolumeDiff = true
+	}
+
+	// Additional EBS volumes
+	if EBSVolumesDrift(plan.EBSBlockDevices, actual.EBSBlockDevices) {
+		res.EBSVolumesDiff = true
+	}
+
+	// Metadata options (IMDS)
+	if MetadataOptionsDrift(plan.MetadataOptions, actual.MetadataOptions) {
+		res.MetadataOptionsDiff = true
+	}
+
+	// Network interfaces
+	if NetworkInterfacesDrift(plan.NetworkInterfaces, actual.NetworkInterfaces) {
+		res.NetworkInterfacesDiff = true
+	}
+
+	// User data
+	if plan.UserDataHash != "" && plan.UserDataHash != actual.UserDataHash {
+		res.UserDataDiff = true
+	}
+
+	// Termination and stop protection
+	if plan.DisableAPITermination != actual.DisableAPITermination {
+		res.TerminationProtectionDiff = true
+	}
+	if plan.DisableAPIStop != actual.DisableAPIStop {
+		res.StopProtectionDiff = true
 	}
 
 	// Tag diffs
@@ -197,6 +305,20 @@ func DetectEC2Drift(plan models.EC2Instance, actual *models.EC2Instance) EC2Drif
 
 // DetectAllEC2Drift performs drift detection across all planned EC2 instances.
 func DetectAllEC2Drift(plans, lives []models.EC2Instance) []EC2DriftResult {
+	out := make([]EC2DriftResult, 0, len(plans))
+	for i, live := range pairEC2Instances(plans, lives) {
+		// Only include results with actual drift
+		if dr := DetectEC2Drift(plans[i], live); dr.HasAnyDrift() {
+			out = append(out, dr)
+		}
+	}
+	return out
+}
+
+// pairEC2Instances returns the live instance each planned instance is
+// compared with, by index, or nil if it has none. Instances are paired by
+// instance ID first, then by Name tag for instances created by the plan.
+func pairEC2Instances(plans, lives []models.EC2Instance) []*models.EC2Instance {
 	// Build map of live instances by ID
 	byID := make(map[string]*models.EC2Instance, len(lives))
 	for i := range lives {
@@ -211,43 +333,81 @@ func DetectAllEC2Drift(plans, lives []models.EC2Instance) []EC2DriftResult {
 		}
 	}
 
-	out := make([]EC2DriftResult, 0, len(plans))
-	for _, p := range plans {
+	paired := make([]*models.EC2Instance, len(plans))
+	for i, p := range plans {
 		// Try to find matching live instance by ID first, then by name
-		var live *models.EC2Instance
 		if p.InstanceID != "" {
-			live = byID[p.InstanceID]
+			paired[i] = byID[p.InstanceID]
 		}
-		if live == nil {
+		if paired[i] == nil {
 			// For new instances, try matching by Name tag
 			if name, ok := p.Tags["Name"]; ok {
-				live = byName[name]
+				paired[i] = byName[name]
 			}
 		}
+	}
+	return paired
+}
 
-		dr := DetectEC2Drift(p, live)
+// BlockDeviceDrift reports whether a live EBS volume differs from its planned
+// configuration. Size, IOPS, throughput and encryption are only compared
+// when planned, since the provider leaves them unset to accept AWS and
+// account defaults.
+func BlockDeviceDrift(plan, actual models.BlockDevice) bool {
+	return (plan.VolumeType != "" && plan.VolumeType != actual.VolumeType) ||
+		(plan.VolumeSize > 0 && plan.VolumeSize != actual.VolumeSize) ||
+		(plan.IOPS > 0 && plan.IOPS != actual.IOPS) ||
+		(plan.Throughput > 0 && plan.Throughput != actual.Throughput) ||
+		(plan.Encrypted != nil && *plan.Encrypted != (actual.Encrypted != nil && *actual.Encrypted))
+}
 
-		// Only include results with actual drift
-		if dr.Missing ||
-			dr.InstanceTypeDiff ||
-			dr.AMIDiff ||
-			dr.SubnetDiff ||
-			dr.SecurityGroupsDiff ||
-			len(dr.TagDiffs) > 0 ||
-			len(dr.ExtraTags) > 0 ||
-			dr.EBSOptimizedDiff ||
-			dr.MonitoringDiff ||
-			dr.KeyNameDiff ||
-			dr.IAMProfileDiff ||
-			dr.RootVolumeDiff {
-			out = append(out, dr)
+// EBSVolumesDrift reports whether any planned additional EBS volume is absent
+// from the live instance or differs from it. Volumes are matched by device
+// name; live volumes not in the plan (e.g., from aws_volume_attachment) are ignored.
+func EBSVolumesDrift(plan, actual []models.BlockDevice) bool {
+	byDevice := make(map[string]models.BlockDevice, len(actual))
+	for _, dev := range actual {
+		byDevice[dev.DeviceName] = dev
+	}
+	for _, p := range plan {
+		live, ok := byDevice[p.DeviceName]
+		if !ok || BlockDeviceDrift(p, live) {
+			return true
 		}
 	}
-	return out
+	return false
+}
+
+// MetadataOptionsDrift reports whether any planned IMDS setting differs from
+// the live instance. Unset planned fields are ignored.
+func MetadataOptionsDrift(plan, actual models.MetadataOptions) bool {
+	return (plan.HTTPTokens != "" && plan.HTTPTokens != actual.HTTPTokens) ||
+		(plan.HTTPEndpoint != "" && plan.HTTPEndpoint != actual.HTTPEndpoint) ||
+		(plan.HTTPPutResponseHopLimit > 0 && plan.HTTPPutResponseHopLimit != actual.HTTPPutResponseHopLimit) ||
+		(plan.InstanceMetadataTags != "" && plan.InstanceMetadataTags != actual.InstanceMetadataTags)
+}
+
+// NetworkInterfacesDrift reports whether any planned network_interface
+// attachment differs from the live instance. Interfaces are matched by
+// device index.
+func NetworkInterfacesDrift(plan, actual []models.NetworkInterface) bool {
+	byIndex := make(map[int]models.NetworkInterface, len(actual))
+	for _, eni := range actual {
+		byIndex[eni.DeviceIndex] = eni
+	}
+	for _, p := range plan {
+		live, ok := byIndex[p.DeviceIndex]
+		if !ok ||
+			(p.NetworkInterfaceID != "" && p.NetworkInterfaceID != live.NetworkInterfaceID) ||
+			p.DeleteOnTermination != live.DeleteOnTermination {
+			return true
+		}
+	}
+	return false
 }
 
-// stringSlicesEqual compares two string slices as sets (order-independent).
-func stringSlicesEqual(a, b []string) bool {
+// StringSlicesEqual compares two string slices as sets (order-independent).
+func StringSlicesEqual(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
 	}
@@ -0,0 +1,132 @@
+package detector
+
+import "github.com/inayathulla/cloudrift/internal/models"
+
+// FetchErrors returns the per-resource fetch errors recorded in a live state
+// value returned by FetchLiveState. Legacy slice types carry no errors.
+func FetchErrors(live interface{}) []models.FetchError {
+	switch l := live.(type) {
+	case *models.S3LiveState:
+		return l.Errors
+	case *models.EC2LiveState:
+		return l.Errors
+	case *models.IAMLiveState:
+		return l.Errors
+	default:
+		return nil
+	}
+}
+
+// plannedResource identifies a planned resource for markUnknown.
+type plannedResource struct {
+	resourceType string
+	name         string
+	address      string
+}
+
+// markUnknown replaces drift results for resources whose live state could not
+// be fetched with a single Unknown result. Resources are matched by type and
+// name, so a failed role does not hide drift in a user of the same name.
+//
+// A failed fetch means the comparison is meaningless: a bucket that could not
+// be read would otherwise be reported as missing, and a partially fetched
+// role would show false attribute drift. Planned resources that failed but
+// produced no drift result are appended so the failure is still visible.
+//
+// Parameters:
+//   - results: drift results from the service-specific comparison
+//   - planned: all planned resources, in plan order
+//   - errs: fetch errors recorded alongside the live state
+//
+// Returns:
+//   - []DriftResult: results with failed resources marked Unknown
+func markUnknown(results []DriftResult, planned []plannedResource, errs []models.FetchError) []DriftResult {
+	if len(errs) == 0 {
+		return results
+	}
+	failed := models.FailedResources(errs)
+
+	seen := make(map[string]bool, len(results))
+	for i, r := range results {
+		key := models.ResourceKey(r.ResourceType, r.BucketName)
+		if failed[key] {
+			results[i] = DriftResult{BucketName: r.BucketName, ResourceType: r.ResourceType, TerraformAddress: r.TerraformAddress, Unknown: true}
+		}
+		seen[key] = true
+	}
+	for _, p := range planned {
+		key := models.ResourceKey(p.resourceType, p.name)
+		if failed[key] && !seen[key] {
+			results = append(results, DriftResult{BucketName: p.name, ResourceType: p.resourceType, TerraformAddress: p.address, Unknown: true})
+			seen[key] = true
+		}
+	}
+	return results
+}
+
+// UnfetchedLiveState builds an empty live state in which every planned
+// resource is recorded as a fetch error.
+//
+// It is used when FetchLiveState is aborted (for example by Ctrl-C or a
+// scan timeout) so that drift detection can still run and report each
+// planned resource as Unknown instead of Missing.
+//
+// Parameters:
+//   - plan: planned resources ([]models.S3Bucket, []models.EC2Instance or *models.IAMPlanResources)
+//   - err: the error that aborted the fetch
+//
+// Returns:
+//   - interface{}: a live state value accepted by the matching detector's DetectDrift
+//   - bool: false if the plan type is not recognised
+func UnfetchedLiveState(plan interface{}, err error) (interface{}, bool) {
+	unfetched := func(service, resourceType, name string) models.FetchError {
+		return models.FetchError{
+			Service:      service,
+			ResourceType: resourceType,
+			Resource:     name,
+			Operation:    "FetchLiveState",
+			Message:      err.Error(),
+		}
+	}
+
+	switch p := plan.(type) {
+	case []models.S3Bucket:
+		state := &models.S3LiveState{}
+		for _, b := range p {
+			state.Errors = append(state.Errors, unfetched("s3", "aws_s3_bucket", b.Name))
+		}
+		return state, true
+	case []models.EC2Instance:
+		state := &models.EC2LiveState{}
+		// Instance errors are recorded by ID; instances the plan creates
+		// have none yet
+		for _, inst := range p {
+			id := inst.InstanceID
+			if id == "" {
+				id = inst.Name()
+			}
+			state.Errors = append(state.Errors, unfetched("ec2", "aws_instance", id))
+		}
+		return state, true
+	case *models.IAMPlanResources:
+		state := &models.IAMLiveState{}
+		for _, r := range p.Roles {
+			state.Errors = append(state.Errors, unfetched("iam", "aws_iam_role", r.RoleName))
+		}
+		for _, u := range p.Users {
+			state.Errors = append(state.Errors, unfetched("iam", "aws_iam_user", u.UserName))
+		}
+		for _, pol := range p.Policies {
+			state.Errors = append(state.Errors, unfetched("iam", "aws_iam_policy", pol.PolicyName))
+		}
+		for _, g := range p.Groups {
+			state.Errors = append(state.Errors, unfetched("iam", "aws_iam_group", g.GroupName))
+		}
+		for _, ip := range p.InstanceProfiles {
+			state.Errors = append(state.Errors, unfetched("iam", "aws_iam_instance_profile", ip.InstanceProfileName))
+		}
+		return state, true
+	default:
+		return nil, false
+	}
+}
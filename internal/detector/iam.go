@@ -1,18 +1,21 @@
 package detector
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/inayathulla/cloudrift/internal/aws"
+	"github.com/inayathulla/cloudrift/internal/iampolicy"
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
 // IAMDriftResult captures the drift detection results for a single IAM resource.
 type IAMDriftResult struct {
-	// ResourceType identifies the IAM resource type: "role", "user", "policy", "group".
+	// ResourceType identifies the IAM resource type: "role", "user", "policy",
+	// "group" or "instance_profile".
 	ResourceType string
 
 	// ResourceName is the name of the IAM resource.
@@ -45,11 +48,25 @@ type IAMDriftResult struct {
 	// MembersDiff is true if group membership differs (groups only).
 	MembersDiff bool
 
+	// InlinePoliciesDiff is true if inline policies differ (roles, users and groups).
+	InlinePoliciesDiff bool
+
+	// PermissionsBoundaryDiff is true if the permissions boundary differs (roles and users).
+	PermissionsBoundaryDiff bool
+
+	// RolesDiff is true if the roles in an instance profile differ (instance profiles only).
+	RolesDiff bool
+
 	// TagDiffs maps tag keys to [expected, actual] value pairs for mismatched tags.
 	TagDiffs map[string][2]string
 
 	// ExtraTags contains tags present in AWS but not in the plan.
 	ExtraTags map[string]string
+
+	// PolicyDiffs maps policy document attributes ("assume_role_policy",
+	// "policy_document" or "inline_policies.<name>") to their statement-level
+	// differences. Documents that cannot be parsed have no entry.
+	PolicyDiffs map[string]iampolicy.Diff
 }
 
 // HasAnyDrift returns true if any drift was detected for this resource.
@@ -62,6 +79,9 @@ func (r IAMDriftResult) HasAnyDrift() bool {
 		r.PathDiff ||
 		r.AttachedPoliciesDiff ||
 		r.MembersDiff ||
+		r.InlinePoliciesDiff ||
+		r.PermissionsBoundaryDiff ||
+		r.RolesDiff ||
 		len(r.TagDiffs) > 0 ||
 		len(r.ExtraTags) > 0
 }
@@ -77,11 +97,12 @@ func NewIAMDriftDetector(cfg sdkaws.Config) *IAMDriftDetector {
 }
 
 // FetchLiveState retrieves the current state of all IAM resources from AWS.
-func (d *IAMDriftDetector) FetchLiveState() (interface{}, error) {
-	return aws.FetchIAMResources(d.cfg)
+func (d *IAMDriftDetector) FetchLiveState(ctx context.Context) (interface{}, error) {
+	return aws.FetchIAMResources(ctx, d.cfg)
 }
 
 // DetectDrift compares Terraform-planned IAM configurations against live AWS state.
+// Resources with recorded fetch errors are reported as Unknown.
 func (d *IAMDriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, error) {
 	plans, ok := plan.(*models.IAMPlanResources)
 	if !ok {
@@ -98,22 +119,56 @@ func (d *IAMDriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, e
 	results := make([]DriftResult, 0, len(iamResults))
 	for _, r := range iamResults {
 		dr := DriftResult{
-			BucketName: r.ResourceName, // Reuse BucketName field for resource name
-			Missing:    r.Missing,
-			TagDiffs:   r.TagDiffs,
-			ExtraTags:  r.ExtraTags,
+			BucketName:       r.ResourceName, // Reuse BucketName field for resource name
+			ResourceType:     "aws_iam_" + r.ResourceType,
+			TerraformAddress: r.TerraformAddress,
+			Missing:          r.Missing,
+			TagDiffs:         r.TagDiffs,
+			ExtraTags:        r.ExtraTags,
+		}
+
+		// Report policy documents as [removed, added] statement entries
+		for attr, diff := range r.PolicyDiffs {
+			if dr.Diffs == nil {
+				dr.Diffs = make(map[string][2]interface{}, len(r.PolicyDiffs))
+			}
+			removed, added := diff.Lines()
+			dr.Diffs[attr] = [2]interface{}{removed, added}
 		}
 
 		// Use AclDiff to indicate "other diffs exist" (same pattern as EC2)
 		if r.AssumeRolePolicyDiff || r.MaxSessionDiff || r.DescriptionDiff ||
-			r.PolicyDocumentDiff || r.PathDiff || r.AttachedPoliciesDiff || r.MembersDiff {
+			r.PolicyDocumentDiff || r.PathDiff || r.AttachedPoliciesDiff || r.MembersDiff ||
+			r.InlinePoliciesDiff || r.PermissionsBoundaryDiff || r.RolesDiff {
 			dr.AclDiff = true
 		}
 
 		results = append(results, dr)
 	}
 
-	return results, nil
+	return markUnknown(results, iamPlanned(plans), lives.Errors), nil
+}
+
+// iamPlanned lists the planned IAM resources in the order of
+// IAMPlanResources.Names.
+func iamPlanned(plans *models.IAMPlanResources) []plannedResource {
+	planned := make([]plannedResource, 0, plans.TotalCount())
+	for _, r := range plans.Roles {
+		planned = append(planned, plannedResource{resourceType: "aws_iam_role", name: r.RoleName, address: r.TerraformAddress})
+	}
+	for _, u := range plans.Users {
+		planned = append(planned, plannedResource{resourceType: "aws_iam_user", name: u.UserName, address: u.TerraformAddress})
+	}
+	for _, p := range plans.Policies {
+		planned = append(planned, plannedResource{resourceType: "aws_iam_policy", name: p.PolicyName, address: p.TerraformAddress})
+	}
+	for _, g := range plans.Groups {
+		planned = append(planned, plannedResource{resourceType: "aws_iam_group", name: g.GroupName, address: g.TerraformAddress})
+	}
+	for _, ip := range plans.InstanceProfiles {
+		planned = append(planned, plannedResource{resourceType: "aws_iam_instance_profile", name: ip.InstanceProfileName, address: ip.TerraformAddress})
+	}
+	return planned
 }
 
 // DetectAllIAMDrift performs drift detection across all planned IAM resources.
@@ -168,6 +223,18 @@ func DetectAllIAMDrift(plans *models.IAMPlanResources, lives *models.IAMLiveStat
 		}
 	}
 
+	// Detect instance profile drift
+	profileMap := make(map[string]*models.IAMInstanceProfile, len(lives.InstanceProfiles))
+	for i := range lives.InstanceProfiles {
+		profileMap[lives.InstanceProfiles[i].InstanceProfileName] = &lives.InstanceProfiles[i]
+	}
+	for _, p := range plans.InstanceProfiles {
+		dr := DetectIAMInstanceProfileDrift(p, profileMap[p.InstanceProfileName])
+		if dr.HasAnyDrift() {
+			results = append(results, dr)
+		}
+	}
+
 	return results
 }
 
@@ -186,9 +253,10 @@ func DetectIAMRoleDrift(plan models.IAMRole, actual *models.IAMRole) IAMDriftRes
 		return res
 	}
 
-	// Trust policy comparison (normalize JSON for comparison)
-	if plan.AssumeRolePolicy != "" && !jsonEqual(plan.AssumeRolePolicy, actual.AssumeRolePolicy) {
+	// Trust policy comparison (semantic policy document comparison)
+	if plan.AssumeRolePolicy != "" && !iampolicy.Equal(plan.AssumeRolePolicy, actual.AssumeRolePolicy) {
 		res.AssumeRolePolicyDiff = true
+		res.addPolicyDiff("assume_role_policy", plan.AssumeRolePolicy, actual.AssumeRolePolicy)
 	}
 
 	// Max session duration
@@ -207,10 +275,23 @@ func DetectIAMRoleDrift(plan models.IAMRole, actual *models.IAMRole) IAMDriftRes
 	}
 
 	// Attached policies
-	if len(plan.AttachedPolicies) > 0 && !stringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
+	if len(plan.AttachedPolicies) > 0 && !StringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
 		res.AttachedPoliciesDiff = true
 	}
 
+	// Inline policies (always compared unless some are unknown at plan
+	// time: the plan manages this principal, so a policy added outside
+	// Terraform is drift even if none are planned)
+	if !plan.InlinePoliciesUnknown && !InlinePoliciesEqual(plan.InlinePolicies, actual.InlinePolicies) {
+		res.InlinePoliciesDiff = true
+		res.addInlinePolicyDiffs(plan.InlinePolicies, actual.InlinePolicies)
+	}
+
+	// Permissions boundary
+	if plan.PermissionsBoundary != "" && plan.PermissionsBoundary != actual.PermissionsBoundary {
+		res.PermissionsBoundaryDiff = true
+	}
+
 	// Tags
 	compareTags(plan.Tags, actual.Tags, res.TagDiffs, res.ExtraTags)
 
@@ -238,10 +319,23 @@ func DetectIAMUserDrift(plan models.IAMUser, actual *models.IAMUser) IAMDriftRes
 	}
 
 	// Attached policies
-	if len(plan.AttachedPolicies) > 0 && !stringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
+	if len(plan.AttachedPolicies) > 0 && !StringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
 		res.AttachedPoliciesDiff = true
 	}
 
+	// Inline policies (always compared unless some are unknown at plan
+	// time: the plan manages this principal, so a policy added outside
+	// Terraform is drift even if none are planned)
+	if !plan.InlinePoliciesUnknown && !InlinePoliciesEqual(plan.InlinePolicies, actual.InlinePolicies) {
+		res.InlinePoliciesDiff = true
+		res.addInlinePolicyDiffs(plan.InlinePolicies, actual.InlinePolicies)
+	}
+
+	// Permissions boundary
+	if plan.PermissionsBoundary != "" && plan.PermissionsBoundary != actual.PermissionsBoundary {
+		res.PermissionsBoundaryDiff = true
+	}
+
 	// Tags
 	compareTags(plan.Tags, actual.Tags, res.TagDiffs, res.ExtraTags)
 
@@ -263,9 +357,10 @@ func DetectIAMPolicyDrift(plan models.IAMPolicy, actual *models.IAMPolicy) IAMDr
 		return res
 	}
 
-	// Policy document comparison (normalize JSON)
-	if plan.PolicyDocument != "" && !jsonEqual(plan.PolicyDocument, actual.PolicyDocument) {
+	// Policy document comparison (semantic policy document comparison)
+	if plan.PolicyDocument != "" && !iampolicy.Equal(plan.PolicyDocument, actual.PolicyDocument) {
 		res.PolicyDocumentDiff = true
+		res.addPolicyDiff("policy_document", plan.PolicyDocument, actual.PolicyDocument)
 	}
 
 	// Description
@@ -305,18 +400,95 @@ func DetectIAMGroupDrift(plan models.IAMGroup, actual *models.IAMGroup) IAMDrift
 	}
 
 	// Attached policies
-	if len(plan.AttachedPolicies) > 0 && !stringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
+	if len(plan.AttachedPolicies) > 0 && !StringSlicesEqual(plan.AttachedPolicies, actual.AttachedPolicies) {
 		res.AttachedPoliciesDiff = true
 	}
 
 	// Members
-	if len(plan.Members) > 0 && !stringSlicesEqual(plan.Members, actual.Members) {
+	if len(plan.Members) > 0 && !StringSlicesEqual(plan.Members, actual.Members) {
 		res.MembersDiff = true
 	}
 
+	// Inline policies (always compared unless some are unknown at plan
+	// time: the plan manages this principal, so a policy added outside
+	// Terraform is drift even if none are planned)
+	if !plan.InlinePoliciesUnknown && !InlinePoliciesEqual(plan.InlinePolicies, actual.InlinePolicies) {
+		res.InlinePoliciesDiff = true
+		res.addInlinePolicyDiffs(plan.InlinePolicies, actual.InlinePolicies)
+	}
+
+	return res
+}
+
+// DetectIAMInstanceProfileDrift compares a single planned instance profile against its actual AWS state.
+func DetectIAMInstanceProfileDrift(plan models.IAMInstanceProfile, actual *models.IAMInstanceProfile) IAMDriftResult {
+	res := IAMDriftResult{
+		ResourceType:     "instance_profile",
+		ResourceName:     plan.InstanceProfileName,
+		TerraformAddress: plan.TerraformAddress,
+		TagDiffs:         make(map[string][2]string),
+		ExtraTags:        make(map[string]string),
+	}
+
+	if actual == nil {
+		res.Missing = true
+		return res
+	}
+
+	// Path
+	if plan.Path != "" && plan.Path != actual.Path {
+		res.PathDiff = true
+	}
+
+	// Roles
+	if len(plan.Roles) > 0 && !StringSlicesEqual(plan.Roles, actual.Roles) {
+		res.RolesDiff = true
+	}
+
+	// Tags
+	compareTags(plan.Tags, actual.Tags, res.TagDiffs, res.ExtraTags)
+
 	return res
 }
 
+// InlinePoliciesEqual compares inline policies by name. Documents are
+// compared semantically, and a policy present on only one side is a difference.
+func InlinePoliciesEqual(plan, actual map[string]string) bool {
+	if len(plan) != len(actual) {
+		return false
+	}
+	for name, doc := range plan {
+		liveDoc, ok := actual[name]
+		if !ok || !iampolicy.Equal(doc, liveDoc) {
+			return false
+		}
+	}
+	return true
+}
+
+// addPolicyDiff records the statement-level diff of a policy document
+// attribute. Nothing is recorded if either document cannot be parsed.
+func (r *IAMDriftResult) addPolicyDiff(attr, plan, actual string) {
+	diff, err := iampolicy.DiffDocuments(plan, actual)
+	if err != nil || diff.Empty() {
+		return
+	}
+	if r.PolicyDiffs == nil {
+		r.PolicyDiffs = make(map[string]iampolicy.Diff)
+	}
+	r.PolicyDiffs[attr] = diff
+}
+
+// addInlinePolicyDiffs records statement-level diffs for inline policies
+// present on both sides with different documents.
+func (r *IAMDriftResult) addInlinePolicyDiffs(plan, actual map[string]string) {
+	for name, doc := range plan {
+		if liveDoc, ok := actual[name]; ok && !iampolicy.Equal(doc, liveDoc) {
+			r.addPolicyDiff("inline_policies."+name, doc, liveDoc)
+		}
+	}
+}
+
 // compareTags detects tag differences and extra tags between planned and actual state.
 func compareTags(plan, actual map[string]string, diffs map[string][2]string, extras map[string]string) {
 	for k, v := range plan {
@@ -2,10 +2,30 @@
 //
 // The package uses a plugin architecture where each AWS service (S3, EC2, IAM, etc.)
 // implements the Detector interface to provide consistent drift detection behavior.
+//
+// Architecture:
+//
+//	┌─────────────────┐     ┌─────────────────┐
+//	│  Terraform Plan │────▶│   Detector      │
+//	│    (parsed)     │     │                 │
+//	└─────────────────┘     │  Compare and    │
+//	                        │  identify diffs │
+//	┌─────────────────┐     │                 │
+//	│   AWS Live      │────▶│                 │
+//	│    State        │     └────────┬────────┘
+//	└─────────────────┘              │
+//	                                 ▼
+//	                        ┌─────────────────┐
+//	                        │  DriftResult[]  │
+//	                        └─────────────────┘
+//
+// Results are rendered by the formatters in the output package.
 package detector
 
 import (
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
 )
 
 // Resource represents a generic cloud resource that can be compared for drift.
@@ -35,9 +55,21 @@ type DriftInfo struct {
 	// ResourceName is the human-readable name.
 	ResourceName string `json:"resource_name"`
 
+	// ResourceAddress is the Terraform resource address (e.g.,
+	// "aws_instance.web"), if the plan provides one.
+	ResourceAddress string `json:"resource_address,omitempty"`
+
+	// Location is where the resource is declared in the Terraform
+	// configuration. It is only set when the configuration was parsed.
+	Location *tfconfig.Location `json:"location,omitempty"`
+
 	// Missing is true if the resource exists in the plan but not in AWS.
 	Missing bool `json:"missing"`
 
+	// Unknown is true if the resource's live state could not be fetched,
+	// so drift could not be determined.
+	Unknown bool `json:"unknown,omitempty"`
+
 	// Diffs contains attribute-level differences.
 	// Key is the attribute name, value is [expected, actual].
 	Diffs map[string][2]interface{} `json:"diffs,omitempty"`
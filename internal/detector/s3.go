@@ -1,7 +1,13 @@
 package detector
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/inayathulla/cloudrift/internal/aws"
@@ -17,9 +23,25 @@ type DriftResult struct {
 	// BucketName is the name of the S3 bucket being compared.
 	BucketName string
 
+	// ResourceType is the Terraform resource type (e.g., "aws_s3_bucket").
+	ResourceType string
+
+	// ResourceID is the AWS identifier of the resource when it differs
+	// from its name (e.g., the EC2 instance ID). Fetch errors of such
+	// resources are recorded under this identifier.
+	ResourceID string
+
+	// TerraformAddress is the planned resource's address (e.g.,
+	// "aws_s3_bucket.logs"), if the plan provides one.
+	TerraformAddress string
+
 	// Missing is true if the bucket exists in the plan but not in AWS.
 	Missing bool
 
+	// Unknown is true if the bucket's live state could not be fetched.
+	// Unknown results carry no attribute diffs.
+	Unknown bool
+
 	// AclDiff is true if the bucket ACL differs.
 	AclDiff bool
 
@@ -32,7 +54,8 @@ type DriftResult struct {
 	// VersioningDiff is true if versioning configuration differs.
 	VersioningDiff bool
 
-	// EncryptionDiff is true if encryption algorithm differs.
+	// EncryptionDiff is true if the encryption algorithm, KMS key or
+	// bucket key setting differs.
 	EncryptionDiff bool
 
 	// LoggingDiff is true if access logging configuration differs.
@@ -43,6 +66,27 @@ type DriftResult struct {
 
 	// LifecycleDiff is true if lifecycle rules differ.
 	LifecycleDiff bool
+
+	// PolicyDiff is true if the bucket policy document differs.
+	PolicyDiff bool
+
+	// OwnershipDiff is true if the object ownership setting differs.
+	OwnershipDiff bool
+
+	// CORSDiff is true if CORS rules differ.
+	CORSDiff bool
+
+	// ReplicationDiff is true if the replication configuration differs.
+	ReplicationDiff bool
+
+	// ObjectLockDiff is true if the object lock configuration differs.
+	ObjectLockDiff bool
+
+	// Diffs maps attribute names to [expected, actual] values for attributes
+	// the detector reports in detail, such as IAM policy statements or the
+	// S3 bucket policy, CORS rules and replication. They are carried into
+	// DriftInfo.Diffs as-is.
+	Diffs map[string][2]interface{}
 }
 
 // S3DriftDetector implements drift detection for AWS S3 buckets.
@@ -60,18 +104,23 @@ func NewS3DriftDetector(cfg sdkaws.Config) *S3DriftDetector {
 
 // FetchLiveState retrieves the current state of all S3 buckets from AWS.
 //
+// Parameters:
+//   - ctx: context for cancellation; buckets not fetched in time are reported as Unknown
+//
 // Returns:
-//   - interface{}: []models.S3Bucket containing all bucket configurations
+//   - interface{}: *models.S3LiveState containing bucket configurations and fetch errors
 //   - error: if the AWS API call fails
-func (d *S3DriftDetector) FetchLiveState() (interface{}, error) {
-	return aws.FetchS3Buckets(d.cfg)
+func (d *S3DriftDetector) FetchLiveState(ctx context.Context) (interface{}, error) {
+	return aws.FetchS3Buckets(ctx, d.cfg)
 }
 
 // DetectDrift compares Terraform-planned bucket configurations against live AWS state.
 //
 // Parameters:
 //   - plan: []models.S3Bucket from the Terraform plan
-//   - live: []models.S3Bucket from the live AWS state
+//   - live: *models.S3LiveState (or []models.S3Bucket) from the live AWS state
+//
+// Buckets whose live state could not be fetched are reported as Unknown.
 //
 // Returns:
 //   - []DriftResult: drift results for buckets with detected differences
@@ -81,23 +130,43 @@ func (d *S3DriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, er
 	if !ok {
 		return nil, fmt.Errorf("plan type mismatch")
 	}
-	lives, ok := live.([]models.S3Bucket)
+	lives, errs, ok := S3LiveBuckets(live)
 	if !ok {
 		return nil, fmt.Errorf("live type mismatch")
 	}
-	return DetectAllS3Drift(plans, lives), nil
+
+	planned := make([]plannedResource, len(plans))
+	for i, p := range plans {
+		planned[i] = plannedResource{resourceType: "aws_s3_bucket", name: p.Name, address: p.Id}
+	}
+	return markUnknown(DetectAllS3Drift(plans, lives), planned, errs), nil
+}
+
+// S3LiveBuckets unpacks S3 live state, accepting both *models.S3LiveState
+// and a plain []models.S3Bucket.
+func S3LiveBuckets(live interface{}) ([]models.S3Bucket, []models.FetchError, bool) {
+	switch l := live.(type) {
+	case *models.S3LiveState:
+		return l.Buckets, l.Errors, true
+	case []models.S3Bucket:
+		return l, nil, true
+	default:
+		return nil, nil, false
+	}
 }
 
 // DetectS3Drift compares a single planned bucket against its actual AWS state.
 //
 // The comparison checks the following attributes:
-//   - ACL
+//   - ACL (canned ACL, or the grant list when the plan defines grants)
 //   - Tags (missing, mismatched, and extra)
 //   - Versioning
 //   - Encryption (only if plan specifies an algorithm)
 //   - Logging configuration
 //   - Public Access Block settings
 //   - Lifecycle rules (only if plan defines rules)
+//   - Bucket policy (normalized JSON), object ownership, CORS, replication
+//     and object lock (each only if the plan configures it)
 //
 // Parameters:
 //   - plan: the Terraform-planned bucket configuration
@@ -107,9 +176,11 @@ func (d *S3DriftDetector) DetectDrift(plan, live interface{}) ([]DriftResult, er
 //   - DriftResult: detailed drift information for the bucket
 func DetectS3Drift(plan models.S3Bucket, actual *models.S3Bucket) DriftResult {
 	res := DriftResult{
-		BucketName: plan.Name,
-		TagDiffs:   make(map[string][2]string),
-		ExtraTags:  make(map[string]string),
+		BucketName:       plan.Name,
+		ResourceType:     "aws_s3_bucket",
+		TerraformAddress: plan.Id,
+		TagDiffs:         make(map[string][2]string),
+		ExtraTags:        make(map[string]string),
 	}
 	if actual == nil {
 		res.Missing = true
@@ -117,7 +188,7 @@ func DetectS3Drift(plan models.S3Bucket, actual *models.S3Bucket) DriftResult {
 	}
 
 	// ACL diff
-	if plan.Acl != actual.Acl {
+	if !aclEqual(plan, *actual) {
 		res.AclDiff = true
 	}
 
@@ -140,8 +211,12 @@ func DetectS3Drift(plan models.S3Bucket, actual *models.S3Bucket) DriftResult {
 	}
 
 	// Encryption diff (only if plan specified an algorithm)
-	if plan.EncryptionAlgorithm != "" && plan.EncryptionAlgorithm != actual.EncryptionAlgorithm {
-		res.EncryptionDiff = true
+	if plan.EncryptionAlgorithm != "" {
+		if plan.EncryptionAlgorithm != actual.EncryptionAlgorithm ||
+			!kmsKeyEqual(plan.KMSKeyID, actual.KMSKeyID) ||
+			plan.BucketKeyEnabled != actual.BucketKeyEnabled {
+			res.EncryptionDiff = true
+		}
 	}
 
 	// Logging diff — compare regardless of plan fields
@@ -163,9 +238,48 @@ func DetectS3Drift(plan models.S3Bucket, actual *models.S3Bucket) DriftResult {
 		}
 	}
 
+	// Bucket policy diff (only if plan attaches a policy; compared as JSON)
+	if plan.Policy != "" && !jsonEqual(plan.Policy, actual.Policy) {
+		res.PolicyDiff = true
+		res.addDiff("policy", plan.Policy, actual.Policy)
+	}
+
+	// Object ownership diff (only if plan configures ownership controls)
+	if plan.ObjectOwnership != "" && plan.ObjectOwnership != actual.ObjectOwnership {
+		res.OwnershipDiff = true
+		res.addDiff("object_ownership", plan.ObjectOwnership, actual.ObjectOwnership)
+	}
+
+	// CORS diff (only if plan defines rules)
+	if len(plan.CORSRules) > 0 && !corsRulesEqual(plan.CORSRules, actual.CORSRules) {
+		res.CORSDiff = true
+		res.addDiff("cors_rules", plan.CORSRules, actual.CORSRules)
+	}
+
+	// Replication diff (only if plan configures replication)
+	if (plan.Replication.Role != "" || len(plan.Replication.Rules) > 0) &&
+		!replicationEqual(plan.Replication, actual.Replication) {
+		res.ReplicationDiff = true
+		res.addDiff("replication", plan.Replication, actual.Replication)
+	}
+
+	// Object lock diff (only if plan enables it; object lock cannot be turned off)
+	if plan.ObjectLock.Enabled && plan.ObjectLock != actual.ObjectLock {
+		res.ObjectLockDiff = true
+		res.addDiff("object_lock", plan.ObjectLock, actual.ObjectLock)
+	}
+
 	return res
 }
 
+// addDiff records the planned and live values of a drifted attribute.
+func (r *DriftResult) addDiff(attr string, planned, actual interface{}) {
+	if r.Diffs == nil {
+		r.Diffs = make(map[string][2]interface{})
+	}
+	r.Diffs[attr] = [2]interface{}{planned, actual}
+}
+
 // DetectAllS3Drift performs drift detection across all planned S3 buckets.
 //
 // This function builds a lookup map of live buckets for O(1) access, then
@@ -195,13 +309,38 @@ func DetectAllS3Drift(plans, lives []models.S3Bucket) []DriftResult {
 			dr.EncryptionDiff ||
 			dr.LoggingDiff ||
 			dr.PublicAccessBlockDiff ||
-			dr.LifecycleDiff {
+			dr.LifecycleDiff ||
+			dr.PolicyDiff ||
+			dr.OwnershipDiff ||
+			dr.CORSDiff ||
+			dr.ReplicationDiff ||
+			dr.ObjectLockDiff {
 			out = append(out, dr)
 		}
 	}
 	return out
 }
 
+// aclEqual reports whether a live bucket's ACL matches the plan.
+//
+// When the plan lists explicit grants, the normalized grant lists are
+// compared. Otherwise the planned canned ACL (defaulting to "private", the
+// AWS default when no ACL is set) is compared with the canned ACL derived
+// from the live grants. Live buckets with no recorded ACL are not compared.
+func aclEqual(plan, actual models.S3Bucket) bool {
+	if actual.Acl == "" && len(actual.AclGrants) == 0 {
+		return true
+	}
+	if len(plan.AclGrants) > 0 {
+		return slices.Equal(plan.AclGrants, actual.AclGrants)
+	}
+	want := plan.Acl
+	if want == "" {
+		want = "private"
+	}
+	return want == actual.Acl
+}
+
 // publicAccessBlockEqual compares two PublicAccessBlockConfig structs.
 func publicAccessBlockEqual(a, b models.PublicAccessBlockConfig) bool {
 	return a.BlockPublicAcls == b.BlockPublicAcls &&
@@ -210,18 +349,108 @@ func publicAccessBlockEqual(a, b models.PublicAccessBlockConfig) bool {
 		a.RestrictPublicBuckets == b.RestrictPublicBuckets
 }
 
-// lifecycleRulesEqual compares two slices of LifecycleRuleSummary.
+// lifecycleRulesEqual compares two lifecycle rule lists, matching rules by
+// ID so that rule order does not matter.
 func lifecycleRulesEqual(a, b []models.LifecycleRuleSummary) bool {
 	if len(a) != len(b) {
 		return false
 	}
-	for i := range a {
-		if a[i].ID != b[i].ID ||
-			a[i].Status != b[i].Status ||
-			a[i].Prefix != b[i].Prefix ||
-			a[i].ExpirationDays != b[i].ExpirationDays {
+	byID := make(map[string]models.LifecycleRuleSummary, len(b))
+	for _, r := range b {
+		byID[r.ID] = r
+	}
+	for _, r := range a {
+		if live, ok := byID[r.ID]; !ok || !LifecycleRuleEqual(r, live) {
+			return false
+		}
+	}
+	return true
+}
+
+// LifecycleRuleEqual compares the filter and every action of two lifecycle
+// rules. Transitions are compared as sets.
+func LifecycleRuleEqual(a, b models.LifecycleRuleSummary) bool {
+	return a.ID == b.ID &&
+		a.Status == b.Status &&
+		a.Prefix == b.Prefix &&
+		maps.Equal(a.FilterTags, b.FilterTags) &&
+		a.ObjectSizeGreaterThan == b.ObjectSizeGreaterThan &&
+		a.ObjectSizeLessThan == b.ObjectSizeLessThan &&
+		a.ExpirationDays == b.ExpirationDays &&
+		a.ExpirationDate == b.ExpirationDate &&
+		a.ExpiredObjectDeleteMarker == b.ExpiredObjectDeleteMarker &&
+		a.NoncurrentExpirationDays == b.NoncurrentExpirationDays &&
+		a.NewerNoncurrentVersions == b.NewerNoncurrentVersions &&
+		transitionsEqual(a.Transitions, b.Transitions) &&
+		transitionsEqual(a.NoncurrentTransitions, b.NoncurrentTransitions) &&
+		a.AbortIncompleteMultipartDays == b.AbortIncompleteMultipartDays
+}
+
+// transitionsEqual compares two transition lists without regard to order.
+func transitionsEqual(a, b []models.LifecycleTransition) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	models.SortLifecycleTransitions(a)
+	models.SortLifecycleTransitions(b)
+	return slices.Equal(a, b)
+}
+
+// kmsKeyEqual compares a planned KMS key with the live one. AWS reports the
+// key as a full ARN, while plans may use the bare key ID, so a live ARN
+// ending in "/<planned>" also matches. An unset planned key is not compared.
+func kmsKeyEqual(plan, actual string) bool {
+	return plan == "" || plan == actual || strings.HasSuffix(actual, "/"+plan)
+}
+
+// corsRulesEqual compares two CORS rule lists without regard to the order of
+// rules or of the values within each rule.
+func corsRulesEqual(a, b []models.CORSRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	key := func(r models.CORSRule) string {
+		return strings.Join([]string{
+			r.ID,
+			strings.Join(sortedCopy(r.AllowedHeaders), ","),
+			strings.Join(sortedCopy(r.AllowedMethods), ","),
+			strings.Join(sortedCopy(r.AllowedOrigins), ","),
+			strings.Join(sortedCopy(r.ExposeHeaders), ","),
+			strconv.Itoa(r.MaxAgeSeconds),
+		}, "|")
+	}
+	keys := make(map[string]int, len(a))
+	for _, r := range a {
+		keys[key(r)]++
+	}
+	for _, r := range b {
+		k := key(r)
+		if keys[k] == 0 {
 			return false
 		}
+		keys[k]--
 	}
 	return true
 }
+
+// replicationEqual compares two replication configurations, matching rules by ID.
+func replicationEqual(a, b models.ReplicationConfig) bool {
+	if a.Role != b.Role || len(a.Rules) != len(b.Rules) {
+		return false
+	}
+	byID := make(map[string]models.ReplicationRule, len(b.Rules))
+	for _, r := range b.Rules {
+		byID[r.ID] = r
+	}
+	for _, r := range a.Rules {
+		if live, ok := byID[r.ID]; !ok || live != r {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedCopy returns a sorted copy of a string slice.
+func sortedCopy(s []string) []string {
+	out := slices.Clone(s)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,82 @@
+package detector
+
+import "github.com/inayathulla/cloudrift/internal/models"
+
+// ResourceScope narrows a plan to the planned resource that corresponds to a
+// single live resource, and wraps that resource in a live state of its own.
+// Passing both to DetectDrift compares the resource before the rest of the
+// account has been fetched, with the same result as the full comparison.
+//
+// Buckets and IAM resources correspond by name. EC2 instances only
+// correspond by instance ID here: matching by Name tag depends on which
+// other instances exist, so it is left to the full comparison.
+//
+// Parameters:
+//   - plan: planned resources ([]models.S3Bucket, []models.EC2Instance or *models.IAMPlanResources)
+//   - resource: a live resource, as passed to aws.FetchTrace.Fetched
+//
+// Returns:
+//   - interface{}: the plan narrowed to the corresponding planned resource
+//   - interface{}: a live state holding only resource
+//   - bool: false if no planned resource corresponds to resource
+func ResourceScope(plan, resource interface{}) (interface{}, interface{}, bool) {
+	switch plans := plan.(type) {
+	case []models.S3Bucket:
+		if b, ok := resource.(models.S3Bucket); ok {
+			for _, p := range plans {
+				if p.Name == b.Name {
+					return []models.S3Bucket{p}, &models.S3LiveState{Buckets: []models.S3Bucket{b}}, true
+				}
+			}
+		}
+	case []models.EC2Instance:
+		if inst, ok := resource.(models.EC2Instance); ok {
+			for _, p := range plans {
+				if p.InstanceID != "" && p.InstanceID == inst.InstanceID {
+					return []models.EC2Instance{p}, &models.EC2LiveState{Instances: []models.EC2Instance{inst}}, true
+				}
+			}
+		}
+	case *models.IAMPlanResources:
+		return iamResourceScope(plans, resource)
+	}
+	return nil, nil, false
+}
+
+// iamResourceScope is ResourceScope for IAM resources, which correspond by
+// type and name.
+func iamResourceScope(plans *models.IAMPlanResources, resource interface{}) (interface{}, interface{}, bool) {
+	switch r := resource.(type) {
+	case models.IAMRole:
+		for _, p := range plans.Roles {
+			if p.RoleName == r.RoleName {
+				return &models.IAMPlanResources{Roles: []models.IAMRole{p}}, &models.IAMLiveState{Roles: []models.IAMRole{r}}, true
+			}
+		}
+	case models.IAMUser:
+		for _, p := range plans.Users {
+			if p.UserName == r.UserName {
+				return &models.IAMPlanResources{Users: []models.IAMUser{p}}, &models.IAMLiveState{Users: []models.IAMUser{r}}, true
+			}
+		}
+	case models.IAMPolicy:
+		for _, p := range plans.Policies {
+			if p.PolicyName == r.PolicyName {
+				return &models.IAMPlanResources{Policies: []models.IAMPolicy{p}}, &models.IAMLiveState{Policies: []models.IAMPolicy{r}}, true
+			}
+		}
+	case models.IAMGroup:
+		for _, p := range plans.Groups {
+			if p.GroupName == r.GroupName {
+				return &models.IAMPlanResources{Groups: []models.IAMGroup{p}}, &models.IAMLiveState{Groups: []models.IAMGroup{r}}, true
+			}
+		}
+	case models.IAMInstanceProfile:
+		for _, p := range plans.InstanceProfiles {
+			if p.InstanceProfileName == r.InstanceProfileName {
+				return &models.IAMPlanResources{InstanceProfiles: []models.IAMInstanceProfile{p}}, &models.IAMLiveState{InstanceProfiles: []models.IAMInstanceProfile{r}}, true
+			}
+		}
+	}
+	return nil, nil, false
+}
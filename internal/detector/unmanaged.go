@@ -0,0 +1,253 @@
+package detector
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// UnmanagedResource is a live AWS resource that no planned resource refers
+// to, such as a bucket or role created by hand outside Terraform.
+type UnmanagedResource struct {
+	// ResourceType is the Terraform resource type (e.g., "aws_s3_bucket").
+	ResourceType string `json:"resource_type"`
+
+	// ResourceID is the AWS identifier: bucket name, instance ID or IAM name.
+	ResourceID string `json:"resource_id"`
+
+	// ResourceName is the display name: the Name tag for instances, or the
+	// resource ID for everything else.
+	ResourceName string `json:"resource_name"`
+
+	// Tags contains the resource tags, if any.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Exclusion removes matching live resources from the unmanaged inventory.
+//
+// Every field that is set must match. A rule with no Name or Tag matches
+// nothing, so a bare resource type cannot silently hide a whole service.
+type Exclusion struct {
+	// ResourceType limits the rule to one Terraform resource type. Empty
+	// matches any type.
+	ResourceType string `mapstructure:"resource_type" json:"resource_type,omitempty"`
+
+	// Name is a glob pattern (path.Match syntax) matched against the
+	// resource ID and the resource name.
+	Name string `mapstructure:"name" json:"name,omitempty"`
+
+	// Tag is "<key>" to match any resource carrying the tag, or
+	// "<key>=<pattern>" to also match its value against a glob pattern.
+	Tag string `mapstructure:"tag" json:"tag,omitempty"`
+}
+
+// ParseExclusion parses an exclusion rule given on the command line:
+// "name:<pattern>" or "tag:<key>[=<pattern>]".
+func ParseExclusion(s string) (Exclusion, error) {
+	kind, value, ok := strings.Cut(s, ":")
+	if !ok || value == "" {
+		return Exclusion{}, fmt.Errorf("invalid exclusion %q: expected name:<pattern> or tag:<key>[=<pattern>]", s)
+	}
+	var e Exclusion
+	switch kind {
+	case "name":
+		e.Name = value
+	case "tag":
+		e.Tag = value
+	default:
+		return Exclusion{}, fmt.Errorf("invalid exclusion %q: unknown kind %q (expected name or tag)", s, kind)
+	}
+	return e, e.Validate()
+}
+
+// Validate reports an error if the rule matches nothing or has a malformed pattern.
+func (e Exclusion) Validate() error {
+	if e.Name == "" && e.Tag == "" {
+		return fmt.Errorf("exclusion must set a name or tag")
+	}
+	if _, err := path.Match(e.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %w", e.Name, err)
+	}
+	_, value, _ := strings.Cut(e.Tag, "=")
+	if _, err := path.Match(value, ""); err != nil {
+		return fmt.Errorf("invalid tag pattern %q: %w", e.Tag, err)
+	}
+	return nil
+}
+
+// Matches reports whether the rule excludes r.
+func (e Exclusion) Matches(r UnmanagedResource) bool {
+	if e.Name == "" && e.Tag == "" {
+		return false
+	}
+	if e.ResourceType != "" && e.ResourceType != r.ResourceType {
+		return false
+	}
+	if e.Name != "" && !globMatch(e.Name, r.ResourceID) && !globMatch(e.Name, r.ResourceName) {
+		return false
+	}
+	if e.Tag != "" {
+		key, pattern, hasValue := strings.Cut(e.Tag, "=")
+		value, ok := r.Tags[key]
+		if !ok || (hasValue && !globMatch(pattern, value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// globMatch reports whether s matches pattern. Malformed patterns match nothing.
+func globMatch(pattern, s string) bool {
+	ok, err := path.Match(pattern, s)
+	return err == nil && ok
+}
+
+// FindUnmanaged lists the live resources that no planned resource refers to.
+//
+// S3 buckets and IAM resources are matched by name. EC2 instances are
+// matched by instance ID or Name tag, the same way drift detection pairs
+// them; terminated instances are ignored. AWS service-linked roles are never
+// reported, since they are created and owned by AWS services.
+//
+// Parameters:
+//   - plan: planned resources ([]models.S3Bucket, []models.EC2Instance or *models.IAMPlanResources)
+//   - live: the live state returned by the matching detector's FetchLiveState
+//   - exclusions: rules removing matching resources from the result
+//
+// Returns:
+//   - []UnmanagedResource: unmanaged resources sorted by type and ID
+//   - error: if the plan and live state types are not recognised
+func FindUnmanaged(plan, live interface{}, exclusions []Exclusion) ([]UnmanagedResource, error) {
+	var found []UnmanagedResource
+
+	switch p := plan.(type) {
+	case []models.S3Bucket:
+		buckets, _, ok := S3LiveBuckets(live)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.S3LiveState")
+		}
+		planned := make(map[string]bool, len(p))
+		for _, b := range p {
+			planned[b.Name] = true
+		}
+		for _, b := range buckets {
+			if !planned[b.Name] {
+				found = append(found, UnmanagedResource{ResourceType: "aws_s3_bucket", ResourceID: b.Name, ResourceName: b.Name, Tags: b.Tags})
+			}
+		}
+
+	case []models.EC2Instance:
+		instances, _, ok := EC2LiveInstances(live)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.EC2LiveState")
+		}
+		plannedIDs := make(map[string]bool, len(p))
+		plannedNames := make(map[string]bool, len(p))
+		for _, inst := range p {
+			if inst.InstanceID != "" {
+				plannedIDs[inst.InstanceID] = true
+			}
+			if name := inst.Tags["Name"]; name != "" {
+				plannedNames[name] = true
+			}
+		}
+		for _, inst := range instances {
+			if inst.State == "terminated" || inst.State == "shutting-down" {
+				continue
+			}
+			if plannedIDs[inst.InstanceID] || plannedNames[inst.Tags["Name"]] {
+				continue
+			}
+			found = append(found, UnmanagedResource{ResourceType: "aws_instance", ResourceID: inst.InstanceID, ResourceName: inst.Name(), Tags: inst.Tags})
+		}
+
+	case *models.IAMPlanResources:
+		l, ok := live.(*models.IAMLiveState)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.IAMLiveState")
+		}
+		add := func(resourceType, name string, planned map[string]bool, tags map[string]string) {
+			if !planned[name] {
+				found = append(found, UnmanagedResource{ResourceType: resourceType, ResourceID: name, ResourceName: name, Tags: tags})
+			}
+		}
+
+		roles := make(map[string]bool, len(p.Roles))
+		for _, r := range p.Roles {
+			roles[r.RoleName] = true
+		}
+		for _, r := range l.Roles {
+			if !isServiceLinkedRole(r) {
+				add("aws_iam_role", r.RoleName, roles, r.Tags)
+			}
+		}
+
+		users := make(map[string]bool, len(p.Users))
+		for _, u := range p.Users {
+			users[u.UserName] = true
+		}
+		for _, u := range l.Users {
+			add("aws_iam_user", u.UserName, users, u.Tags)
+		}
+
+		policies := make(map[string]bool, len(p.Policies))
+		for _, pol := range p.Policies {
+			policies[pol.PolicyName] = true
+		}
+		for _, pol := range l.Policies {
+			add("aws_iam_policy", pol.PolicyName, policies, pol.Tags)
+		}
+
+		groups := make(map[string]bool, len(p.Groups))
+		for _, g := range p.Groups {
+			groups[g.GroupName] = true
+		}
+		for _, g := range l.Groups {
+			add("aws_iam_group", g.GroupName, groups, nil)
+		}
+
+		profiles := make(map[string]bool, len(p.InstanceProfiles))
+		for _, ip := range p.InstanceProfiles {
+			profiles[ip.InstanceProfileName] = true
+		}
+		for _, ip := range l.InstanceProfiles {
+			add("aws_iam_instance_profile", ip.InstanceProfileName, profiles, ip.Tags)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported plan type %T", plan)
+	}
+
+	out := make([]UnmanagedResource, 0, len(found))
+	for _, r := range found {
+		if !excluded(r, exclusions) {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ResourceType != out[j].ResourceType {
+			return out[i].ResourceType < out[j].ResourceType
+		}
+		return out[i].ResourceID < out[j].ResourceID
+	})
+	return out, nil
+}
+
+// excluded reports whether any exclusion rule matches r.
+func excluded(r UnmanagedResource, exclusions []Exclusion) bool {
+	for _, e := range exclusions {
+		if e.Matches(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isServiceLinkedRole reports whether a role is an AWS service-linked role.
+// The fetcher already skips them; this also covers recorded snapshots.
+func isServiceLinkedRole(r models.IAMRole) bool {
+	return strings.HasPrefix(r.Path, "/aws-service-role/")
+}
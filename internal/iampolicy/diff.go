@@ -0,0 +1,375 @@
+package iampolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Entry is a single value of a statement element: one action, one resource,
+// one principal or one condition value.
+type Entry struct {
+	// Element names the statement element the value belongs to: "Action",
+	// "NotAction", "Resource", "NotResource", "Principal.<type>",
+	// "NotPrincipal.<type>" or "Condition.<operator>.<key>".
+	Element string `json:"element"`
+
+	// Value is the element value (e.g., "s3:GetObject").
+	Value string `json:"value"`
+
+	// Wildcard is true if the value contains a "*" or "?" wildcard.
+	Wildcard bool `json:"wildcard,omitempty"`
+}
+
+// String returns the entry as "<element> <value>".
+func (e Entry) String() string {
+	return e.Element + " " + e.Value
+}
+
+// ChangeKind describes how a statement differs between two documents.
+type ChangeKind string
+
+const (
+	// StatementAdded marks a statement present only in the actual document.
+	StatementAdded ChangeKind = "added"
+
+	// StatementRemoved marks a statement present only in the expected document.
+	StatementRemoved ChangeKind = "removed"
+
+	// StatementModified marks a statement present in both documents with
+	// different entries.
+	StatementModified ChangeKind = "modified"
+)
+
+// StatementChange describes one statement that differs between an expected
+// and an actual document.
+type StatementChange struct {
+	// Kind is how the statement changed.
+	Kind ChangeKind `json:"kind"`
+
+	// Sid is the statement identifier, if either side has one.
+	Sid string `json:"sid,omitempty"`
+
+	// ExpectedEffect is the effect in the expected document. Empty for added statements.
+	ExpectedEffect string `json:"expected_effect,omitempty"`
+
+	// ActualEffect is the effect in the actual document. Empty for removed statements.
+	ActualEffect string `json:"actual_effect,omitempty"`
+
+	// Added contains entries present only in the actual statement.
+	Added []Entry `json:"added,omitempty"`
+
+	// Removed contains entries present only in the expected statement.
+	Removed []Entry `json:"removed,omitempty"`
+}
+
+// Diff is the statement-level difference between an expected and an actual
+// policy document.
+type Diff struct {
+	// ExpectedVersion and ActualVersion are set only if the policy
+	// language versions differ.
+	ExpectedVersion string `json:"expected_version,omitempty"`
+	ActualVersion   string `json:"actual_version,omitempty"`
+
+	// Statements lists the statements that were added, removed or modified.
+	Statements []StatementChange `json:"statements,omitempty"`
+}
+
+// Empty reports whether the documents have no differences.
+func (d Diff) Empty() bool {
+	return d.ExpectedVersion == d.ActualVersion && len(d.Statements) == 0
+}
+
+// DiffDocuments compares two JSON policy documents statement by statement.
+//
+// Parameters:
+//   - expected: the planned policy document JSON
+//   - actual: the live policy document JSON
+//
+// Returns:
+//   - Diff: the statement-level differences
+//   - error: if either document cannot be parsed
+func DiffDocuments(expected, actual string) (Diff, error) {
+	de, err := Parse(expected)
+	if err != nil {
+		return Diff{}, err
+	}
+	da, err := Parse(actual)
+	if err != nil {
+		return Diff{}, err
+	}
+	return Compare(de, da), nil
+}
+
+// Compare returns the statement-level differences between two documents.
+//
+// Equivalent statements are matched first and dropped. The remaining
+// statements are paired by Sid, then by the number of entries they share;
+// paired statements are reported as modified with the entries added and
+// removed. Unpaired statements are reported as added or removed as a whole.
+func Compare(expected, actual *Document) Diff {
+	var d Diff
+	if expected.Version != actual.Version {
+		d.ExpectedVersion, d.ActualVersion = expected.Version, actual.Version
+	}
+
+	exp, act := unmatched(expected.Statements, actual.Statements)
+	usedExp := make([]bool, len(exp))
+	usedAct := make([]bool, len(act))
+
+	pair := func(i, j int) {
+		usedExp[i], usedAct[j] = true, true
+		if c := modified(exp[i], act[j]); len(c.Added) > 0 || len(c.Removed) > 0 || c.ExpectedEffect != c.ActualEffect {
+			d.Statements = append(d.Statements, c)
+		}
+	}
+
+	// Pair statements that carry the same Sid
+	for i, e := range exp {
+		if e.Sid == "" {
+			continue
+		}
+		for j, a := range act {
+			if !usedAct[j] && a.Sid == e.Sid {
+				pair(i, j)
+				break
+			}
+		}
+	}
+
+	// Pair the remaining statements by shared entries, best match first
+	type candidate struct{ i, j, shared int }
+	var candidates []candidate
+	for i := range exp {
+		for j := range act {
+			if usedExp[i] || usedAct[j] {
+				continue
+			}
+			if n := sharedEntries(exp[i], act[j]); n > 0 {
+				candidates = append(candidates, candidate{i, j, n})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(x, y int) bool {
+		return candidates[x].shared > candidates[y].shared
+	})
+	for _, c := range candidates {
+		if !usedExp[c.i] && !usedAct[c.j] {
+			pair(c.i, c.j)
+		}
+	}
+
+	for i, s := range exp {
+		if !usedExp[i] {
+			d.Statements = append(d.Statements, StatementChange{
+				Kind:           StatementRemoved,
+				Sid:            s.Sid,
+				ExpectedEffect: s.Effect,
+				Removed:        entries(s),
+			})
+		}
+	}
+	for j, s := range act {
+		if !usedAct[j] {
+			d.Statements = append(d.Statements, StatementChange{
+				Kind:         StatementAdded,
+				Sid:          s.Sid,
+				ActualEffect: s.Effect,
+				Added:        entries(s),
+			})
+		}
+	}
+	return d
+}
+
+// Lines renders the diff as two lists of human-readable lines: entries
+// present only in the expected document, and entries present only in the
+// actual document. Each line is prefixed with the statement's Sid (if any)
+// and effect, and wildcard values are marked with "(wildcard)".
+//
+// Returns:
+//   - removed: lines for entries missing from the actual document
+//   - added: lines for entries not in the expected document
+func (d Diff) Lines() (removed, added []string) {
+	if d.ExpectedVersion != d.ActualVersion {
+		removed = append(removed, "Version "+valueOrEmpty(d.ExpectedVersion))
+		added = append(added, "Version "+valueOrEmpty(d.ActualVersion))
+	}
+	for _, c := range d.Statements {
+		if c.Kind == StatementModified && c.ExpectedEffect != c.ActualEffect {
+			removed = append(removed, c.label("")+"Effect "+c.ExpectedEffect)
+			added = append(added, c.label("")+"Effect "+c.ActualEffect)
+		}
+		for _, e := range c.Removed {
+			removed = append(removed, c.line(c.ExpectedEffect, e))
+		}
+		for _, e := range c.Added {
+			added = append(added, c.line(c.ActualEffect, e))
+		}
+	}
+	return removed, added
+}
+
+// line formats one entry of the statement for Lines.
+func (c StatementChange) line(effect string, e Entry) string {
+	s := c.label(effect) + e.String()
+	if e.Wildcard {
+		s += " (wildcard)"
+	}
+	return s
+}
+
+// label returns the "<sid>: <effect> " prefix for a line of the statement.
+func (c StatementChange) label(effect string) string {
+	var b strings.Builder
+	if c.Sid != "" {
+		b.WriteString(c.Sid + ": ")
+	}
+	if effect != "" {
+		b.WriteString(effect + " ")
+	}
+	return b.String()
+}
+
+// valueOrEmpty returns s, or "<empty>" if s is empty.
+func valueOrEmpty(s string) string {
+	if s == "" {
+		return "<empty>"
+	}
+	return s
+}
+
+// unmatched drops the statements that appear in both lists (counting
+// duplicates) and returns what is left of each.
+func unmatched(expected, actual []Statement) (exp, act []Statement) {
+	keys := make(map[string]int, len(actual))
+	for _, s := range actual {
+		keys[s.Key()]++
+	}
+	matched := make(map[string]int, len(actual))
+	for _, s := range expected {
+		k := s.Key()
+		if keys[k] > 0 {
+			keys[k]--
+			matched[k]++
+			continue
+		}
+		exp = append(exp, s)
+	}
+	for _, s := range actual {
+		k := s.Key()
+		if matched[k] > 0 {
+			matched[k]--
+			continue
+		}
+		act = append(act, s)
+	}
+	return exp, act
+}
+
+// modified builds the change between two paired statements.
+func modified(expected, actual Statement) StatementChange {
+	sid := actual.Sid
+	if sid == "" {
+		sid = expected.Sid
+	}
+	c := StatementChange{
+		Kind:           StatementModified,
+		Sid:            sid,
+		ExpectedEffect: expected.Effect,
+		ActualEffect:   actual.Effect,
+	}
+	exp, act := entries(expected), entries(actual)
+	expKeys := entryKeys(exp)
+	actKeys := entryKeys(act)
+	for _, e := range exp {
+		if !actKeys[entryKey(e)] {
+			c.Removed = append(c.Removed, e)
+		}
+	}
+	for _, e := range act {
+		if !expKeys[entryKey(e)] {
+			c.Added = append(c.Added, e)
+		}
+	}
+	return c
+}
+
+// sharedEntries counts the entries two statements have in common.
+func sharedEntries(a, b Statement) int {
+	keys := entryKeys(entries(a))
+	n := 0
+	for _, e := range entries(b) {
+		if keys[entryKey(e)] {
+			n++
+		}
+	}
+	return n
+}
+
+// entries flattens a statement into its element values, in a stable order.
+// The Effect is not included.
+func entries(s Statement) []Entry {
+	var out []Entry
+	out = appendPrincipals(out, "Principal", s.Principal)
+	out = appendPrincipals(out, "NotPrincipal", s.NotPrincipal)
+	out = appendEntries(out, "Action", s.Action)
+	out = appendEntries(out, "NotAction", s.NotAction)
+	out = appendEntries(out, "Resource", s.Resource)
+	out = appendEntries(out, "NotResource", s.NotResource)
+	for _, op := range sortedKeys(s.Condition) {
+		for _, key := range sortedKeys(s.Condition[op]) {
+			out = appendEntries(out, fmt.Sprintf("Condition.%s.%s", op, key), s.Condition[op][key])
+		}
+	}
+	return out
+}
+
+// appendPrincipals appends one entry per principal identifier.
+func appendPrincipals(out []Entry, element string, p map[string][]string) []Entry {
+	for _, typ := range sortedKeys(p) {
+		out = appendEntries(out, element+"."+typ, p[typ])
+	}
+	return out
+}
+
+// appendEntries appends one entry per value.
+func appendEntries(out []Entry, element string, values []string) []Entry {
+	for _, v := range values {
+		out = append(out, Entry{
+			Element:  element,
+			Value:    v,
+			Wildcard: strings.ContainsAny(v, "*?"),
+		})
+	}
+	return out
+}
+
+// entryKey identifies an entry for matching. Action names are matched
+// case-insensitively, as IAM does.
+func entryKey(e Entry) string {
+	v := e.Value
+	if e.Element == "Action" || e.Element == "NotAction" {
+		v = strings.ToLower(v)
+	}
+	return e.Element + "\x00" + v
+}
+
+// entryKeys returns the set of keys of the given entries.
+func entryKeys(list []Entry) map[string]bool {
+	keys := make(map[string]bool, len(list))
+	for _, e := range list {
+		keys[entryKey(e)] = true
+	}
+	return keys
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,258 @@
+// Package iampolicy models IAM policy documents so that they can be compared
+// by meaning rather than by text.
+//
+// IAM accepts several spellings of the same policy: a single statement or a
+// list of statements, a string or a one-element array for Action, Resource
+// and condition values, "*" or {"AWS": "*"} as a principal, and statements
+// in any order. AWS may also return a document in a different form from the
+// one Terraform submitted. Parse normalizes all of these so that two
+// documents granting the same permissions compare equal.
+package iampolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Document is a normalized IAM policy document.
+type Document struct {
+	// Version is the policy language version (e.g., "2012-10-17").
+	Version string `json:"Version,omitempty"`
+
+	// ID is the optional policy identifier.
+	ID string `json:"Id,omitempty"`
+
+	// Statements contains the policy statements, sorted by their canonical form.
+	Statements []Statement `json:"Statement"`
+}
+
+// Statement is a normalized IAM policy statement. Every list is sorted and
+// free of duplicates.
+type Statement struct {
+	// Sid is the optional statement identifier. It is ignored when comparing.
+	Sid string `json:"Sid,omitempty"`
+
+	// Effect is "Allow" or "Deny".
+	Effect string `json:"Effect"`
+
+	// Principal maps principal types ("AWS", "Service", "Federated",
+	// "CanonicalUser") to principal identifiers. A bare "*" principal is
+	// stored as {"AWS": ["*"]}.
+	Principal map[string][]string `json:"Principal,omitempty"`
+
+	// NotPrincipal is the excluded principal set, in the same form as Principal.
+	NotPrincipal map[string][]string `json:"NotPrincipal,omitempty"`
+
+	// Action lists the actions the statement applies to.
+	Action []string `json:"Action,omitempty"`
+
+	// NotAction lists the actions the statement excludes.
+	NotAction []string `json:"NotAction,omitempty"`
+
+	// Resource lists the resource ARNs the statement applies to.
+	Resource []string `json:"Resource,omitempty"`
+
+	// NotResource lists the resource ARNs the statement excludes.
+	NotResource []string `json:"NotResource,omitempty"`
+
+	// Condition maps condition operators to condition keys and their values.
+	// Non-string values such as booleans are stored in their string form.
+	Condition map[string]map[string][]string `json:"Condition,omitempty"`
+}
+
+// rawStatement mirrors a statement as written, before normalization.
+type rawStatement struct {
+	Sid          string                            `json:"Sid"`
+	Effect       string                            `json:"Effect"`
+	Principal    interface{}                       `json:"Principal"`
+	NotPrincipal interface{}                       `json:"NotPrincipal"`
+	Action       interface{}                       `json:"Action"`
+	NotAction    interface{}                       `json:"NotAction"`
+	Resource     interface{}                       `json:"Resource"`
+	NotResource  interface{}                       `json:"NotResource"`
+	Condition    map[string]map[string]interface{} `json:"Condition"`
+}
+
+// Parse parses and normalizes a JSON policy document.
+//
+// Parameters:
+//   - doc: the policy document JSON
+//
+// Returns:
+//   - *Document: the normalized document
+//   - error: if doc is not valid JSON or not a policy document
+func Parse(doc string) (*Document, error) {
+	var raw struct {
+		Version   string          `json:"Version"`
+		ID        string          `json:"Id"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(doc), &raw); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %w", err)
+	}
+
+	// Statement may be a single object or a list
+	var stmts []rawStatement
+	trimmed := strings.TrimSpace(string(raw.Statement))
+	switch {
+	case trimmed == "" || trimmed == "null":
+	case strings.HasPrefix(trimmed, "{"):
+		var s rawStatement
+		if err := json.Unmarshal(raw.Statement, &s); err != nil {
+			return nil, fmt.Errorf("invalid policy statement: %w", err)
+		}
+		stmts = append(stmts, s)
+	default:
+		if err := json.Unmarshal(raw.Statement, &stmts); err != nil {
+			return nil, fmt.Errorf("invalid policy statement: %w", err)
+		}
+	}
+
+	d := &Document{Version: raw.Version, ID: raw.ID}
+	for _, s := range stmts {
+		d.Statements = append(d.Statements, Statement{
+			Sid:          s.Sid,
+			Effect:       s.Effect,
+			Principal:    principals(s.Principal),
+			NotPrincipal: principals(s.NotPrincipal),
+			Action:       stringSet(s.Action),
+			NotAction:    stringSet(s.NotAction),
+			Resource:     stringSet(s.Resource),
+			NotResource:  stringSet(s.NotResource),
+			Condition:    conditions(s.Condition),
+		})
+	}
+	sort.SliceStable(d.Statements, func(i, j int) bool {
+		return d.Statements[i].Key() < d.Statements[j].Key()
+	})
+	return d, nil
+}
+
+// Equal reports whether two JSON policy documents grant the same permissions.
+//
+// Statement order, Sid values, string-versus-array spelling, duplicate
+// entries and the case of action names are ignored. If either document
+// cannot be parsed, the documents are equal only if their text is identical
+// after trimming whitespace.
+func Equal(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == b {
+		return true
+	}
+	da, err := Parse(a)
+	if err != nil {
+		return false
+	}
+	db, err := Parse(b)
+	if err != nil {
+		return false
+	}
+	return da.Equal(db)
+}
+
+// Equal reports whether d and other contain the same statements under the
+// same policy version, ignoring statement order and Sid values.
+func (d *Document) Equal(other *Document) bool {
+	if d.Version != other.Version || len(d.Statements) != len(other.Statements) {
+		return false
+	}
+	keys := make(map[string]int, len(d.Statements))
+	for _, s := range d.Statements {
+		keys[s.Key()]++
+	}
+	for _, s := range other.Statements {
+		k := s.Key()
+		if keys[k] == 0 {
+			return false
+		}
+		keys[k]--
+	}
+	return true
+}
+
+// Key returns the canonical form of the statement: its JSON encoding with
+// the Sid removed and action names lowercased, since IAM matches actions
+// case-insensitively. Two statements with the same key are equivalent.
+func (s Statement) Key() string {
+	c := s
+	c.Sid = ""
+	c.Action = lowerSet(s.Action)
+	c.NotAction = lowerSet(s.NotAction)
+	b, _ := json.Marshal(c) // maps and string slices always marshal
+	return string(b)
+}
+
+// principals normalizes a Principal or NotPrincipal element.
+func principals(v interface{}) map[string][]string {
+	switch p := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return map[string][]string{"AWS": {p}}
+	case map[string]interface{}:
+		out := make(map[string][]string, len(p))
+		for k, ids := range p {
+			out[k] = stringSet(ids)
+		}
+		return out
+	default:
+		return map[string][]string{"AWS": stringSet(p)}
+	}
+}
+
+// conditions normalizes a Condition element.
+func conditions(c map[string]map[string]interface{}) map[string]map[string][]string {
+	if len(c) == 0 {
+		return nil
+	}
+	out := make(map[string]map[string][]string, len(c))
+	for op, keys := range c {
+		out[op] = make(map[string][]string, len(keys))
+		for k, v := range keys {
+			out[op][k] = stringSet(v)
+		}
+	}
+	return out
+}
+
+// stringSet converts a string or list element into a sorted list without
+// duplicates. Non-string scalars are converted to their string form.
+func stringSet(v interface{}) []string {
+	var out []string
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, e := range x {
+			out = append(out, scalar(e))
+		}
+	default:
+		out = append(out, scalar(x))
+	}
+	sort.Strings(out)
+	return slices.Compact(out)
+}
+
+// scalar returns the string form of a JSON scalar.
+func scalar(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// lowerSet returns a lowercased, sorted copy of a list without duplicates.
+func lowerSet(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = strings.ToLower(s)
+	}
+	sort.Strings(out)
+	return slices.Compact(out)
+}
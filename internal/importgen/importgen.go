@@ -0,0 +1,293 @@
+// Package importgen generates Terraform configuration that adopts unmanaged
+// AWS resources.
+//
+// For each unmanaged resource it emits a Terraform 1.5+ import block and,
+// optionally, a skeleton resource block populated from the live model. The
+// skeleton is a starting point: run "terraform plan" after importing and
+// fill in any attributes Cloudrift does not model.
+package importgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// Generator accumulates import blocks, and optionally resource skeletons,
+// for unmanaged resources from one or more services.
+type Generator struct {
+	file      *hclwrite.File
+	skeletons bool
+	count     int
+
+	// names tracks the resource names used per resource type, so that
+	// AWS names that normalize to the same identifier stay unique.
+	names map[string]map[string]bool
+}
+
+// New creates a generator. If skeletons is true, a resource block is written
+// after each import block.
+func New(skeletons bool) *Generator {
+	return &Generator{
+		file:      hclwrite.NewEmptyFile(),
+		skeletons: skeletons,
+		names:     make(map[string]map[string]bool),
+	}
+}
+
+// Add appends blocks for the given unmanaged resources.
+//
+// Parameters:
+//   - resources: unmanaged resources, as returned by detector.FindUnmanaged
+//   - live: the live state the resources were found in, used for import IDs and skeletons
+//
+// Returns:
+//   - error: if a resource type is not supported or is not present in the live state
+func (g *Generator) Add(resources []detector.UnmanagedResource, live interface{}) error {
+	idx := indexLiveState(live)
+	for _, r := range resources {
+		model, ok := idx[r.ResourceType+"/"+r.ResourceID]
+		if !ok {
+			return fmt.Errorf("%s %q not found in live state", r.ResourceType, r.ResourceID)
+		}
+		importID, err := importID(r, model)
+		if err != nil {
+			return err
+		}
+
+		name := g.uniqueName(r.ResourceType, r.ResourceName)
+		addr := hcl.Traversal{hcl.TraverseRoot{Name: r.ResourceType}, hcl.TraverseAttr{Name: name}}
+
+		body := g.file.Body()
+		if g.count > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("import", nil).Body()
+		block.SetAttributeTraversal("to", addr)
+		block.SetAttributeValue("id", cty.StringVal(importID))
+
+		if g.skeletons {
+			body.AppendNewline()
+			res := body.AppendNewBlock("resource", []string{r.ResourceType, name}).Body()
+			writeSkeleton(res, model)
+		}
+		g.count++
+	}
+	return nil
+}
+
+// Count returns the number of resources added so far.
+func (g *Generator) Count() int {
+	return g.count
+}
+
+// Bytes returns the generated configuration, formatted as Terraform would.
+func (g *Generator) Bytes() []byte {
+	return hclwrite.Format(g.file.Bytes())
+}
+
+// uniqueName returns the resource name for an AWS name, adding a numeric
+// suffix if the name is already taken for the resource type.
+func (g *Generator) uniqueName(resourceType, awsName string) string {
+	used := g.names[resourceType]
+	if used == nil {
+		used = make(map[string]bool)
+		g.names[resourceType] = used
+	}
+	base := ResourceName(awsName)
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+// ResourceName derives a Terraform resource name from an AWS name.
+//
+// The name is lowercased and every run of characters other than letters and
+// digits becomes a single underscore. Names that would start with a digit
+// are prefixed with "r_", since Terraform identifiers must start with a
+// letter or underscore. For example, "My-Bucket.logs" becomes "my_bucket_logs".
+func ResourceName(awsName string) string {
+	var b strings.Builder
+	underscore := false
+	for _, c := range strings.ToLower(awsName) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(c)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	name := b.String()
+	switch {
+	case name == "":
+		return "resource"
+	case name[0] >= '0' && name[0] <= '9':
+		return "r_" + name
+	default:
+		return name
+	}
+}
+
+// indexLiveState maps "<resource type>/<resource ID>" to the live model.
+func indexLiveState(live interface{}) map[string]interface{} {
+	idx := make(map[string]interface{})
+	switch l := live.(type) {
+	case *models.S3LiveState:
+		for _, b := range l.Buckets {
+			idx["aws_s3_bucket/"+b.Name] = b
+		}
+	case []models.S3Bucket:
+		for _, b := range l {
+			idx["aws_s3_bucket/"+b.Name] = b
+		}
+	case *models.EC2LiveState:
+		for _, inst := range l.Instances {
+			idx["aws_instance/"+inst.InstanceID] = inst
+		}
+	case []models.EC2Instance:
+		for _, inst := range l {
+			idx["aws_instance/"+inst.InstanceID] = inst
+		}
+	case *models.IAMLiveState:
+		for _, r := range l.Roles {
+			idx["aws_iam_role/"+r.RoleName] = r
+		}
+		for _, u := range l.Users {
+			idx["aws_iam_user/"+u.UserName] = u
+		}
+		for _, p := range l.Policies {
+			idx["aws_iam_policy/"+p.PolicyName] = p
+		}
+		for _, gr := range l.Groups {
+			idx["aws_iam_group/"+gr.GroupName] = gr
+		}
+		for _, ip := range l.InstanceProfiles {
+			idx["aws_iam_instance_profile/"+ip.InstanceProfileName] = ip
+		}
+	}
+	return idx
+}
+
+// importID returns the ID Terraform expects when importing the resource.
+// Customer-managed policies are imported by ARN; everything else by name or
+// instance ID.
+func importID(r detector.UnmanagedResource, model interface{}) (string, error) {
+	if p, ok := model.(models.IAMPolicy); ok {
+		if p.Arn == "" {
+			return "", fmt.Errorf("aws_iam_policy %q has no ARN to import", p.PolicyName)
+		}
+		return p.Arn, nil
+	}
+	return r.ResourceID, nil
+}
+
+// writeSkeleton sets the resource attributes known from the live model.
+// Empty values are omitted so Terraform applies its defaults.
+func writeSkeleton(body *hclwrite.Body, model interface{}) {
+	switch m := model.(type) {
+	case models.S3Bucket:
+		setString(body, "bucket", m.Name)
+		setTags(body, m.Tags)
+
+	case models.EC2Instance:
+		setString(body, "ami", m.AMI)
+		setString(body, "instance_type", m.InstanceType)
+		setString(body, "subnet_id", m.SubnetID)
+		setStrings(body, "vpc_security_group_ids", m.SecurityGroupIDs)
+		setString(body, "key_name", m.KeyName)
+		setString(body, "iam_instance_profile", models.InstanceProfileName(m.IAMInstanceProfile))
+		setBool(body, "ebs_optimized", m.EBSOptimized)
+		setBool(body, "monitoring", m.Monitoring)
+		setBool(body, "disable_api_termination", m.DisableAPITermination)
+		setBool(body, "disable_api_stop", m.DisableAPIStop)
+		setTags(body, m.Tags)
+
+	case models.IAMRole:
+		setString(body, "name", m.RoleName)
+		setString(body, "path", m.Path)
+		setString(body, "description", m.Description)
+		setString(body, "assume_role_policy", m.AssumeRolePolicy)
+		if m.MaxSessionDuration > 0 {
+			body.SetAttributeValue("max_session_duration", cty.NumberIntVal(int64(m.MaxSessionDuration)))
+		}
+		setString(body, "permissions_boundary", m.PermissionsBoundary)
+		setTags(body, m.Tags)
+
+	case models.IAMUser:
+		setString(body, "name", m.UserName)
+		setString(body, "path", m.Path)
+		setString(body, "permissions_boundary", m.PermissionsBoundary)
+		setTags(body, m.Tags)
+
+	case models.IAMPolicy:
+		setString(body, "name", m.PolicyName)
+		setString(body, "path", m.Path)
+		setString(body, "description", m.Description)
+		setString(body, "policy", m.PolicyDocument)
+		setTags(body, m.Tags)
+
+	case models.IAMGroup:
+		setString(body, "name", m.GroupName)
+		setString(body, "path", m.Path)
+
+	case models.IAMInstanceProfile:
+		setString(body, "name", m.InstanceProfileName)
+		setString(body, "path", m.Path)
+		if len(m.Roles) > 0 {
+			setString(body, "role", m.Roles[0])
+		}
+		setTags(body, m.Tags)
+	}
+}
+
+// setString sets a string attribute unless the value is empty.
+func setString(body *hclwrite.Body, name, value string) {
+	if value != "" {
+		body.SetAttributeValue(name, cty.StringVal(value))
+	}
+}
+
+// setBool sets a boolean attribute unless the value is false.
+func setBool(body *hclwrite.Body, name string, value bool) {
+	if value {
+		body.SetAttributeValue(name, cty.True)
+	}
+}
+
+// setStrings sets a list-of-strings attribute unless the list is empty.
+func setStrings(body *hclwrite.Body, name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	vals := make([]cty.Value, len(values))
+	for i, v := range values {
+		vals[i] = cty.StringVal(v)
+	}
+	body.SetAttributeValue(name, cty.ListVal(vals))
+}
+
+// setTags sets the tags map, skipping AWS-reserved "aws:" tags, which
+// cannot be managed by Terraform.
+func setTags(body *hclwrite.Body, tags map[string]string) {
+	vals := make(map[string]cty.Value, len(tags))
+	for k, v := range tags {
+		if !strings.HasPrefix(k, "aws:") {
+			vals[k] = cty.StringVal(v)
+		}
+	}
+	if len(vals) > 0 {
+		body.SetAttributeValue("tags", cty.MapVal(vals))
+	}
+}
@@ -1,6 +1,12 @@
 // Package models defines data structures for cloud resources.
 package models
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
 // EC2Instance represents an AWS EC2 instance with its configuration.
 //
 // This model captures the key attributes that are compared for drift detection
@@ -54,6 +60,25 @@ type EC2Instance struct {
 	// RootBlockDevice contains root volume configuration.
 	RootBlockDevice BlockDevice `json:"root_block_device,omitempty"`
 
+	// EBSBlockDevices contains additional (non-root) EBS volumes, identified by device name.
+	EBSBlockDevices []BlockDevice `json:"ebs_block_devices,omitempty"`
+
+	// MetadataOptions contains the instance metadata service (IMDS) settings.
+	MetadataOptions MetadataOptions `json:"metadata_options"`
+
+	// NetworkInterfaces lists the elastic network interfaces attached to the instance.
+	NetworkInterfaces []NetworkInterface `json:"network_interfaces,omitempty"`
+
+	// UserDataHash is the hex SHA-1 of the instance user data, matching the
+	// value the Terraform AWS provider stores for user_data. Empty if unset.
+	UserDataHash string `json:"user_data_hash,omitempty"`
+
+	// DisableAPITermination indicates if termination protection is enabled.
+	DisableAPITermination bool `json:"disable_api_termination"`
+
+	// DisableAPIStop indicates if stop protection is enabled.
+	DisableAPIStop bool `json:"disable_api_stop"`
+
 	// SourceDestCheck indicates if source/destination checking is enabled.
 	SourceDestCheck bool `json:"source_dest_check"`
 
@@ -62,8 +87,25 @@ type EC2Instance struct {
 	TerraformAddress string `json:"terraform_address,omitempty"`
 }
 
+// EC2LiveState holds all EC2 instances fetched from AWS along with any
+// per-instance fetch errors.
+type EC2LiveState struct {
+	// Instances contains the instances whose state was fetched successfully.
+	Instances []EC2Instance `json:"instances"`
+
+	// Errors lists instances whose state could not be fully fetched.
+	Errors []FetchError `json:"errors,omitempty"`
+}
+
 // BlockDevice represents an EBS block device configuration.
 type BlockDevice struct {
+	// DeviceName is the device name (e.g., "/dev/sdf"). Empty for the root
+	// device in plans, which do not expose it.
+	DeviceName string `json:"device_name,omitempty"`
+
+	// VolumeID is the EBS volume ID (live state only).
+	VolumeID string `json:"volume_id,omitempty"`
+
 	// VolumeType is the EBS volume type (gp2, gp3, io1, io2, etc.).
 	VolumeType string `json:"volume_type"`
 
@@ -73,8 +115,10 @@ type BlockDevice struct {
 	// DeleteOnTermination indicates if the volume is deleted when instance terminates.
 	DeleteOnTermination bool `json:"delete_on_termination"`
 
-	// Encrypted indicates if the volume is encrypted.
-	Encrypted bool `json:"encrypted"`
+	// Encrypted indicates if the volume is encrypted. It is nil in a plan
+	// that omits it, leaving encryption to the account default, or whose
+	// value is only known after apply.
+	Encrypted *bool `json:"encrypted,omitempty"`
 
 	// IOPS is the provisioned IOPS (for io1/io2/gp3 volumes).
 	IOPS int `json:"iops,omitempty"`
@@ -83,6 +127,42 @@ type BlockDevice struct {
 	Throughput int `json:"throughput,omitempty"`
 }
 
+// MetadataOptions represents the instance metadata service configuration.
+type MetadataOptions struct {
+	// HTTPTokens is "required" when IMDSv2 is enforced, or "optional".
+	HTTPTokens string `json:"http_tokens,omitempty"`
+
+	// HTTPEndpoint is "enabled" or "disabled".
+	HTTPEndpoint string `json:"http_endpoint,omitempty"`
+
+	// HTTPPutResponseHopLimit is the hop limit for IMDS PUT responses.
+	HTTPPutResponseHopLimit int `json:"http_put_response_hop_limit,omitempty"`
+
+	// InstanceMetadataTags is "enabled" if instance tags are exposed via IMDS.
+	InstanceMetadataTags string `json:"instance_metadata_tags,omitempty"`
+}
+
+// NetworkInterface represents an elastic network interface attached to an instance.
+type NetworkInterface struct {
+	// NetworkInterfaceID is the ENI ID (e.g., "eni-0123456789abcdef0").
+	NetworkInterfaceID string `json:"network_interface_id"`
+
+	// DeviceIndex is the attachment position (0 for the primary interface).
+	DeviceIndex int `json:"device_index"`
+
+	// SubnetID is the subnet the interface belongs to.
+	SubnetID string `json:"subnet_id,omitempty"`
+
+	// PrivateIP is the primary private IPv4 address of the interface.
+	PrivateIP string `json:"private_ip,omitempty"`
+
+	// SecurityGroupIDs lists the security groups attached to the interface.
+	SecurityGroupIDs []string `json:"security_group_ids,omitempty"`
+
+	// DeleteOnTermination indicates if the interface is deleted with the instance.
+	DeleteOnTermination bool `json:"delete_on_termination"`
+}
+
 // Name returns the instance name from tags, or the instance ID if no name tag exists.
 func (i EC2Instance) Name() string {
 	if name, ok := i.Tags["Name"]; ok && name != "" {
@@ -90,3 +170,20 @@ func (i EC2Instance) Name() string {
 	}
 	return i.InstanceID
 }
+
+// InstanceProfileName returns the name of an instance profile from its ARN
+// (e.g., "arn:aws:iam::123456789012:instance-profile/app" gives "app"), the
+// form the iam_instance_profile argument takes. A name is returned as is.
+func InstanceProfileName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// HashUserData returns the hex SHA-1 of raw (decoded) user data, the form the
+// Terraform AWS provider stores in the user_data attribute.
+func HashUserData(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
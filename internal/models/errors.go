@@ -0,0 +1,45 @@
+package models
+
+// FetchError records a failure to fetch the live state of a single resource.
+//
+// Fetchers collect these instead of aborting the whole scan, so that one
+// inaccessible bucket or throttled API call does not hide the rest of the
+// account. Resources with fetch errors are reported as "unknown" rather
+// than "missing", since their actual state could not be determined.
+type FetchError struct {
+	// Service is the AWS service being scanned (e.g., "s3", "iam").
+	Service string `json:"service"`
+
+	// ResourceType is the Terraform resource type (e.g., "aws_s3_bucket").
+	ResourceType string `json:"resource_type"`
+
+	// Resource is the name of the affected resource, or its identifier for
+	// resources whose names are not unique (the instance ID for EC2).
+	Resource string `json:"resource"`
+
+	// Operation is the AWS API call that failed (e.g., "GetBucketAcl").
+	Operation string `json:"operation"`
+
+	// ErrorCode is the AWS error code (e.g., "AccessDenied"), if available.
+	ErrorCode string `json:"error_code,omitempty"`
+
+	// Message is the full error message.
+	Message string `json:"message"`
+}
+
+// ResourceKey identifies a resource by Terraform type and name (e.g.,
+// "aws_iam_role.app"). Names are only unique within a type: an IAM role,
+// user and group may all be called "app".
+func ResourceKey(resourceType, name string) string {
+	return resourceType + "." + name
+}
+
+// FailedResources returns the set of resources that have at least one fetch
+// error, keyed by ResourceKey.
+func FailedResources(errs []FetchError) map[string]bool {
+	failed := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		failed[ResourceKey(e.ResourceType, e.Resource)] = true
+	}
+	return failed
+}
@@ -32,6 +32,18 @@ type IAMRole struct {
 
 	// AttachedPolicies lists the ARNs of managed policies attached to the role.
 	AttachedPolicies []string `json:"attached_policies"`
+
+	// InlinePolicies maps inline policy names to their JSON policy documents.
+	InlinePolicies map[string]string `json:"inline_policies,omitempty"`
+
+	// InlinePoliciesUnknown is set in a plan that declares an inline policy
+	// whose name or document is only known after apply. Inline policies
+	// are then not compared.
+	InlinePoliciesUnknown bool `json:"inline_policies_unknown,omitempty"`
+
+	// PermissionsBoundary is the ARN of the policy used as the role's
+	// permissions boundary. Empty if none is set.
+	PermissionsBoundary string `json:"permissions_boundary,omitempty"`
 }
 
 // Name returns the role name for display purposes.
@@ -61,6 +73,18 @@ type IAMUser struct {
 
 	// AttachedPolicies lists the ARNs of managed policies attached to the user.
 	AttachedPolicies []string `json:"attached_policies"`
+
+	// InlinePolicies maps inline policy names to their JSON policy documents.
+	InlinePolicies map[string]string `json:"inline_policies,omitempty"`
+
+	// InlinePoliciesUnknown is set in a plan that declares an inline policy
+	// whose name or document is only known after apply. Inline policies
+	// are then not compared.
+	InlinePoliciesUnknown bool `json:"inline_policies_unknown,omitempty"`
+
+	// PermissionsBoundary is the ARN of the policy used as the user's
+	// permissions boundary. Empty if none is set.
+	PermissionsBoundary string `json:"permissions_boundary,omitempty"`
 }
 
 // Name returns the user name for display purposes.
@@ -122,6 +146,14 @@ type IAMGroup struct {
 
 	// Members lists the user names that belong to this group.
 	Members []string `json:"members"`
+
+	// InlinePolicies maps inline policy names to their JSON policy documents.
+	InlinePolicies map[string]string `json:"inline_policies,omitempty"`
+
+	// InlinePoliciesUnknown is set in a plan that declares an inline policy
+	// whose name or document is only known after apply. Inline policies
+	// are then not compared.
+	InlinePoliciesUnknown bool `json:"inline_policies_unknown,omitempty"`
 }
 
 // Name returns the group name for display purposes.
@@ -132,23 +164,79 @@ func (g IAMGroup) Name() string {
 	return g.Arn
 }
 
+// IAMInstanceProfile represents an AWS IAM instance profile.
+type IAMInstanceProfile struct {
+	// TerraformAddress is the Terraform resource address (e.g., "aws_iam_instance_profile.web").
+	TerraformAddress string `json:"terraform_address,omitempty"`
+
+	// InstanceProfileName is the name of the instance profile.
+	InstanceProfileName string `json:"instance_profile_name"`
+
+	// Arn is the Amazon Resource Name for the instance profile.
+	Arn string `json:"arn,omitempty"`
+
+	// Path is the IAM path for the instance profile.
+	Path string `json:"path"`
+
+	// Roles lists the names of the roles in the instance profile (at most one).
+	Roles []string `json:"roles"`
+
+	// Tags contains the instance profile's tag key-value pairs.
+	Tags map[string]string `json:"tags"`
+}
+
+// Name returns the instance profile name for display purposes.
+func (p IAMInstanceProfile) Name() string {
+	if p.InstanceProfileName != "" {
+		return p.InstanceProfileName
+	}
+	return p.Arn
+}
+
 // IAMPlanResources holds all IAM resources parsed from a Terraform plan.
 type IAMPlanResources struct {
-	Roles    []IAMRole   `json:"roles"`
-	Users    []IAMUser   `json:"users"`
-	Policies []IAMPolicy `json:"policies"`
-	Groups   []IAMGroup  `json:"groups"`
+	Roles            []IAMRole            `json:"roles"`
+	Users            []IAMUser            `json:"users"`
+	Policies         []IAMPolicy          `json:"policies"`
+	Groups           []IAMGroup           `json:"groups"`
+	InstanceProfiles []IAMInstanceProfile `json:"instance_profiles"`
 }
 
 // TotalCount returns the total number of IAM resources across all types.
 func (p *IAMPlanResources) TotalCount() int {
-	return len(p.Roles) + len(p.Users) + len(p.Policies) + len(p.Groups)
+	return len(p.Roles) + len(p.Users) + len(p.Policies) + len(p.Groups) + len(p.InstanceProfiles)
+}
+
+// Names returns the names of all planned IAM resources: roles, users,
+// policies, groups, then instance profiles.
+func (p *IAMPlanResources) Names() []string {
+	names := make([]string, 0, p.TotalCount())
+	for _, r := range p.Roles {
+		names = append(names, r.RoleName)
+	}
+	for _, u := range p.Users {
+		names = append(names, u.UserName)
+	}
+	for _, pol := range p.Policies {
+		names = append(names, pol.PolicyName)
+	}
+	for _, g := range p.Groups {
+		names = append(names, g.GroupName)
+	}
+	for _, ip := range p.InstanceProfiles {
+		names = append(names, ip.InstanceProfileName)
+	}
+	return names
 }
 
 // IAMLiveState holds all IAM resources fetched from AWS.
 type IAMLiveState struct {
-	Roles    []IAMRole   `json:"roles"`
-	Users    []IAMUser   `json:"users"`
-	Policies []IAMPolicy `json:"policies"`
-	Groups   []IAMGroup  `json:"groups"`
+	Roles            []IAMRole            `json:"roles"`
+	Users            []IAMUser            `json:"users"`
+	Policies         []IAMPolicy          `json:"policies"`
+	Groups           []IAMGroup           `json:"groups"`
+	InstanceProfiles []IAMInstanceProfile `json:"instance_profiles,omitempty"`
+
+	// Errors lists resources whose tags, documents or attachments could not be fetched.
+	Errors []FetchError `json:"errors,omitempty"`
 }
@@ -5,6 +5,11 @@
 // AWS API fetchers, and drift detection logic.
 package models
 
+import (
+	"sort"
+	"strings"
+)
+
 // S3Bucket represents the configuration state of an AWS S3 bucket.
 // It captures both Terraform-planned attributes and live AWS state,
 // enabling attribute-level drift comparison.
@@ -16,8 +21,19 @@ type S3Bucket struct {
 	Name string `yaml:"name"`
 
 	// Acl is the canned ACL applied to the bucket (e.g., "private", "public-read").
+	// For live buckets it is derived from the grants and is empty when the
+	// grants match no canned ACL; AclGrants then describes the actual ACL.
 	Acl string `yaml:"acl"`
 
+	// AclGrants is the normalized grant list. Plans populate it from grant
+	// blocks; live buckets always populate it from GetBucketAcl.
+	AclGrants []AclGrant
+
+	// ObjectOwnership is the bucket's object ownership setting
+	// ("BucketOwnerEnforced", "BucketOwnerPreferred" or "ObjectWriter").
+	// Empty if no ownership controls are configured.
+	ObjectOwnership string
+
 	// Tags contains the key-value metadata tags associated with the bucket.
 	Tags map[string]string `yaml:"tags"`
 
@@ -28,6 +44,14 @@ type S3Bucket struct {
 	// (e.g., "AES256", "aws:kms"). Empty string means no encryption configured.
 	EncryptionAlgorithm string
 
+	// KMSKeyID is the KMS key used for default encryption with aws:kms.
+	// Empty means the AWS managed key (aws/s3).
+	KMSKeyID string
+
+	// BucketKeyEnabled indicates whether an S3 Bucket Key is used to reduce
+	// KMS request costs.
+	BucketKeyEnabled bool
+
 	// LoggingEnabled indicates whether server access logging is enabled.
 	LoggingEnabled bool
 
@@ -42,6 +66,75 @@ type S3Bucket struct {
 
 	// LifecycleRules contains the object lifecycle management rules.
 	LifecycleRules []LifecycleRuleSummary
+
+	// Policy is the bucket policy JSON document. Empty if no policy is attached.
+	Policy string
+
+	// CORSRules contains the cross-origin resource sharing rules.
+	CORSRules []CORSRule
+
+	// Replication contains the replication configuration.
+	Replication ReplicationConfig
+
+	// ObjectLock contains the object lock configuration.
+	ObjectLock ObjectLockConfig
+}
+
+// S3LiveState holds all S3 buckets fetched from AWS along with any
+// per-bucket fetch errors.
+type S3LiveState struct {
+	// Buckets contains the buckets whose state was fetched successfully.
+	Buckets []S3Bucket `json:"buckets"`
+
+	// Errors lists buckets whose state could not be fetched.
+	Errors []FetchError `json:"errors,omitempty"`
+}
+
+// AclGrant is a single normalized bucket ACL grant.
+type AclGrant struct {
+	// Grantee identifies who receives the permission: "id:<canonical user ID>",
+	// "group:<AllUsers|AuthenticatedUsers|LogDelivery>" or "email:<address>".
+	Grantee string
+
+	// Permission is FULL_CONTROL, READ, WRITE, READ_ACP or WRITE_ACP.
+	Permission string
+}
+
+// NewAclGrant builds a normalized grant from the fields S3 and Terraform use
+// to describe a grantee. Group URIs are reduced to their final path segment
+// (e.g., "http://acs.amazonaws.com/groups/global/AllUsers" becomes "group:AllUsers").
+//
+// Parameters:
+//   - granteeType: "CanonicalUser", "Group" or "AmazonCustomerByEmail"
+//   - id: canonical user ID (CanonicalUser grantees)
+//   - uri: group URI (Group grantees)
+//   - email: email address (AmazonCustomerByEmail grantees)
+//   - permission: the granted permission
+//
+// Returns:
+//   - AclGrant: the normalized grant
+func NewAclGrant(granteeType, id, uri, email, permission string) AclGrant {
+	var grantee string
+	switch granteeType {
+	case "Group":
+		grantee = "group:" + uri[strings.LastIndex(uri, "/")+1:]
+	case "AmazonCustomerByEmail":
+		grantee = "email:" + email
+	default:
+		grantee = "id:" + id
+	}
+	return AclGrant{Grantee: grantee, Permission: permission}
+}
+
+// SortAclGrants sorts grants by grantee, then permission, so grant lists can
+// be compared without regard to order.
+func SortAclGrants(grants []AclGrant) {
+	sort.Slice(grants, func(i, j int) bool {
+		if grants[i].Grantee != grants[j].Grantee {
+			return grants[i].Grantee < grants[j].Grantee
+		}
+		return grants[i].Permission < grants[j].Permission
+	})
 }
 
 // PublicAccessBlockConfig represents the S3 Block Public Access configuration.
@@ -61,8 +154,8 @@ type PublicAccessBlockConfig struct {
 	RestrictPublicBuckets bool
 }
 
-// LifecycleRuleSummary represents a simplified view of an S3 lifecycle rule.
-// It captures the essential attributes needed for drift detection.
+// LifecycleRuleSummary represents an S3 lifecycle rule: its filter and
+// every action it applies. Day counts and sizes of zero mean "not set".
 type LifecycleRuleSummary struct {
 	// ID is the unique identifier for the lifecycle rule.
 	ID string
@@ -73,6 +166,135 @@ type LifecycleRuleSummary struct {
 	// Prefix is the object key prefix that identifies objects subject to this rule.
 	Prefix string
 
+	// FilterTags limits the rule to objects carrying all of these tags.
+	FilterTags map[string]string
+
+	// ObjectSizeGreaterThan limits the rule to objects larger than this many bytes.
+	ObjectSizeGreaterThan int64
+
+	// ObjectSizeLessThan limits the rule to objects smaller than this many bytes.
+	ObjectSizeLessThan int64
+
 	// ExpirationDays is the number of days after creation when objects expire.
 	ExpirationDays int
+
+	// ExpirationDate is the date objects expire, as YYYY-MM-DD.
+	ExpirationDate string
+
+	// ExpiredObjectDeleteMarker removes delete markers with no noncurrent versions.
+	ExpiredObjectDeleteMarker bool
+
+	// NoncurrentExpirationDays is the number of days after becoming noncurrent
+	// when object versions expire.
+	NoncurrentExpirationDays int
+
+	// NewerNoncurrentVersions is the number of noncurrent versions retained
+	// regardless of NoncurrentExpirationDays.
+	NewerNoncurrentVersions int
+
+	// Transitions moves current object versions to other storage classes.
+	Transitions []LifecycleTransition
+
+	// NoncurrentTransitions moves noncurrent object versions to other storage classes.
+	NoncurrentTransitions []LifecycleTransition
+
+	// AbortIncompleteMultipartDays is the number of days after initiation when
+	// incomplete multipart uploads are aborted.
+	AbortIncompleteMultipartDays int
+}
+
+// LifecycleTransition is a single storage class transition in a lifecycle rule.
+type LifecycleTransition struct {
+	// Days is the number of days (after creation, or after becoming
+	// noncurrent for noncurrent transitions) before the transition.
+	Days int
+
+	// Date is the date of the transition, as YYYY-MM-DD.
+	Date string
+
+	// StorageClass is the target storage class (e.g., "GLACIER", "STANDARD_IA").
+	StorageClass string
+}
+
+// SortLifecycleTransitions sorts transitions by days, date, then storage
+// class, so transition lists can be compared without regard to order.
+func SortLifecycleTransitions(ts []LifecycleTransition) {
+	sort.Slice(ts, func(i, j int) bool {
+		if ts[i].Days != ts[j].Days {
+			return ts[i].Days < ts[j].Days
+		}
+		if ts[i].Date != ts[j].Date {
+			return ts[i].Date < ts[j].Date
+		}
+		return ts[i].StorageClass < ts[j].StorageClass
+	})
+}
+
+// CORSRule represents a single S3 CORS rule.
+type CORSRule struct {
+	// ID is the optional rule identifier.
+	ID string
+
+	// AllowedHeaders lists headers allowed in preflight requests.
+	AllowedHeaders []string
+
+	// AllowedMethods lists the allowed HTTP methods (GET, PUT, POST, DELETE, HEAD).
+	AllowedMethods []string
+
+	// AllowedOrigins lists the origins allowed to make cross-origin requests.
+	AllowedOrigins []string
+
+	// ExposeHeaders lists response headers exposed to the browser.
+	ExposeHeaders []string
+
+	// MaxAgeSeconds is how long browsers may cache the preflight response.
+	MaxAgeSeconds int
+}
+
+// ReplicationConfig represents an S3 replication configuration.
+type ReplicationConfig struct {
+	// Role is the ARN of the IAM role S3 assumes to replicate objects.
+	Role string
+
+	// Rules contains the replication rules.
+	Rules []ReplicationRule
+}
+
+// ReplicationRule represents a single S3 replication rule.
+type ReplicationRule struct {
+	// ID is the unique identifier for the rule.
+	ID string
+
+	// Status indicates whether the rule is "Enabled" or "Disabled".
+	Status string
+
+	// Priority decides which rule wins when several match an object.
+	Priority int
+
+	// Prefix is the object key prefix the rule applies to.
+	Prefix string
+
+	// DestinationBucket is the ARN of the destination bucket.
+	DestinationBucket string
+
+	// StorageClass is the storage class for replicas (empty keeps the source class).
+	StorageClass string
+
+	// DeleteMarkerReplication indicates whether delete markers are replicated.
+	DeleteMarkerReplication bool
+}
+
+// ObjectLockConfig represents an S3 object lock configuration.
+type ObjectLockConfig struct {
+	// Enabled indicates whether object lock is enabled on the bucket.
+	Enabled bool
+
+	// Mode is the default retention mode ("GOVERNANCE" or "COMPLIANCE").
+	Mode string
+
+	// Days is the default retention period in days.
+	Days int
+
+	// Years is the default retention period in years.
+	Years int
 }
@@ -0,0 +1,208 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ASFFFormatter outputs scan results as AWS Security Finding Format (ASFF)
+// findings, ready to send to Security Hub with BatchImportFindings.
+//
+// Each drifted or missing resource and each policy violation or warning is
+// one finding. Finding IDs are derived from the account, region, check and
+// resource, so importing a later scan updates existing findings instead of
+// creating duplicates. Findings use the account's default Security Hub
+// product ARN.
+//
+// Specification: https://docs.aws.amazon.com/securityhub/latest/userguide/securityhub-findings-format.html
+type ASFFFormatter struct{}
+
+// NewASFFFormatter creates a new ASFF formatter.
+func NewASFFFormatter() *ASFFFormatter {
+	return &ASFFFormatter{}
+}
+
+// asffDocument is the body of a BatchImportFindings request. The API
+// accepts at most 100 findings per call.
+type asffDocument struct {
+	Findings []asffFinding `json:"Findings"`
+}
+
+type asffFinding struct {
+	SchemaVersion string            `json:"SchemaVersion"`
+	ID            string            `json:"Id"`
+	ProductArn    string            `json:"ProductArn"`
+	ProductName   string            `json:"ProductName"`
+	CompanyName   string            `json:"CompanyName"`
+	GeneratorID   string            `json:"GeneratorId"`
+	AwsAccountID  string            `json:"AwsAccountId"`
+	Region        string            `json:"Region,omitempty"`
+	Types         []string          `json:"Types"`
+	CreatedAt     string            `json:"CreatedAt"`
+	UpdatedAt     string            `json:"UpdatedAt"`
+	Severity      asffSeverity      `json:"Severity"`
+	Title         string            `json:"Title"`
+	Description   string            `json:"Description"`
+	Remediation   *asffRemediation  `json:"Remediation,omitempty"`
+	ProductFields map[string]string `json:"ProductFields"`
+	Resources     []asffResource    `json:"Resources"`
+	Compliance    *asffCompliance   `json:"Compliance,omitempty"`
+	RecordState   string            `json:"RecordState"`
+}
+
+type asffSeverity struct {
+	Label    string `json:"Label"`
+	Original string `json:"Original"`
+}
+
+type asffRemediation struct {
+	Recommendation asffRecommendation `json:"Recommendation"`
+}
+
+type asffRecommendation struct {
+	Text string `json:"Text"`
+}
+
+type asffResource struct {
+	Type      string `json:"Type"`
+	ID        string `json:"Id"`
+	Partition string `json:"Partition"`
+	Region    string `json:"Region,omitempty"`
+}
+
+type asffCompliance struct {
+	Status              string   `json:"Status"`
+	RelatedRequirements []string `json:"RelatedRequirements,omitempty"`
+}
+
+// asffSeverityLabels maps normalized severities to ASFF labels.
+var asffSeverityLabels = map[string]string{
+	"critical": "CRITICAL",
+	"high":     "HIGH",
+	"medium":   "MEDIUM",
+	"low":      "LOW",
+	"info":     "INFORMATIONAL",
+}
+
+// asffResourceTypes maps Terraform resource types to ASFF resource types.
+var asffResourceTypes = map[string]string{
+	"aws_s3_bucket":  "AwsS3Bucket",
+	"aws_instance":   "AwsEc2Instance",
+	"aws_iam_role":   "AwsIamRole",
+	"aws_iam_user":   "AwsIamUser",
+	"aws_iam_group":  "AwsIamGroup",
+	"aws_iam_policy": "AwsIamPolicy",
+}
+
+// Format writes the scan result as a BatchImportFindings request body.
+func (f *ASFFFormatter) Format(w io.Writer, result ScanResult) error {
+	timestamp := result.Timestamp
+	if timestamp == "" {
+		timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	partition := awsPartition(result.Region)
+
+	doc := asffDocument{Findings: []asffFinding{}}
+	for _, sf := range securityFindings(result) {
+		finding := asffFinding{
+			SchemaVersion: "2018-10-08",
+			ID:            sf.ID,
+			ProductArn:    fmt.Sprintf("arn:%s:securityhub:%s:%s:product/%s/default", partition, result.Region, result.AccountID, result.AccountID),
+			ProductName:   "Cloudrift",
+			CompanyName:   "Cloudrift",
+			GeneratorID:   "cloudrift/" + sf.Control,
+			AwsAccountID:  result.AccountID,
+			Region:        result.Region,
+			Types:         []string{asffType(sf)},
+			CreatedAt:     timestamp,
+			UpdatedAt:     timestamp,
+			Severity: asffSeverity{
+				Label:    asffSeverityLabels[sf.Severity],
+				Original: sf.Severity,
+			},
+			Title:         truncate(sf.Title, 256),
+			Description:   truncate(sf.Description, 1024),
+			ProductFields: asffProductFields(sf),
+			Resources:     []asffResource{asffResourceFor(sf, partition, result.Region)},
+			RecordState:   "ACTIVE",
+		}
+		if sf.Remediation != "" {
+			finding.Remediation = &asffRemediation{Recommendation: asffRecommendation{Text: truncate(sf.Remediation, 512)}}
+		}
+		if !sf.Drift {
+			status := "FAILED"
+			if sf.Warning {
+				status = "WARNING"
+			}
+			finding.Compliance = &asffCompliance{Status: status, RelatedRequirements: frameworkRequirements(sf.Frameworks)}
+		}
+		doc.Findings = append(doc.Findings, finding)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// asffType classifies a finding in the ASFF types taxonomy.
+func asffType(sf securityFinding) string {
+	switch {
+	case sf.Drift:
+		return "Software and Configuration Checks/AWS Security Best Practices/Configuration Drift"
+	case len(sf.Frameworks) > 0:
+		return "Software and Configuration Checks/Industry and Regulatory Standards"
+	default:
+		return "Software and Configuration Checks/AWS Security Best Practices"
+	}
+}
+
+// asffResourceFor describes the resource of a finding. The ARN is used as
+// the ID when known, then the AWS identifier, then the Terraform address.
+func asffResourceFor(sf securityFinding, partition, region string) asffResource {
+	resourceType, ok := asffResourceTypes[sf.ResourceType]
+	if !ok {
+		resourceType = "Other"
+	}
+	id := sf.ARN
+	if id == "" {
+		id = sf.ResourceID
+	}
+	if id == "" {
+		id = sf.ResourceAddress
+	}
+	return asffResource{Type: resourceType, ID: id, Partition: partition, Region: region}
+}
+
+// asffProductFields records Cloudrift-specific details on a finding.
+func asffProductFields(sf securityFinding) map[string]string {
+	fields := map[string]string{
+		"cloudrift/Control":      sf.Control,
+		"cloudrift/ResourceType": sf.ResourceType,
+	}
+	if sf.ResourceAddress != "" {
+		fields["cloudrift/ResourceAddress"] = sf.ResourceAddress
+	}
+	if sf.Category != "" {
+		fields["cloudrift/Category"] = sf.Category
+	}
+	if sf.Location != nil {
+		fields["cloudrift/Location"] = sf.Location.String()
+	}
+	return fields
+}
+
+// Name returns the format name.
+func (f *ASFFFormatter) Name() string {
+	return "asff"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *ASFFFormatter) FileExtension() string {
+	return ".json"
+}
+
+func init() {
+	Register(FormatASFF, NewASFFFormatter())
+}
@@ -6,13 +6,30 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
 )
 
+// consoleRule separates the sections of the console output.
+var consoleRule = strings.Repeat("━", 50)
+
 // ConsoleFormatter outputs scan results as colorized CLI output.
 //
 // This is the default format for interactive terminal use, providing
-// human-readable output with color coding for drift severity.
-type ConsoleFormatter struct{}
+// human-readable output with color coding for drift severity. When the
+// scan result carries service details, drift is shown in the service's
+// detail view (S3, EC2 or IAM); otherwise the generic attribute view is
+// used. Fetch errors, unmanaged resources, policy findings and the
+// compliance summary follow.
+type ConsoleFormatter struct {
+	// NoColor disables ANSI colors, e.g. when writing to a file. Colors
+	// are otherwise enabled when stdout is a terminal.
+	NoColor bool
+
+	// NoEmoji replaces emoji with ASCII indicators.
+	NoEmoji bool
+}
 
 // NewConsoleFormatter creates a new console formatter.
 func NewConsoleFormatter() *ConsoleFormatter {
@@ -21,94 +38,342 @@ func NewConsoleFormatter() *ConsoleFormatter {
 
 // Format writes the scan result as colorized text to the provided writer.
 func (f *ConsoleFormatter) Format(w io.Writer, result ScanResult) error {
-	// Summary header
+	c := &consoleWriter{w: w, noColor: f.NoColor, noEmoji: f.NoEmoji}
+
+	if result.Incomplete {
+		c.printf("\n%s\n", c.yellow("%sScan incomplete - results are partial", c.icon("⏹️  ", "[!] ")))
+	}
+	if !c.writeServiceDetails(result.Details) {
+		c.writeDrifts(result)
+	}
+	c.writeErrors(result.Errors)
+	c.writeUnmanaged(result.Unmanaged)
+	c.writePolicy(result.PolicyResult)
+	return nil
+}
+
+// Name returns the format name.
+func (f *ConsoleFormatter) Name() string {
+	return "console"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *ConsoleFormatter) FileExtension() string {
+	return ".txt"
+}
+
+// consoleWriter renders console output with the color and emoji settings
+// of a ConsoleFormatter.
+type consoleWriter struct {
+	w       io.Writer
+	noColor bool
+	noEmoji bool
+}
+
+func (c *consoleWriter) printf(format string, a ...interface{}) {
+	fmt.Fprintf(c.w, format, a...)
+}
+
+func (c *consoleWriter) println(a ...interface{}) {
+	fmt.Fprintln(c.w, a...)
+}
+
+// icon returns emoji, or ascii when emoji are disabled. Both include any
+// trailing spacing.
+func (c *consoleWriter) icon(emoji, ascii string) string {
+	if c.noEmoji {
+		return ascii
+	}
+	return emoji
+}
+
+// paint formats text in the given color, or plainly when colors are off.
+func (c *consoleWriter) paint(attrs []color.Attribute, format string, a ...interface{}) string {
+	col := color.New(attrs...)
+	if c.noColor {
+		col.DisableColor()
+	}
+	return col.Sprintf(format, a...)
+}
+
+func (c *consoleWriter) red(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgRed}, format, a...)
+}
+
+func (c *consoleWriter) green(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgGreen}, format, a...)
+}
+
+func (c *consoleWriter) yellow(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgYellow}, format, a...)
+}
+
+func (c *consoleWriter) blue(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgBlue}, format, a...)
+}
+
+func (c *consoleWriter) magenta(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgMagenta}, format, a...)
+}
+
+func (c *consoleWriter) cyan(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgCyan}, format, a...)
+}
+
+func (c *consoleWriter) white(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgWhite}, format, a...)
+}
+
+func (c *consoleWriter) grey(format string, a ...interface{}) string {
+	return c.paint([]color.Attribute{color.FgHiBlack}, format, a...)
+}
+
+// banner writes a section title between two rules.
+func (c *consoleWriter) banner(title string) {
+	c.println(c.cyan("%s", consoleRule))
+	c.println(c.cyan("%s", title))
+	c.println(c.cyan("%s", consoleRule))
+}
+
+// writeServiceDetails writes the detail view of the service that produced
+// the results. It reports false if there is no view for the details.
+func (c *consoleWriter) writeServiceDetails(d *ServiceDetails) bool {
+	if d == nil {
+		return false
+	}
+	switch plan := d.Plan.(type) {
+	case []models.S3Bucket:
+		live, _, ok := detector.S3LiveBuckets(d.Live)
+		if ok {
+			c.writeS3Details(d.Results, plan, live)
+		}
+		return ok
+	case []models.EC2Instance:
+		live, _, ok := detector.EC2LiveInstances(d.Live)
+		if ok {
+			c.writeEC2Details(d.Results, plan, live)
+		}
+		return ok
+	case *models.IAMPlanResources:
+		live, ok := d.Live.(*models.IAMLiveState)
+		if ok {
+			c.writeIAMDetails(d.Results, plan, live)
+		}
+		return ok
+	}
+	return false
+}
+
+// writeDrifts writes the generic drift view, built from the attribute
+// diffs of each resource.
+func (c *consoleWriter) writeDrifts(result ScanResult) {
 	if result.DriftCount == 0 {
-		fmt.Fprintf(w, "\n%s\n", color.GreenString("✅ No drift detected!"))
-		fmt.Fprintf(w, "   Scanned %d %s resources in %dms\n\n",
+		c.printf("\n%s\n", c.green("%sNo drift detected!", c.icon("✅ ", "[+] ")))
+		c.printf("   Scanned %d %s resources in %dms\n",
 			result.TotalResources, result.Service, result.ScanDuration)
-		return nil
+		return
 	}
 
-	fmt.Fprintf(w, "\n%s\n", color.YellowString("⚠️  Drift detected!"))
-	fmt.Fprintf(w, "   %d of %d %s resources have drift\n\n",
+	c.printf("\n%s\n", c.yellow("%sDrift detected!", c.icon("⚠️  ", "[!] ")))
+	c.printf("   %d of %d %s resources have drift\n\n",
 		result.DriftCount, result.TotalResources, result.Service)
 
-	// Print each drift
 	for i, drift := range result.Drifts {
 		if !drift.HasDrift() {
 			continue
 		}
 
 		// Resource header
-		fmt.Fprintf(w, "%s\n", color.CyanString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
-		fmt.Fprintf(w, "📦 %s\n", color.WhiteString("%s (%s)", drift.ResourceName, drift.ResourceType))
+		c.println(c.cyan("%s", consoleRule))
+		c.printf("%s%s\n", c.icon("📦 ", "* "), c.white("%s (%s)", drift.ResourceName, drift.ResourceType))
 
 		if drift.Missing {
-			fmt.Fprintf(w, "   %s\n", color.RedString("❌ MISSING - Resource not found in AWS"))
+			c.printf("   %s\n", c.red("%sMISSING - Resource not found in AWS", c.icon("❌ ", "[X] ")))
 			continue
 		}
 
 		// Attribute diffs
 		if len(drift.Diffs) > 0 {
-			fmt.Fprintf(w, "   %s\n", color.YellowString("Attribute differences:"))
-			for attr, values := range drift.Diffs {
-				expected := f.formatValue(values[0])
-				actual := f.formatValue(values[1])
-				fmt.Fprintf(w, "     • %s:\n", color.WhiteString(attr))
-				fmt.Fprintf(w, "       %s %s\n", color.RedString("- expected:"), expected)
-				fmt.Fprintf(w, "       %s %s\n", color.GreenString("+ actual:  "), actual)
+			c.printf("   %s\n", c.yellow("Attribute differences:"))
+			for _, attr := range sortedKeys(drift.Diffs) {
+				values := drift.Diffs[attr]
+				c.printf("     • %s:\n", c.white("%s", attr))
+				c.printf("       %s %s\n", c.red("- expected:"), c.value(values[0]))
+				c.printf("       %s %s\n", c.green("+ actual:  "), c.value(values[1]))
 			}
 		}
 
 		// Extra attributes
 		if len(drift.ExtraAttributes) > 0 {
-			fmt.Fprintf(w, "   %s\n", color.BlueString("Extra attributes in AWS:"))
-			for attr, value := range drift.ExtraAttributes {
-				fmt.Fprintf(w, "     • %s: %s\n", color.WhiteString(attr), f.formatValue(value))
+			c.printf("   %s\n", c.blue("Extra attributes in AWS:"))
+			for _, attr := range sortedKeys(drift.ExtraAttributes) {
+				c.printf("     • %s: %s\n", c.white("%s", attr), c.value(drift.ExtraAttributes[attr]))
 			}
 		}
 
 		if i < len(result.Drifts)-1 {
-			fmt.Fprintln(w)
+			c.println()
 		}
 	}
 
-	fmt.Fprintf(w, "%s\n\n", color.CyanString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"))
+	c.printf("%s\n\n", c.cyan("%s", consoleRule))
 
 	// Summary footer
-	fmt.Fprintf(w, "📊 Summary: %s resources with drift out of %d scanned\n",
-		color.YellowString("%d", result.DriftCount), result.TotalResources)
-	fmt.Fprintf(w, "⏱️  Scan completed in %dms\n\n", result.ScanDuration)
+	c.printf("%sSummary: %s resources with drift out of %d scanned\n",
+		c.icon("📊 ", ""), c.yellow("%d", result.DriftCount), result.TotalResources)
+	c.printf("%sScan completed in %dms\n", c.icon("⏱️  ", ""), result.ScanDuration)
+}
 
-	return nil
+// writeErrors lists resources whose live state could not be fetched.
+func (c *consoleWriter) writeErrors(errs []models.FetchError) {
+	if len(errs) == 0 {
+		return
+	}
+	c.printf("\n%s\n", c.yellow("%sLive state unknown for %d resources:", c.icon("❔ ", "[?] "), len(errs)))
+	for _, e := range errs {
+		code := e.ErrorCode
+		if code == "" {
+			code = "error"
+		}
+		c.printf("   • %s (%s): %s %s - %s\n",
+			c.white("%s", e.Resource), e.ResourceType, e.Operation, c.red("%s", code), e.Message)
+	}
+}
+
+// writeUnmanaged lists live resources that are not managed by Terraform.
+func (c *consoleWriter) writeUnmanaged(resources []detector.UnmanagedResource) {
+	if len(resources) == 0 {
+		return
+	}
+	c.printf("\n%s\n", c.magenta("%s%d resources in AWS are not managed by Terraform:", c.icon("👻 ", "[?] "), len(resources)))
+	for _, r := range resources {
+		if r.ResourceName != r.ResourceID {
+			c.printf("   • %s (%s): %s\n", c.white("%s", r.ResourceName), r.ResourceType, r.ResourceID)
+		} else {
+			c.printf("   • %s (%s)\n", c.white("%s", r.ResourceName), r.ResourceType)
+		}
+	}
+}
+
+// writePolicy writes the policy violations and warnings followed by the
+// compliance summary. Nothing is written when there are no findings.
+func (c *consoleWriter) writePolicy(p *PolicyOutput) {
+	if p == nil || (len(p.Violations) == 0 && len(p.Warnings) == 0) {
+		return
+	}
+	c.println()
+	c.banner("              POLICY EVALUATION                   ")
+
+	if len(p.Violations) > 0 {
+		c.println()
+		c.println(c.red("%sVIOLATIONS (%d)", c.icon("❌ ", "[X] "), len(p.Violations)))
+		for _, v := range p.Violations {
+			c.writeFinding(v, c.red)
+		}
+	}
+	if len(p.Warnings) > 0 {
+		c.println()
+		c.println(c.yellow("%sWARNINGS (%d)", c.icon("⚠️  ", "[!] "), len(p.Warnings)))
+		for _, w := range p.Warnings {
+			c.writeFinding(w, c.yellow)
+		}
+	}
+
+	if p.ComplianceResult != nil {
+		c.writeCompliance(p.ComplianceResult)
+	}
+}
+
+// writeFinding writes one policy violation or warning.
+func (c *consoleWriter) writeFinding(v PolicyViolationOutput, paint func(string, ...interface{}) string) {
+	c.println()
+	c.println(paint("  [%s] %s", v.Severity, v.PolicyID))
+	c.printf("  %sResource: %s\n", c.icon("📍 ", "[-] "), c.cyan("%s", v.ResourceAddress))
+	c.printf("  %s%s\n", c.icon("💬 ", "[-] "), v.Message)
+	if v.Remediation != "" {
+		c.printf("  %s%s\n", c.icon("🔧 ", "[#] "), c.yellow("%s", v.Remediation))
+	}
+}
+
+// writeCompliance writes the overall, per-category and per-framework
+// compliance scores.
+func (c *consoleWriter) writeCompliance(compliance *ComplianceOutput) {
+	c.println()
+	if len(compliance.ActiveFrameworks) > 0 {
+		c.banner(fmt.Sprintf("      COMPLIANCE SUMMARY (%s)", strings.ToUpper(strings.Join(compliance.ActiveFrameworks, ", "))))
+	} else {
+		c.banner("            COMPLIANCE SUMMARY                    ")
+	}
+	c.println()
+
+	overall := c.green
+	if compliance.OverallPercentage < 80 {
+		overall = c.red
+	} else if compliance.OverallPercentage < 100 {
+		overall = c.yellow
+	}
+	c.printf("  Overall: %s (%d/%d policies passing)\n",
+		overall("%.1f%%", compliance.OverallPercentage),
+		compliance.PassingPolicies, compliance.TotalPolicies)
+	c.println()
+
+	c.println("  Categories:")
+	for _, cat := range sortedKeys(compliance.Categories) {
+		score := compliance.Categories[cat]
+		c.printf("    %-12s %s (%d/%d)\n", cat, c.score(score.Percentage, score.Failed), score.Passed, score.Total)
+	}
+	c.println()
+
+	c.println("  Frameworks:")
+	for _, fw := range sortedKeys(compliance.Frameworks) {
+		score := compliance.Frameworks[fw]
+		c.printf("    %-12s %s (%d/%d)\n", fw, c.score(score.Percentage, score.Failed), score.Passed, score.Total)
+	}
+
+	c.println()
+	c.println(c.cyan("%s", consoleRule))
+}
+
+// score renders a percentage in red if any policy failed, else in green.
+func (c *consoleWriter) score(pct float64, failed int) string {
+	if failed > 0 {
+		return c.red("%.1f%%", pct)
+	}
+	return c.green("%.1f%%", pct)
 }
 
-func (f *ConsoleFormatter) formatValue(v interface{}) string {
+// value renders a drift value for the generic view.
+func (c *consoleWriter) value(v interface{}) string {
 	switch val := v.(type) {
 	case nil:
-		return color.HiBlackString("<not set>")
+		return c.grey("<not set>")
 	case string:
 		if val == "" {
-			return color.HiBlackString("<empty>")
+			return c.grey("<empty>")
 		}
 		return fmt.Sprintf("%q", val)
 	case bool:
 		if val {
-			return color.GreenString("true")
+			return c.green("true")
 		}
-		return color.RedString("false")
+		return c.red("false")
 	case map[string]interface{}:
 		if len(val) == 0 {
-			return color.HiBlackString("{}")
+			return c.grey("{}")
 		}
 		parts := make([]string, 0, len(val))
-		for k, v := range val {
-			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		for _, k := range sortedKeys(val) {
+			parts = append(parts, fmt.Sprintf("%s=%v", k, val[k]))
 		}
 		return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
+	case []string:
+		if len(val) == 0 {
+			return c.grey("[]")
+		}
+		return fmt.Sprintf("[%s]", strings.Join(val, ", "))
 	case []interface{}:
 		if len(val) == 0 {
-			return color.HiBlackString("[]")
+			return c.grey("[]")
 		}
 		parts := make([]string, len(val))
 		for i, v := range val {
@@ -120,16 +385,6 @@ func (f *ConsoleFormatter) formatValue(v interface{}) string {
 	}
 }
 
-// Name returns the format name.
-func (f *ConsoleFormatter) Name() string {
-	return "console"
-}
-
-// FileExtension returns the recommended file extension.
-func (f *ConsoleFormatter) FileExtension() string {
-	return ".txt"
-}
-
 func init() {
 	Register(FormatConsole, NewConsoleFormatter())
 }
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// writeEC2Details writes the EC2 detail view: instance counts, then per
+// drifted instance each differing attribute with its planned and live value.
+func (c *consoleWriter) writeEC2Details(results []detector.DriftResult, planInstances, liveInstances []models.EC2Instance) {
+	planMap := make(map[string]*models.EC2Instance, len(planInstances))
+	for i := range planInstances {
+		planMap[planInstances[i].Name()] = &planInstances[i]
+	}
+	liveMap := make(map[string]*models.EC2Instance, len(liveInstances))
+	for i := range liveInstances {
+		liveMap[liveInstances[i].Name()] = &liveInstances[i]
+	}
+
+	c.println()
+	c.banner("               EC2 DRIFT DETECTION                 ")
+	c.printf("%sPlanned instances: %d\n", c.icon("📊 ", ""), len(planInstances))
+	c.printf("%sLive instances: %d\n", c.icon("☁️  ", ""), len(liveInstances))
+	c.printf("%sInstances with drift: %d\n", c.icon("⚠️  ", ""), len(results))
+	c.println()
+
+	if len(results) == 0 {
+		c.println(c.green("%sNo drift detected! All planned instances match live state.", c.icon("✅ ", "[+] ")))
+		return
+	}
+
+	for _, dr := range results {
+		instanceName := dr.BucketName // Reused field
+		c.println()
+		c.println(c.yellow("%s", consoleRule))
+		c.printf("%sInstance: %s\n", c.icon("🖥️  ", ""), c.cyan("%s", instanceName))
+
+		plannedInst := planMap[instanceName]
+		liveInst := liveMap[instanceName]
+
+		if dr.Unknown {
+			c.println(c.yellow("   %sUNKNOWN - live state could not be fetched", c.icon("❔ ", "[?] ")))
+			continue
+		}
+		if dr.Missing {
+			c.println(c.red("   %sMISSING - Instance not found in AWS", c.icon("❌ ", "[X] ")))
+			if plannedInst != nil {
+				c.printf("      Planned instance type: %s\n", plannedInst.InstanceType)
+				c.printf("      Planned AMI: %s\n", plannedInst.AMI)
+			}
+			continue
+		}
+
+		if dr.AclDiff && plannedInst != nil && liveInst != nil {
+			c.writeEC2Attributes(plannedInst, liveInst)
+		}
+
+		if len(dr.TagDiffs) > 0 {
+			c.println(c.yellow("   %sTag differences:", c.icon("🏷️  ", "")))
+			for _, k := range sortedKeys(dr.TagDiffs) {
+				c.printf("      • %s:\n", k)
+				c.writePlannedActual(fmt.Sprintf("%q", dr.TagDiffs[k][0]), fmt.Sprintf("%q", dr.TagDiffs[k][1]))
+			}
+		}
+		if len(dr.ExtraTags) > 0 {
+			c.println(c.blue("   %sExtra tags in AWS:", c.icon("🏷️  ", "")))
+			for _, k := range sortedKeys(dr.ExtraTags) {
+				c.printf("      • %s: %q\n", k, dr.ExtraTags[k])
+			}
+		}
+	}
+
+	c.println()
+	c.println(c.yellow("%s", consoleRule))
+}
+
+// writeEC2Attributes writes the instance attributes that differ between
+// the plan and AWS.
+func (c *consoleWriter) writeEC2Attributes(plan, live *models.EC2Instance) {
+	c.println(c.yellow("   %sAttribute differences:", c.icon("📋 ", "")))
+
+	attr := func(name string, planned, actual interface{}) {
+		c.printf("      • %s:\n", name)
+		c.writePlannedActual(fmt.Sprint(planned), fmt.Sprint(actual))
+	}
+	if plan.InstanceType != live.InstanceType {
+		attr("Instance Type", plan.InstanceType, live.InstanceType)
+	}
+	if plan.AMI != "" && plan.AMI != live.AMI {
+		attr("AMI", plan.AMI, live.AMI)
+	}
+	if plan.SubnetID != "" && plan.SubnetID != live.SubnetID {
+		attr("Subnet ID", plan.SubnetID, live.SubnetID)
+	}
+	if !detector.StringSlicesEqual(plan.SecurityGroupIDs, live.SecurityGroupIDs) {
+		attr("Security Groups", plan.SecurityGroupIDs, live.SecurityGroupIDs)
+	}
+	if plan.EBSOptimized != live.EBSOptimized {
+		attr("EBS Optimized", plan.EBSOptimized, live.EBSOptimized)
+	}
+	if plan.Monitoring != live.Monitoring {
+		attr("Detailed Monitoring", plan.Monitoring, live.Monitoring)
+	}
+	if plan.RootBlockDevice.VolumeType != "" && detector.BlockDeviceDrift(plan.RootBlockDevice, live.RootBlockDevice) {
+		attr("Root Volume", formatBlockDevice(plan.RootBlockDevice), formatBlockDevice(live.RootBlockDevice))
+	}
+	if detector.EBSVolumesDrift(plan.EBSBlockDevices, live.EBSBlockDevices) {
+		c.printf("      • EBS Volumes:\n")
+		for _, dev := range plan.EBSBlockDevices {
+			c.printf("        %s %s %s\n", c.red("- planned:"), dev.DeviceName, formatBlockDevice(dev))
+		}
+		for _, dev := range live.EBSBlockDevices {
+			c.printf("        %s %s %s\n", c.green("+ actual: "), dev.DeviceName, formatBlockDevice(dev))
+		}
+	}
+	if detector.MetadataOptionsDrift(plan.MetadataOptions, live.MetadataOptions) {
+		attr("Metadata Options", formatMetadataOptions(plan.MetadataOptions), formatMetadataOptions(live.MetadataOptions))
+	}
+	if detector.NetworkInterfacesDrift(plan.NetworkInterfaces, live.NetworkInterfaces) {
+		c.printf("      • Network Interfaces:\n")
+		for _, eni := range plan.NetworkInterfaces {
+			c.printf("        %s [%d] %s delete_on_termination=%v\n", c.red("- planned:"), eni.DeviceIndex, eni.NetworkInterfaceID, eni.DeleteOnTermination)
+		}
+		for _, eni := range live.NetworkInterfaces {
+			c.printf("        %s [%d] %s delete_on_termination=%v\n", c.green("+ actual: "), eni.DeviceIndex, eni.NetworkInterfaceID, eni.DeleteOnTermination)
+		}
+	}
+	if plan.UserDataHash != "" && plan.UserDataHash != live.UserDataHash {
+		attr("User Data (SHA-1)", plan.UserDataHash, live.UserDataHash)
+	}
+	if plan.DisableAPITermination != live.DisableAPITermination {
+		attr("Termination Protection", plan.DisableAPITermination, live.DisableAPITermination)
+	}
+	if plan.DisableAPIStop != live.DisableAPIStop {
+		attr("Stop Protection", plan.DisableAPIStop, live.DisableAPIStop)
+	}
+}
+
+// writePlannedActual writes a planned value and the actual value below it.
+func (c *consoleWriter) writePlannedActual(planned, actual string) {
+	c.printf("        %s %s\n", c.red("- planned:"), planned)
+	c.printf("        %s %s\n", c.green("+ actual: "), actual)
+}
+
+// formatBlockDevice renders the compared attributes of an EBS volume.
+func formatBlockDevice(d models.BlockDevice) string {
+	encrypted := "default"
+	if d.Encrypted != nil {
+		encrypted = strconv.FormatBool(*d.Encrypted)
+	}
+	return fmt.Sprintf("type=%s size=%d encrypted=%s iops=%d throughput=%d",
+		d.VolumeType, d.VolumeSize, encrypted, d.IOPS, d.Throughput)
+}
+
+// formatMetadataOptions renders the IMDS settings of an instance.
+func formatMetadataOptions(m models.MetadataOptions) string {
+	return fmt.Sprintf("http_tokens=%s http_endpoint=%s hop_limit=%d instance_metadata_tags=%s",
+		m.HTTPTokens, m.HTTPEndpoint, m.HTTPPutResponseHopLimit, m.InstanceMetadataTags)
+}
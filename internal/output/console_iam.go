@@ -0,0 +1,321 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/iampolicy"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// writeIAMDetails writes the IAM detail view: resource counts, then per
+// drifted resource each differing attribute, with statement-level diffs
+// of policy documents.
+func (c *consoleWriter) writeIAMDetails(results []detector.DriftResult, plan *models.IAMPlanResources, live *models.IAMLiveState) {
+	planRoles := make(map[string]*models.IAMRole, len(plan.Roles))
+	for i := range plan.Roles {
+		planRoles[plan.Roles[i].RoleName] = &plan.Roles[i]
+	}
+	planUsers := make(map[string]*models.IAMUser, len(plan.Users))
+	for i := range plan.Users {
+		planUsers[plan.Users[i].UserName] = &plan.Users[i]
+	}
+	planPolicies := make(map[string]*models.IAMPolicy, len(plan.Policies))
+	for i := range plan.Policies {
+		planPolicies[plan.Policies[i].PolicyName] = &plan.Policies[i]
+	}
+	planGroups := make(map[string]*models.IAMGroup, len(plan.Groups))
+	for i := range plan.Groups {
+		planGroups[plan.Groups[i].GroupName] = &plan.Groups[i]
+	}
+	planProfiles := make(map[string]*models.IAMInstanceProfile, len(plan.InstanceProfiles))
+	for i := range plan.InstanceProfiles {
+		planProfiles[plan.InstanceProfiles[i].InstanceProfileName] = &plan.InstanceProfiles[i]
+	}
+
+	liveRoles := make(map[string]*models.IAMRole, len(live.Roles))
+	for i := range live.Roles {
+		liveRoles[live.Roles[i].RoleName] = &live.Roles[i]
+	}
+	liveUsers := make(map[string]*models.IAMUser, len(live.Users))
+	for i := range live.Users {
+		liveUsers[live.Users[i].UserName] = &live.Users[i]
+	}
+	livePolicies := make(map[string]*models.IAMPolicy, len(live.Policies))
+	for i := range live.Policies {
+		livePolicies[live.Policies[i].PolicyName] = &live.Policies[i]
+	}
+	liveGroups := make(map[string]*models.IAMGroup, len(live.Groups))
+	for i := range live.Groups {
+		liveGroups[live.Groups[i].GroupName] = &live.Groups[i]
+	}
+	liveProfiles := make(map[string]*models.IAMInstanceProfile, len(live.InstanceProfiles))
+	for i := range live.InstanceProfiles {
+		liveProfiles[live.InstanceProfiles[i].InstanceProfileName] = &live.InstanceProfiles[i]
+	}
+
+	c.println()
+	c.banner("              IAM DRIFT DETECTION                  ")
+	c.printf("  Planned: %d roles, %d users, %d policies, %d groups, %d instance profiles (%d total)\n",
+		len(plan.Roles), len(plan.Users), len(plan.Policies), len(plan.Groups),
+		len(plan.InstanceProfiles), plan.TotalCount())
+	c.printf("  Live:    %d roles, %d users, %d policies, %d groups, %d instance profiles\n",
+		len(live.Roles), len(live.Users), len(live.Policies), len(live.Groups), len(live.InstanceProfiles))
+	c.printf("  Drifted: %d resources\n", len(results))
+	c.println()
+
+	if len(results) == 0 {
+		c.println(c.green("  No drift detected! All planned IAM resources match live state."))
+		return
+	}
+
+	for _, dr := range results {
+		name := dr.BucketName // Reused field
+		c.println()
+		c.println(c.yellow("%s", consoleRule))
+
+		label := "  "
+		if _, ok := planRoles[name]; ok {
+			label = "  Role: "
+		} else if _, ok := planUsers[name]; ok {
+			label = "  User: "
+		} else if _, ok := planPolicies[name]; ok {
+			label = "  Policy: "
+		} else if _, ok := planGroups[name]; ok {
+			label = "  Group: "
+		} else if _, ok := planProfiles[name]; ok {
+			label = "  Instance Profile: "
+		}
+		c.printf("%s%s\n", label, c.cyan("%s", name))
+
+		if dr.Unknown {
+			c.println(c.yellow("   UNKNOWN - live state could not be fetched"))
+			continue
+		}
+		if dr.Missing {
+			c.println(c.red("   MISSING - Resource not found in AWS"))
+			continue
+		}
+
+		if dr.AclDiff {
+			if p, ok := planRoles[name]; ok {
+				if l, ok := liveRoles[name]; ok {
+					c.writeIAMRoleDiffs(p, l)
+				}
+			}
+			if p, ok := planUsers[name]; ok {
+				if l, ok := liveUsers[name]; ok {
+					c.writeIAMUserDiffs(p, l)
+				}
+			}
+			if p, ok := planPolicies[name]; ok {
+				if l, ok := livePolicies[name]; ok {
+					c.writeIAMPolicyDiffs(p, l)
+				}
+			}
+			if p, ok := planGroups[name]; ok {
+				if l, ok := liveGroups[name]; ok {
+					c.writeIAMGroupDiffs(p, l)
+				}
+			}
+			if p, ok := planProfiles[name]; ok {
+				if l, ok := liveProfiles[name]; ok {
+					c.writeIAMInstanceProfileDiffs(p, l)
+				}
+			}
+		}
+
+		if len(dr.TagDiffs) > 0 {
+			c.println(c.yellow("   Tag differences:"))
+			for _, k := range sortedKeys(dr.TagDiffs) {
+				c.printf("      %s:\n", k)
+				c.writePlannedActual(fmt.Sprintf("%q", dr.TagDiffs[k][0]), fmt.Sprintf("%q", dr.TagDiffs[k][1]))
+			}
+		}
+		if len(dr.ExtraTags) > 0 {
+			c.println(c.blue("   Extra tags in AWS:"))
+			for _, k := range sortedKeys(dr.ExtraTags) {
+				c.printf("      %s: %q\n", k, dr.ExtraTags[k])
+			}
+		}
+	}
+
+	c.println()
+	c.println(c.yellow("%s", consoleRule))
+}
+
+// writeIAMAttribute writes one differing attribute of an IAM resource.
+func (c *consoleWriter) writeIAMAttribute(name string, planned, actual interface{}) {
+	c.printf("      %s:\n", name)
+	c.writePlannedActual(fmt.Sprint(planned), fmt.Sprint(actual))
+}
+
+// writeIAMRoleDiffs writes attribute-level diffs for an IAM role.
+func (c *consoleWriter) writeIAMRoleDiffs(plan, live *models.IAMRole) {
+	c.println(c.yellow("   Attribute differences:"))
+
+	if plan.AssumeRolePolicy != "" && !iampolicy.Equal(plan.AssumeRolePolicy, live.AssumeRolePolicy) {
+		c.printf("      Assume Role Policy:\n")
+		c.writePolicyDocumentDiff(plan.AssumeRolePolicy, live.AssumeRolePolicy, "        ")
+	}
+	if plan.MaxSessionDuration > 0 && plan.MaxSessionDuration != live.MaxSessionDuration {
+		c.writeIAMAttribute("Max Session Duration", plan.MaxSessionDuration, live.MaxSessionDuration)
+	}
+	if plan.Description != "" && plan.Description != live.Description {
+		c.writeIAMAttribute("Description", fmt.Sprintf("%q", plan.Description), fmt.Sprintf("%q", live.Description))
+	}
+	if plan.Path != "" && plan.Path != live.Path {
+		c.writeIAMAttribute("Path", plan.Path, live.Path)
+	}
+	if len(plan.AttachedPolicies) > 0 && !detector.StringSlicesEqual(plan.AttachedPolicies, live.AttachedPolicies) {
+		c.writeIAMAttribute("Attached Policies", plan.AttachedPolicies, live.AttachedPolicies)
+	}
+	if !plan.InlinePoliciesUnknown {
+		c.writeInlinePolicyDiffs(plan.InlinePolicies, live.InlinePolicies)
+	}
+	if plan.PermissionsBoundary != "" && plan.PermissionsBoundary != live.PermissionsBoundary {
+		c.writeIAMAttribute("Permissions Boundary", plan.PermissionsBoundary, valueOrNone(live.PermissionsBoundary))
+	}
+}
+
+// writeIAMUserDiffs writes attribute-level diffs for an IAM user.
+func (c *consoleWriter) writeIAMUserDiffs(plan, live *models.IAMUser) {
+	c.println(c.yellow("   Attribute differences:"))
+
+	if plan.Path != "" && plan.Path != live.Path {
+		c.writeIAMAttribute("Path", plan.Path, live.Path)
+	}
+	if len(plan.AttachedPolicies) > 0 && !detector.StringSlicesEqual(plan.AttachedPolicies, live.AttachedPolicies) {
+		c.writeIAMAttribute("Attached Policies", plan.AttachedPolicies, live.AttachedPolicies)
+	}
+	if !plan.InlinePoliciesUnknown {
+		c.writeInlinePolicyDiffs(plan.InlinePolicies, live.InlinePolicies)
+	}
+	if plan.PermissionsBoundary != "" && plan.PermissionsBoundary != live.PermissionsBoundary {
+		c.writeIAMAttribute("Permissions Boundary", plan.PermissionsBoundary, valueOrNone(live.PermissionsBoundary))
+	}
+}
+
+// writeIAMPolicyDiffs writes attribute-level diffs for an IAM policy.
+func (c *consoleWriter) writeIAMPolicyDiffs(plan, live *models.IAMPolicy) {
+	c.println(c.yellow("   Attribute differences:"))
+
+	if plan.PolicyDocument != "" && !iampolicy.Equal(plan.PolicyDocument, live.PolicyDocument) {
+		c.printf("      Policy Document:\n")
+		c.writePolicyDocumentDiff(plan.PolicyDocument, live.PolicyDocument, "        ")
+	}
+	if plan.Description != "" && plan.Description != live.Description {
+		c.writeIAMAttribute("Description", fmt.Sprintf("%q", plan.Description), fmt.Sprintf("%q", live.Description))
+	}
+	if plan.Path != "" && plan.Path != live.Path {
+		c.writeIAMAttribute("Path", plan.Path, live.Path)
+	}
+}
+
+// writeIAMGroupDiffs writes attribute-level diffs for an IAM group.
+func (c *consoleWriter) writeIAMGroupDiffs(plan, live *models.IAMGroup) {
+	c.println(c.yellow("   Attribute differences:"))
+
+	if plan.Path != "" && plan.Path != live.Path {
+		c.writeIAMAttribute("Path", plan.Path, live.Path)
+	}
+	if len(plan.AttachedPolicies) > 0 && !detector.StringSlicesEqual(plan.AttachedPolicies, live.AttachedPolicies) {
+		c.writeIAMAttribute("Attached Policies", plan.AttachedPolicies, live.AttachedPolicies)
+	}
+	if len(plan.Members) > 0 && !detector.StringSlicesEqual(plan.Members, live.Members) {
+		c.writeIAMAttribute("Members", plan.Members, live.Members)
+	}
+	if !plan.InlinePoliciesUnknown {
+		c.writeInlinePolicyDiffs(plan.InlinePolicies, live.InlinePolicies)
+	}
+}
+
+// writeIAMInstanceProfileDiffs writes attribute-level diffs for an IAM
+// instance profile.
+func (c *consoleWriter) writeIAMInstanceProfileDiffs(plan, live *models.IAMInstanceProfile) {
+	c.println(c.yellow("   Attribute differences:"))
+
+	if plan.Path != "" && plan.Path != live.Path {
+		c.writeIAMAttribute("Path", plan.Path, live.Path)
+	}
+	if len(plan.Roles) > 0 && !detector.StringSlicesEqual(plan.Roles, live.Roles) {
+		c.writeIAMAttribute("Roles", plan.Roles, live.Roles)
+	}
+}
+
+// writeInlinePolicyDiffs writes inline policies that were added, removed or
+// changed outside Terraform.
+func (c *consoleWriter) writeInlinePolicyDiffs(plan, live map[string]string) {
+	if detector.InlinePoliciesEqual(plan, live) {
+		return
+	}
+	c.printf("      Inline Policies:\n")
+	for _, name := range sortedKeys(plan) {
+		liveDoc, ok := live[name]
+		switch {
+		case !ok:
+			c.printf("        %s %s (removed from AWS)\n", c.red("-"), name)
+		case !iampolicy.Equal(plan[name], liveDoc):
+			c.printf("        %s %s (policy document changed)\n", c.yellow("~"), name)
+			c.writePolicyDocumentDiff(plan[name], liveDoc, "          ")
+		}
+	}
+	for _, name := range sortedKeys(live) {
+		if _, ok := plan[name]; !ok {
+			c.printf("        %s %s (not in plan)\n", c.green("+"), name)
+		}
+	}
+}
+
+// writePolicyDocumentDiff writes the statements added, removed or modified
+// between a planned and a live policy document, one entry per line.
+// Wildcard values are highlighted. If either document cannot be parsed,
+// only a one-line notice is written.
+func (c *consoleWriter) writePolicyDocumentDiff(plan, live, indent string) {
+	diff, err := iampolicy.DiffDocuments(plan, live)
+	if err != nil {
+		c.printf("%s%s (policy document changed)\n", indent, c.red("- planned differs from actual"))
+		return
+	}
+	if diff.ExpectedVersion != diff.ActualVersion {
+		c.printf("%sVersion: %s → %s\n", indent, valueOrNone(diff.ExpectedVersion), valueOrNone(diff.ActualVersion))
+	}
+	for _, s := range diff.Statements {
+		switch s.Kind {
+		case iampolicy.StatementRemoved:
+			c.printf("%s%s %s statement%s (removed from AWS)\n", indent, c.red("-"), s.ExpectedEffect, sidLabel(s.Sid))
+		case iampolicy.StatementAdded:
+			c.printf("%s%s %s statement%s (not in plan)\n", indent, c.green("+"), s.ActualEffect, sidLabel(s.Sid))
+		default:
+			effect := s.ActualEffect
+			if s.ExpectedEffect != s.ActualEffect {
+				effect = s.ExpectedEffect + " → " + s.ActualEffect
+			}
+			c.printf("%s%s %s statement%s (modified)\n", indent, c.yellow("~"), effect, sidLabel(s.Sid))
+		}
+		for _, e := range s.Removed {
+			c.printf("%s    %s %s\n", indent, c.red("-"), c.policyEntry(e))
+		}
+		for _, e := range s.Added {
+			c.printf("%s    %s %s\n", indent, c.green("+"), c.policyEntry(e))
+		}
+	}
+}
+
+// policyEntry formats a policy entry, highlighting wildcard values.
+func (c *consoleWriter) policyEntry(e iampolicy.Entry) string {
+	if e.Wildcard {
+		return fmt.Sprintf("%s: %s", e.Element, c.paint([]color.Attribute{color.FgHiRed, color.Bold}, "%s (wildcard)", e.Value))
+	}
+	return fmt.Sprintf("%s: %s", e.Element, e.Value)
+}
+
+// sidLabel returns ` "<sid>"` for a statement with a Sid, or "".
+func sidLabel(sid string) string {
+	if sid == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %q", sid)
+}
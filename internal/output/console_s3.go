@@ -0,0 +1,449 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// writeS3Details writes the S3 detail view: per bucket, each drifted
+// setting with its planned and live value, followed by a summary.
+func (c *consoleWriter) writeS3Details(results []detector.DriftResult, planBuckets, liveBuckets []models.S3Bucket) {
+	planMap := make(map[string]*models.S3Bucket, len(planBuckets))
+	for i := range planBuckets {
+		planMap[planBuckets[i].Name] = &planBuckets[i]
+	}
+	liveMap := make(map[string]*models.S3Bucket, len(liveBuckets))
+	for i := range liveBuckets {
+		liveMap[liveBuckets[i].Name] = &liveBuckets[i]
+	}
+
+	if len(results) == 0 {
+		c.println(c.green("%sNo drift detected!", c.icon("✅ ", "[+] ")))
+		return
+	}
+	c.println(c.yellow("%sDrift detected!", c.icon("⚠️  ", "[!] ")))
+
+	drifted, unknown := 0, 0
+	for _, r := range results {
+		c.println(c.yellow("%s%s", c.icon("🪣 ", "* "), r.BucketName))
+
+		if r.Unknown {
+			c.println(c.yellow("  %sUNKNOWN - live state could not be fetched", c.icon("❔ ", "[?] ")))
+			unknown++
+			c.println()
+			continue
+		}
+
+		planB, liveB := planMap[r.BucketName], liveMap[r.BucketName]
+		printed := c.writeS3Tags(r)
+		if r.AclDiff {
+			var planACL, liveACL string
+			if planB != nil {
+				planACL = formatACL(*planB)
+			}
+			if liveB != nil {
+				liveACL = formatACL(*liveB)
+			}
+			c.writeS3Mismatch("🔑 ", "ACL mismatch:", planACL, liveACL)
+			printed = true
+		}
+		if r.VersioningDiff {
+			var planVer, liveVer bool
+			if planB != nil {
+				planVer = planB.VersioningEnabled
+			}
+			if liveB != nil {
+				liveVer = liveB.VersioningEnabled
+			}
+			c.println(c.magenta("  %sVersioning mismatch:", c.icon("🔄 ", "")))
+			c.printf("    • expected → enabled: %s\n", c.yellow("%t", planVer))
+			c.printf("    • actual   → enabled: %s\n", c.red("%t", liveVer))
+			printed = true
+		}
+		if r.EncryptionDiff {
+			var planEnc, liveEnc string
+			if planB != nil {
+				planEnc = formatEncryption(*planB)
+			}
+			if liveB != nil {
+				liveEnc = formatEncryption(*liveB)
+			}
+			c.writeS3Mismatch("🔐 ", "Encryption mismatch:", planEnc, liveEnc)
+			printed = true
+		}
+		if r.LoggingDiff {
+			var planLog, liveLog models.S3Bucket
+			if planB != nil {
+				planLog = *planB
+			}
+			if liveB != nil {
+				liveLog = *liveB
+			}
+			c.writeS3Logging(planLog, liveLog)
+			printed = true
+		}
+		if r.PublicAccessBlockDiff {
+			var planPAB, livePAB models.PublicAccessBlockConfig
+			if planB != nil {
+				planPAB = planB.PublicAccessBlock
+			}
+			if liveB != nil {
+				livePAB = liveB.PublicAccessBlock
+			}
+			c.writeS3PublicAccessBlock(planPAB, livePAB)
+			printed = true
+		}
+		if r.LifecycleDiff {
+			var planLC, liveLC []models.LifecycleRuleSummary
+			if planB != nil {
+				planLC = planB.LifecycleRules
+			}
+			if liveB != nil {
+				liveLC = liveB.LifecycleRules
+			}
+			if c.writeS3Lifecycle(planLC, liveLC) {
+				printed = true
+			}
+		}
+		if planB != nil && liveB != nil {
+			if r.PolicyDiff {
+				c.writeS3Mismatch("📜 ", "Bucket policy mismatch:", compactJSON(planB.Policy), compactJSON(liveB.Policy))
+				printed = true
+			}
+			if r.OwnershipDiff {
+				c.writeS3Mismatch("👤 ", "Object ownership mismatch:", planB.ObjectOwnership, valueOrNone(liveB.ObjectOwnership))
+				printed = true
+			}
+			if r.CORSDiff {
+				c.println(c.cyan("  %sCORS rules:", c.icon("🌐 ", "")))
+				for _, rule := range planB.CORSRules {
+					c.printf("    • plan → %s\n", c.yellow("%s", formatCORSRule(rule)))
+				}
+				for _, rule := range liveB.CORSRules {
+					c.printf("    • live → %s\n", c.red("%s", formatCORSRule(rule)))
+				}
+				if len(liveB.CORSRules) == 0 {
+					c.printf("    • live → %s\n", c.red("none"))
+				}
+				printed = true
+			}
+			if r.ReplicationDiff {
+				c.println(c.cyan("  %sReplication:", c.icon("🔁 ", "")))
+				c.printf("    • plan → %s\n", c.yellow("%s", formatReplication(planB.Replication)))
+				c.printf("    • live → %s\n", c.red("%s", formatReplication(liveB.Replication)))
+				printed = true
+			}
+			if r.ObjectLockDiff {
+				c.writeS3Mismatch("🔒 ", "Object lock mismatch:", formatObjectLock(planB.ObjectLock), formatObjectLock(liveB.ObjectLock))
+				printed = true
+			}
+		}
+
+		if printed {
+			drifted++
+		} else {
+			c.println(c.green("  %sNo drift detected!", c.icon("✅ ", "[+] ")))
+		}
+		c.println()
+	}
+
+	total := len(planBuckets)
+	c.println(c.cyan("%s", strings.Repeat("═", 44)))
+	c.println(c.cyan("Summary:"))
+	c.printf("  S3 Buckets scanned: %d\n", total)
+	c.printf("  Buckets with drift: %d\n", drifted)
+	c.printf("  Buckets without drift: %d\n", total-drifted-unknown)
+	if unknown > 0 {
+		c.printf("  Buckets with unknown state: %d\n", unknown)
+	}
+	c.println(c.cyan("%s", strings.Repeat("═", 44)))
+}
+
+// writeS3Mismatch writes a setting whose expected and actual values are
+// shown on one line each.
+func (c *consoleWriter) writeS3Mismatch(emoji, title, expected, actual string) {
+	c.println(c.magenta("  %s%s", c.icon(emoji, ""), title))
+	c.printf("    • expected → %s\n", c.yellow("%s", expected))
+	c.printf("    • actual   → %s\n", c.red("%s", actual))
+}
+
+// writeS3Tags writes mismatched, missing and extra tags. It reports
+// whether any tag drift was written.
+func (c *consoleWriter) writeS3Tags(r detector.DriftResult) bool {
+	if len(r.TagDiffs) == 0 && len(r.ExtraTags) == 0 {
+		return false
+	}
+	c.println(c.cyan("  %sTags:", c.icon("🏷️  ", "")))
+	var mismatches, missing []string
+	for _, key := range sortedKeys(r.TagDiffs) {
+		expVal, liveVal := r.TagDiffs[key][0], r.TagDiffs[key][1]
+		if liveVal == "" {
+			missing = append(missing, fmt.Sprintf("%s:%s", key, expVal))
+		} else if expVal != liveVal {
+			mismatches = append(mismatches, fmt.Sprintf("%s:%s != %s:%s", key, expVal, key, liveVal))
+		}
+	}
+	printed := false
+	if len(mismatches) > 0 {
+		c.println(c.red("    %sMismatches:", c.icon("🔀 ", "")))
+		for _, m := range mismatches {
+			c.printf("        • %s\n", c.red("%s", m))
+		}
+		printed = true
+	}
+	if len(missing) > 0 {
+		c.println(c.yellow("    %sMissing:", c.icon("⚠️  ", "")))
+		for _, m := range missing {
+			c.printf("        • %s\n", c.yellow("%s", m))
+		}
+		printed = true
+	}
+	if len(r.ExtraTags) > 0 {
+		c.println(c.yellow("    %sExtra:", c.icon("➕ ", "")))
+		for _, key := range sortedKeys(r.ExtraTags) {
+			c.printf("        • %s\n", c.yellow("%s:%s", key, r.ExtraTags[key]))
+		}
+		printed = true
+	}
+	return printed
+}
+
+// writeS3Logging writes the planned logging settings and the live settings
+// that differ from them.
+func (c *consoleWriter) writeS3Logging(plan, live models.S3Bucket) {
+	planFields := []string{fmt.Sprintf("enabled=%t", plan.LoggingEnabled)}
+	if plan.LoggingTargetBucket != "" {
+		planFields = append(planFields, "bucket="+plan.LoggingTargetBucket)
+	}
+	if plan.LoggingTargetPrefix != "" {
+		planFields = append(planFields, "prefix="+plan.LoggingTargetPrefix)
+	}
+	liveFields := []string{fmt.Sprintf("enabled=%t", live.LoggingEnabled)}
+	if live.LoggingTargetBucket != plan.LoggingTargetBucket && live.LoggingTargetBucket != "" {
+		liveFields = append(liveFields, "bucket="+live.LoggingTargetBucket)
+	}
+	if live.LoggingTargetPrefix != plan.LoggingTargetPrefix && live.LoggingTargetPrefix != "" {
+		liveFields = append(liveFields, "prefix="+live.LoggingTargetPrefix)
+	}
+	c.println(c.cyan("  %sLogging:", c.icon("📝 ", "")))
+	c.printf("    • plan → %s\n", c.yellow("%s", strings.Join(planFields, ", ")))
+	c.printf("    • live → %s\n", c.red("%s", strings.Join(liveFields, ", ")))
+}
+
+// writeS3PublicAccessBlock writes the planned public access block settings
+// and the live settings that differ from them.
+func (c *consoleWriter) writeS3PublicAccessBlock(plan, live models.PublicAccessBlockConfig) {
+	settings := []struct {
+		name       string
+		plan, live bool
+	}{
+		{"BlockPublicAcls", plan.BlockPublicAcls, live.BlockPublicAcls},
+		{"IgnorePublicAcls", plan.IgnorePublicAcls, live.IgnorePublicAcls},
+		{"BlockPublicPolicy", plan.BlockPublicPolicy, live.BlockPublicPolicy},
+		{"RestrictPublicBuckets", plan.RestrictPublicBuckets, live.RestrictPublicBuckets},
+	}
+	planFields := make([]string, 0, len(settings))
+	liveFields := []string{}
+	for _, s := range settings {
+		planFields = append(planFields, fmt.Sprintf("%s=%t", s.name, s.plan))
+		if s.live != s.plan {
+			liveFields = append(liveFields, fmt.Sprintf("%s=%t", s.name, s.live))
+		}
+	}
+	c.println(c.cyan("  %sPublic Access Block differ:", c.icon("🚫 ", "")))
+	c.printf("    • plan → %s\n", c.yellow("%s", strings.Join(planFields, ", ")))
+	c.printf("    • live → %s\n", c.red("%s", strings.Join(liveFields, ", ")))
+}
+
+// writeS3Lifecycle writes lifecycle rules that were changed, deleted or
+// added outside Terraform. It reports whether any rule was written.
+func (c *consoleWriter) writeS3Lifecycle(planRules, liveRules []models.LifecycleRuleSummary) bool {
+	planByID := make(map[string]models.LifecycleRuleSummary, len(planRules))
+	for _, r := range planRules {
+		planByID[r.ID] = r
+	}
+	liveByID := make(map[string]models.LifecycleRuleSummary, len(liveRules))
+	for _, r := range liveRules {
+		liveByID[r.ID] = r
+	}
+	var mismatches, deleted, extras []string
+	for id, pr := range planByID {
+		if lr, ok := liveByID[id]; !ok {
+			deleted = append(deleted, id)
+		} else if !detector.LifecycleRuleEqual(pr, lr) {
+			mismatches = append(mismatches, id)
+		}
+	}
+	for id := range liveByID {
+		if _, ok := planByID[id]; !ok {
+			extras = append(extras, id)
+		}
+	}
+	sort.Strings(mismatches)
+	sort.Strings(deleted)
+	sort.Strings(extras)
+
+	c.println(c.cyan("  %sLifecycle rules:", c.icon("⏳ ", "")))
+	if len(mismatches) > 0 {
+		c.println(c.red("    • Mismatched rules:"))
+		for _, id := range mismatches {
+			c.printf("        – %s:\n", id)
+			c.printf("            • plan → %s\n", c.yellow("%s", formatLifecycleRule(planByID[id])))
+			c.printf("            • live → %s\n", c.red("%s", formatLifecycleRule(liveByID[id])))
+		}
+	}
+	if len(deleted) > 0 {
+		c.println(c.yellow("    %sDeleted rules:", c.icon("⚠️  ", "")))
+		for _, id := range deleted {
+			c.printf("        – %s: %s\n", id, formatLifecycleRule(planByID[id]))
+		}
+	}
+	if len(extras) > 0 {
+		c.println(c.yellow("    • Extra rules:"))
+		for _, id := range extras {
+			c.printf("        – %s: %s\n", id, formatLifecycleRule(liveByID[id]))
+		}
+	}
+	return len(mismatches)+len(deleted)+len(extras) > 0
+}
+
+// formatACL renders a bucket ACL as its canned name, or as its grant list
+// when the plan defines grants or no canned ACL matches.
+func formatACL(b models.S3Bucket) string {
+	if b.Acl != "" && len(b.AclGrants) == 0 {
+		return b.Acl
+	}
+	if b.Acl == "" && len(b.AclGrants) == 0 {
+		return "private"
+	}
+	grants := make([]string, len(b.AclGrants))
+	for i, g := range b.AclGrants {
+		grants[i] = g.Grantee + "=" + g.Permission
+	}
+	label := b.Acl
+	if label == "" {
+		label = "custom"
+	}
+	return fmt.Sprintf("%s [%s]", label, strings.Join(grants, ", "))
+}
+
+// formatEncryption renders the default encryption settings of a bucket.
+func formatEncryption(b models.S3Bucket) string {
+	out := fmt.Sprintf("%q", b.EncryptionAlgorithm)
+	if b.KMSKeyID != "" {
+		out += " key=" + b.KMSKeyID
+	}
+	return out + fmt.Sprintf(" bucket_key=%t", b.BucketKeyEnabled)
+}
+
+// compactJSON renders a JSON document on one line, or "none" if empty.
+func compactJSON(doc string) string {
+	if doc == "" {
+		return "none"
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(doc)); err != nil {
+		return doc
+	}
+	return buf.String()
+}
+
+// valueOrNone returns v, or "none" if it is empty.
+func valueOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// formatCORSRule renders a CORS rule on one line.
+func formatCORSRule(c models.CORSRule) string {
+	return fmt.Sprintf("origins=%v methods=%v headers=%v expose=%v max_age=%d",
+		c.AllowedOrigins, c.AllowedMethods, c.AllowedHeaders, c.ExposeHeaders, c.MaxAgeSeconds)
+}
+
+// formatReplication renders a replication configuration on one line.
+func formatReplication(r models.ReplicationConfig) string {
+	if r.Role == "" && len(r.Rules) == 0 {
+		return "none"
+	}
+	rules := make([]string, len(r.Rules))
+	for i, rule := range r.Rules {
+		rules[i] = fmt.Sprintf("%s(%s → %s)", rule.ID, rule.Status, rule.DestinationBucket)
+	}
+	return fmt.Sprintf("role=%s rules=[%s]", r.Role, strings.Join(rules, ", "))
+}
+
+// formatLifecycleRule renders a lifecycle rule's filter and actions on one
+// line, omitting unset fields.
+func formatLifecycleRule(r models.LifecycleRuleSummary) string {
+	parts := []string{r.Status}
+	if r.Prefix != "" {
+		parts = append(parts, "prefix="+r.Prefix)
+	}
+	if len(r.FilterTags) > 0 {
+		keys := sortedKeys(r.FilterTags)
+		tags := make([]string, len(keys))
+		for i, k := range keys {
+			tags[i] = k + "=" + r.FilterTags[k]
+		}
+		parts = append(parts, "tags={"+strings.Join(tags, ",")+"}")
+	}
+	if r.ObjectSizeGreaterThan > 0 {
+		parts = append(parts, fmt.Sprintf("size>%d", r.ObjectSizeGreaterThan))
+	}
+	if r.ObjectSizeLessThan > 0 {
+		parts = append(parts, fmt.Sprintf("size<%d", r.ObjectSizeLessThan))
+	}
+	if r.ExpirationDays > 0 {
+		parts = append(parts, fmt.Sprintf("expire=%dd", r.ExpirationDays))
+	}
+	if r.ExpirationDate != "" {
+		parts = append(parts, "expire="+r.ExpirationDate)
+	}
+	if r.ExpiredObjectDeleteMarker {
+		parts = append(parts, "expired_delete_markers")
+	}
+	if r.NoncurrentExpirationDays > 0 {
+		parts = append(parts, fmt.Sprintf("noncurrent_expire=%dd", r.NoncurrentExpirationDays))
+	}
+	if r.NewerNoncurrentVersions > 0 {
+		parts = append(parts, fmt.Sprintf("keep_versions=%d", r.NewerNoncurrentVersions))
+	}
+	for _, t := range r.Transitions {
+		parts = append(parts, "transition="+formatTransition(t))
+	}
+	for _, t := range r.NoncurrentTransitions {
+		parts = append(parts, "noncurrent_transition="+formatTransition(t))
+	}
+	if r.AbortIncompleteMultipartDays > 0 {
+		parts = append(parts, fmt.Sprintf("abort_multipart=%dd", r.AbortIncompleteMultipartDays))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatTransition renders a lifecycle transition as "<when>→<class>".
+func formatTransition(t models.LifecycleTransition) string {
+	when := fmt.Sprintf("%dd", t.Days)
+	if t.Date != "" {
+		when = t.Date
+	}
+	return when + "→" + t.StorageClass
+}
+
+// formatObjectLock renders an object lock configuration on one line.
+func formatObjectLock(o models.ObjectLockConfig) string {
+	if !o.Enabled {
+		return "disabled"
+	}
+	if o.Mode == "" {
+		return "enabled (no default retention)"
+	}
+	return fmt.Sprintf("enabled %s days=%d years=%d", o.Mode, o.Days, o.Years)
+}
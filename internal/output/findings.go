@@ -0,0 +1,252 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
+)
+
+// This file converts a scan into security findings, the common model behind
+// the ASFF and OCSF formatters. Each drifted or missing resource and each
+// policy violation or warning becomes one finding with a deterministic ID,
+// so re-importing the same scan updates findings instead of duplicating them.
+
+// securityFinding is one drift or policy finding about one resource.
+type securityFinding struct {
+	// ID is stable across scans for the same check, resource, account and
+	// region.
+	ID string
+
+	// Control is the policy ID, or DRIFT001 (missing) / DRIFT002 (drifted)
+	// for drift, matching the SARIF rule IDs.
+	Control string
+
+	Title       string
+	Description string
+	Remediation string
+
+	// Severity is one of severityOrder.
+	Severity string
+
+	// Warning is true for policy warnings, which do not fail the scan.
+	Warning bool
+
+	// Drift is true for drift findings.
+	Drift bool
+
+	Category   string
+	Frameworks []string
+
+	ResourceType    string
+	ResourceID      string
+	ResourceAddress string
+	Location        *tfconfig.Location
+
+	// ARN is the resource ARN, or "" if it cannot be derived reliably.
+	ARN string
+}
+
+// securityFindings converts the drift and policy results of a scan into
+// findings: drift first in scan order, then violations and warnings sorted
+// by severity.
+func securityFindings(result ScanResult) []securityFinding {
+	var findings []securityFinding
+
+	// Policy findings only carry the Terraform address; the AWS identifier
+	// comes from the drift entry for the same resource.
+	ids := make(map[string]string)
+	for _, d := range result.Drifts {
+		if d.ResourceAddress != "" {
+			ids[d.ResourceAddress] = d.ResourceID
+		}
+	}
+
+	for _, d := range reportedDrifts(result.Drifts) {
+		if d.Unknown {
+			continue
+		}
+		f := securityFinding{
+			Control:         "DRIFT002",
+			Severity:        normalizeSeverity(d.Severity),
+			Drift:           true,
+			ResourceType:    d.ResourceType,
+			ResourceID:      d.ResourceID,
+			ResourceAddress: d.ResourceAddress,
+			Location:        d.Location,
+		}
+		label := resourceLabel(d)
+		if d.Missing {
+			f.Control = "DRIFT001"
+			f.Title = fmt.Sprintf("Resource %s exists in the Terraform plan but not in AWS", label)
+			f.Description = "A resource defined in the Terraform plan does not exist in AWS. It may have been deleted outside of Terraform."
+			f.Remediation = "Run 'terraform apply' to create the resource, or remove it from the Terraform configuration if it is no longer needed."
+		} else {
+			rows := attributeRows(d, ", ")
+			parts := make([]string, len(rows))
+			for i, r := range rows {
+				if r.Extra {
+					parts[i] = fmt.Sprintf("%s: not in plan, actual %s", r.Attribute, r.Actual)
+				} else {
+					parts[i] = fmt.Sprintf("%s: expected %s, actual %s", r.Attribute, r.Expected, r.Actual)
+				}
+			}
+			f.Title = fmt.Sprintf("Resource %s has drifted from the Terraform plan", label)
+			f.Description = "Drifted attributes: " + strings.Join(parts, "; ")
+			f.Remediation = "Update the Terraform configuration to match AWS, or run 'terraform apply' to enforce the planned state."
+		}
+		findings = append(findings, f)
+	}
+
+	if pr := result.PolicyResult; pr != nil {
+		add := func(v PolicyViolationOutput, warning bool) {
+			title := v.PolicyName
+			if title == "" {
+				title = v.PolicyID
+			}
+			findings = append(findings, securityFinding{
+				Control:         v.PolicyID,
+				Title:           fmt.Sprintf("%s: %s", v.PolicyID, title),
+				Description:     v.Message,
+				Remediation:     v.Remediation,
+				Severity:        normalizeSeverity(v.Severity),
+				Warning:         warning,
+				Category:        v.Category,
+				Frameworks:      v.Frameworks,
+				ResourceType:    v.ResourceType,
+				ResourceID:      ids[v.ResourceAddress],
+				ResourceAddress: v.ResourceAddress,
+				Location:        v.Location,
+			})
+		}
+		for _, v := range sortedFindings(pr.Violations) {
+			add(v, false)
+		}
+		for _, v := range sortedFindings(pr.Warnings) {
+			add(v, true)
+		}
+	}
+
+	assignFindingIDs(findings, result.AccountID, result.Region)
+	for i := range findings {
+		findings[i].ARN = resourceARN(findings[i].ResourceType, findings[i].ResourceID, result.AccountID, result.Region)
+	}
+	return findings
+}
+
+// assignFindingIDs derives each finding's ID from the account, region,
+// control and resource. A policy can report several messages for the same
+// resource; only then is the message added to keep the IDs unique.
+func assignFindingIDs(findings []securityFinding, accountID, region string) {
+	key := func(f securityFinding) string {
+		resource := f.ResourceAddress
+		if resource == "" {
+			resource = f.ResourceType + "/" + f.ResourceID
+		}
+		return f.Control + "\x00" + resource
+	}
+
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[key(f)]++
+	}
+	for i := range findings {
+		f := &findings[i]
+		k := key(*f)
+		if counts[k] > 1 {
+			k += "\x00" + f.Description
+		}
+		f.ID = fmt.Sprintf("cloudrift/%s/%s/%s/%s", accountID, region, f.Control, fingerprint(accountID, region, k))
+	}
+}
+
+// normalizeSeverity maps policy and drift severities onto severityOrder.
+// Drift uses "warning" for attribute changes; unrecognised values are
+// treated as medium.
+func normalizeSeverity(severity string) string {
+	switch s := strings.ToLower(severity); s {
+	case "critical", "high", "medium", "low", "info":
+		return s
+	case "error":
+		return "high"
+	case "informational", "note":
+		return "info"
+	default:
+		return "medium"
+	}
+}
+
+// resourceARN returns the ARN of a resource when it can be derived from its
+// identifier alone. IAM ARNs include a path that the scan does not record,
+// so they are only returned when the identifier already is an ARN.
+func resourceARN(resourceType, id, accountID, region string) string {
+	if id == "" {
+		return ""
+	}
+	if strings.HasPrefix(id, "arn:") {
+		return id
+	}
+	partition := awsPartition(region)
+	switch resourceType {
+	case "aws_s3_bucket":
+		return fmt.Sprintf("arn:%s:s3:::%s", partition, id)
+	case "aws_instance":
+		if strings.HasPrefix(id, "i-") && accountID != "" && region != "" {
+			return fmt.Sprintf("arn:%s:ec2:%s:%s:instance/%s", partition, region, accountID, id)
+		}
+	}
+	return ""
+}
+
+// awsPartition returns the AWS partition of a region.
+func awsPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+// frameworkNames are the display names of the compliance frameworks used
+// by the built-in policies.
+var frameworkNames = map[string]string{
+	"gdpr":      "GDPR",
+	"hipaa":     "HIPAA",
+	"iso_27001": "ISO 27001",
+	"pci_dss":   "PCI DSS",
+	"soc2":      "SOC 2",
+}
+
+// frameworkRequirements returns the display names of frameworks, sorted.
+func frameworkRequirements(frameworks []string) []string {
+	if len(frameworks) == 0 {
+		return nil
+	}
+	names := make([]string, len(frameworks))
+	for i, fw := range frameworks {
+		name, ok := frameworkNames[fw]
+		if !ok {
+			name = strings.ToUpper(fw)
+		}
+		names[i] = name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// truncate shortens s to at most n bytes, ending with "..." when cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n - 3
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
@@ -3,13 +3,25 @@
 // Supported formats include:
 //   - Console: Colorized CLI output (default)
 //   - JSON: Machine-readable JSON format
+//   - NDJSON: One JSON record per line, streamed while the scan runs
 //   - SARIF: Static Analysis Results Interchange Format for GitHub/GitLab integration
+//   - Remediation: HCL patches that make the Terraform configuration match AWS
+//   - HTML: Self-contained report for auditors and archiving
+//   - JUnit: XML test report for CI test tabs (Jenkins, GitLab, Azure DevOps)
+//   - Markdown: Size-limited summary for pull request comments
+//   - ASFF: AWS Security Hub findings (BatchImportFindings request body)
+//   - OCSF: Compliance Finding events for SIEMs and security data lakes
+//   - OpenMetrics: Drift and compliance gauges for Prometheus
+//   - Template: User-supplied text/template, with built-in CSV and table templates
 package output
 
 import (
 	"io"
 
 	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
 )
 
 // PolicyOutput contains the results of policy evaluation in a JSON-friendly format.
@@ -28,50 +40,117 @@ type PolicyOutput struct {
 
 	// ComplianceResult contains compliance scoring data.
 	ComplianceResult *ComplianceOutput `json:"compliance,omitempty"`
+
+	// Rules describes every evaluated policy, including those without
+	// findings. It feeds rule metadata to formats such as SARIF and is not
+	// part of the JSON output.
+	Rules []PolicyRuleOutput `json:"-"`
+}
+
+// PolicyRuleOutput describes one evaluated policy.
+type PolicyRuleOutput struct {
+	ID          string
+	Name        string
+	Severity    string
+	Remediation string
+	Category    string
+	Frameworks  []string
 }
 
 // ComplianceOutput contains compliance scoring results.
 type ComplianceOutput struct {
-	OverallPercentage float64                   `json:"overall_percentage"`
-	TotalPolicies     int                       `json:"total_policies"`
-	PassingPolicies   int                       `json:"passing_policies"`
-	FailingPolicies   int                       `json:"failing_policies"`
-	Categories        map[string]CategoryScore  `json:"categories"`
-	Frameworks        map[string]FrameworkScore `json:"frameworks"`
-	ActiveFrameworks  []string                  `json:"active_frameworks,omitempty"`
+	// OverallPercentage is the share of evaluated policies that passed (0-100).
+	OverallPercentage float64 `json:"overall_percentage"`
+
+	// TotalPolicies is the number of evaluated policies.
+	TotalPolicies int `json:"total_policies"`
+
+	// PassingPolicies is the number of policies without findings.
+	PassingPolicies int `json:"passing_policies"`
+
+	// FailingPolicies is the number of policies with at least one finding.
+	FailingPolicies int `json:"failing_policies"`
+
+	// Categories holds the scores per policy category (e.g., "security").
+	Categories map[string]CategoryScore `json:"categories"`
+
+	// Frameworks holds the scores per compliance framework (e.g., "hipaa").
+	Frameworks map[string]FrameworkScore `json:"frameworks"`
+
+	// ActiveFrameworks lists the frameworks selected for the scan, if any.
+	ActiveFrameworks []string `json:"active_frameworks,omitempty"`
 }
 
 // CategoryScore holds pass/fail counts for a single policy category.
 type CategoryScore struct {
+	// Percentage is the share of the category's policies that passed (0-100).
 	Percentage float64 `json:"percentage"`
-	Passed     int     `json:"passed"`
-	Failed     int     `json:"failed"`
-	Total      int     `json:"total"`
+
+	// Passed is the number of the category's policies without findings.
+	Passed int `json:"passed"`
+
+	// Failed is the number of the category's policies with findings.
+	Failed int `json:"failed"`
+
+	// Total is the number of policies in the category.
+	Total int `json:"total"`
 }
 
 // FrameworkScore holds pass/fail counts for a single compliance framework.
 type FrameworkScore struct {
+	// Percentage is the share of the framework's policies that passed (0-100).
 	Percentage float64 `json:"percentage"`
-	Passed     int     `json:"passed"`
-	Failed     int     `json:"failed"`
-	Total      int     `json:"total"`
+
+	// Passed is the number of the framework's policies without findings.
+	Passed int `json:"passed"`
+
+	// Failed is the number of the framework's policies with findings.
+	Failed int `json:"failed"`
+
+	// Total is the number of policies in the framework.
+	Total int `json:"total"`
 }
 
 // PolicyViolationOutput represents a single policy violation in JSON output.
 type PolicyViolationOutput struct {
-	PolicyID        string   `json:"policy_id"`
-	PolicyName      string   `json:"policy_name"`
-	Message         string   `json:"message"`
-	Severity        string   `json:"severity"`
-	ResourceType    string   `json:"resource_type"`
-	ResourceAddress string   `json:"resource_address"`
-	Remediation     string   `json:"remediation,omitempty"`
-	Category        string   `json:"category,omitempty"`
-	Frameworks      []string `json:"frameworks,omitempty"`
+	// PolicyID is the policy identifier (e.g., "S3-001").
+	PolicyID string `json:"policy_id"`
+
+	// PolicyName is the human-readable policy name.
+	PolicyName string `json:"policy_name"`
+
+	// Message describes the finding for this resource.
+	Message string `json:"message"`
+
+	// Severity is the policy severity (critical, high, medium, low).
+	Severity string `json:"severity"`
+
+	// ResourceType is the Terraform resource type.
+	ResourceType string `json:"resource_type"`
+
+	// ResourceAddress is the Terraform resource address.
+	ResourceAddress string `json:"resource_address"`
+
+	// Remediation explains how to fix the finding.
+	Remediation string `json:"remediation,omitempty"`
+
+	// Category is the policy category (e.g., "security", "tagging").
+	Category string `json:"category,omitempty"`
+
+	// Frameworks lists the compliance frameworks the policy maps to.
+	Frameworks []string `json:"frameworks,omitempty"`
+
+	// Location is where the resource is declared in the Terraform
+	// configuration. It is only set when the configuration was parsed.
+	Location *tfconfig.Location `json:"location,omitempty"`
 }
 
 // ScanResult contains the complete results of a drift scan.
 type ScanResult struct {
+	// SchemaVersion is the version of the JSON output schema. The JSON
+	// formatter always sets it to the current version.
+	SchemaVersion string `json:"schema_version"`
+
 	// Service is the AWS service that was scanned (e.g., "s3", "ec2").
 	Service string `json:"service"`
 
@@ -90,6 +169,22 @@ type ScanResult struct {
 	// Drifts contains detailed drift information for each resource.
 	Drifts []detector.DriftInfo `json:"drifts"`
 
+	// Errors lists resources whose live state could not be fetched.
+	// Those resources appear in Drifts with Unknown set.
+	Errors []models.FetchError `json:"errors,omitempty"`
+
+	// Unmanaged lists live resources not referenced by the plan. It is
+	// only populated when unmanaged resource detection is enabled.
+	Unmanaged []detector.UnmanagedResource `json:"unmanaged,omitempty"`
+
+	// Remediation contains, per drifted resource address, the argument
+	// values that would make the Terraform configuration match AWS.
+	Remediation []remediation.Patch `json:"remediation,omitempty"`
+
+	// Incomplete is true if the scan was cancelled or timed out before it
+	// finished. Resources that were not fetched are reported as unknown.
+	Incomplete bool `json:"incomplete,omitempty"`
+
 	// PolicyResult contains policy evaluation results (nil if policies were skipped).
 	PolicyResult *PolicyOutput `json:"policy_result,omitempty"`
 
@@ -98,6 +193,24 @@ type ScanResult struct {
 
 	// Timestamp is when the scan was performed (ISO 8601).
 	Timestamp string `json:"timestamp"`
+
+	// Details holds the service-specific detector results the console
+	// detail views are rendered from. It is not part of the JSON output.
+	Details *ServiceDetails `json:"-"`
+}
+
+// ServiceDetails holds the raw results of a service scan.
+type ServiceDetails struct {
+	// Results are the drift results returned by the service detector.
+	Results []detector.DriftResult
+
+	// Plan is the planned state: []models.S3Bucket, []models.EC2Instance
+	// or *models.IAMPlanResources.
+	Plan interface{}
+
+	// Live is the live state: *models.S3LiveState, *models.EC2LiveState or
+	// *models.IAMLiveState (plain slices are accepted for S3 and EC2).
+	Live interface{}
 }
 
 // Formatter defines the interface for output formatters.
@@ -119,9 +232,21 @@ type Formatter interface {
 type FormatType string
 
 const (
-	FormatConsole FormatType = "console"
-	FormatJSON    FormatType = "json"
-	FormatSARIF   FormatType = "sarif"
+	FormatConsole     FormatType = "console"
+	FormatJSON        FormatType = "json"
+	FormatSARIF       FormatType = "sarif"
+	FormatRemediation FormatType = "remediation"
+	FormatHTML        FormatType = "html"
+	FormatJUnit       FormatType = "junit"
+	FormatMarkdown    FormatType = "markdown"
+	FormatASFF        FormatType = "asff"
+	FormatOCSF        FormatType = "ocsf"
+	FormatNDJSON      FormatType = "ndjson"
+	FormatOpenMetrics FormatType = "openmetrics"
+
+	// FormatTemplate renders a user-supplied template. It is not in the
+	// registry because it needs the template; use LoadTemplate.
+	FormatTemplate FormatType = "template"
 )
 
 // registry holds registered formatters.
@@ -0,0 +1,22 @@
+//go:build ignore
+
+// gen_schema writes schema/scan-result.schema.json from the output types.
+// Run it with "go generate ./internal/output".
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/inayathulla/cloudrift/internal/output/schemagen"
+)
+
+func main() {
+	data, err := schemagen.Generate("../..")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("schema/scan-result.schema.json", data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/remediation"
+)
+
+// htmlAssets holds the report template and the stylesheet and script that
+// are inlined into it, so the report works offline.
+//
+//go:embed html/report.html.tmpl html/report.css html/report.js
+var htmlAssets embed.FS
+
+// HTMLFormatter outputs scan results as a self-contained HTML report.
+//
+// The report is a single file with the CSS and JavaScript inlined and no
+// external requests, suitable for attaching to audits or archiving as a CI
+// artifact. It shows the compliance score with per-framework and
+// per-category bars, a filterable table of policy findings, and side-by-side
+// expected/actual values for each drifted resource.
+type HTMLFormatter struct {
+	tmpl *template.Template
+}
+
+// NewHTMLFormatter creates a new HTML formatter.
+func NewHTMLFormatter() *HTMLFormatter {
+	tmpl := template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+		"percent":    func(p float64) string { return fmt.Sprintf("%.1f%%", p) },
+		"barWidth":   func(p float64) template.CSS { return template.CSS(fmt.Sprintf("width: %.1f%%", p)) },
+		"scoreClass": scoreClass,
+		"join":       strings.Join,
+	}).ParseFS(htmlAssets, "html/report.html.tmpl"))
+	return &HTMLFormatter{tmpl: tmpl}
+}
+
+// htmlReport is the view model rendered by the report template.
+type htmlReport struct {
+	Result      ScanResult
+	Policy      bool
+	Compliance  *ComplianceOutput
+	Frameworks  []htmlScore
+	Categories  []htmlScore
+	Findings    []htmlFinding
+	Severities  []string
+	Drifts      []htmlDrift
+	Remediation string
+
+	ViolationCount int
+	WarningCount   int
+
+	CSS template.CSS
+	JS  template.JS
+}
+
+// htmlScore is one compliance bar.
+type htmlScore struct {
+	Name       string
+	Percentage float64
+	Passed     int
+	Total      int
+}
+
+// htmlFinding is one row of the policy findings table.
+type htmlFinding struct {
+	PolicyViolationOutput
+	Kind     string
+	Location string
+}
+
+// htmlDrift is one drifted resource.
+type htmlDrift struct {
+	Label    string
+	Status   string
+	Location string
+	Rows     []attributeRow
+}
+
+// Format writes the scan result as an HTML document.
+func (f *HTMLFormatter) Format(w io.Writer, result ScanResult) error {
+	css, err := htmlAssets.ReadFile("html/report.css")
+	if err != nil {
+		return err
+	}
+	js, err := htmlAssets.ReadFile("html/report.js")
+	if err != nil {
+		return err
+	}
+
+	report := htmlReport{
+		Result: result,
+		CSS:    template.CSS(css),
+		JS:     template.JS(js),
+	}
+
+	if pr := result.PolicyResult; pr != nil {
+		report.Policy = true
+		report.ViolationCount = len(pr.Violations)
+		report.WarningCount = len(pr.Warnings)
+
+		seen := make(map[string]bool)
+		add := func(v PolicyViolationOutput, kind string) {
+			finding := htmlFinding{PolicyViolationOutput: v, Kind: kind}
+			if v.Location != nil {
+				finding.Location = v.Location.String()
+			}
+			report.Findings = append(report.Findings, finding)
+			seen[v.Severity] = true
+		}
+		for _, v := range sortedFindings(pr.Violations) {
+			add(v, "violation")
+		}
+		for _, v := range sortedFindings(pr.Warnings) {
+			add(v, "warning")
+		}
+		for _, s := range severityOrder {
+			if seen[s] {
+				report.Severities = append(report.Severities, s)
+			}
+		}
+
+		if c := pr.ComplianceResult; c != nil {
+			report.Compliance = c
+			for _, name := range sortedKeys(c.Frameworks) {
+				s := c.Frameworks[name]
+				report.Frameworks = append(report.Frameworks, htmlScore{Name: name, Percentage: s.Percentage, Passed: s.Passed, Total: s.Total})
+			}
+			for _, name := range sortedKeys(c.Categories) {
+				s := c.Categories[name]
+				report.Categories = append(report.Categories, htmlScore{Name: name, Percentage: s.Percentage, Passed: s.Passed, Total: s.Total})
+			}
+		}
+	}
+
+	for _, d := range reportedDrifts(result.Drifts) {
+		drift := htmlDrift{
+			Label:  resourceLabel(d),
+			Status: driftStatus(d),
+			Rows:   attributeRows(d, "\n"),
+		}
+		if d.Location != nil {
+			drift.Location = d.Location.String()
+		}
+		report.Drifts = append(report.Drifts, drift)
+	}
+
+	if len(result.Remediation) > 0 {
+		report.Remediation = string(remediation.HCL(result.Remediation))
+	}
+
+	return f.tmpl.Execute(w, report)
+}
+
+// scoreClass grades a compliance percentage for colouring.
+func scoreClass(p float64) string {
+	switch {
+	case p >= 90:
+		return "good"
+	case p >= 70:
+		return "warn"
+	default:
+		return "bad"
+	}
+}
+
+// Name returns the format name.
+func (f *HTMLFormatter) Name() string {
+	return "html"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *HTMLFormatter) FileExtension() string {
+	return ".html"
+}
+
+func init() {
+	Register(FormatHTML, NewHTMLFormatter())
+}
@@ -0,0 +1,31 @@
+(function () {
+  var search = document.getElementById("finding-search");
+  var severity = document.getElementById("finding-severity");
+  var kind = document.getElementById("finding-kind");
+  var count = document.getElementById("finding-count");
+  if (!search) {
+    return;
+  }
+  var rows = document.querySelectorAll("#findings tbody tr");
+
+  function apply() {
+    var text = search.value.toLowerCase();
+    var shown = 0;
+    rows.forEach(function (row) {
+      var visible =
+        (!text || row.textContent.toLowerCase().indexOf(text) !== -1) &&
+        (!severity.value || row.dataset.severity === severity.value) &&
+        (!kind.value || row.dataset.kind === kind.value);
+      row.hidden = !visible;
+      if (visible) {
+        shown++;
+      }
+    });
+    count.textContent = shown + " of " + rows.length;
+  }
+
+  search.addEventListener("input", apply);
+  severity.addEventListener("change", apply);
+  kind.addEventListener("change", apply);
+  apply();
+})();
@@ -16,8 +16,10 @@ func NewJSONFormatter() *JSONFormatter {
 	return &JSONFormatter{}
 }
 
-// Format writes the scan result as JSON to the provided writer.
+// Format writes the scan result as JSON to the provided writer, stamped
+// with the current SchemaVersion.
 func (f *JSONFormatter) Format(w io.Writer, result ScanResult) error {
+	result.SchemaVersion = SchemaVersion
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(result)
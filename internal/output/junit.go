@@ -0,0 +1,266 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// JUnitFormatter outputs scan results as JUnit XML.
+//
+// Jenkins, GitLab CI and Azure DevOps render JUnit reports natively, so drift
+// and policy results show up in the pipeline's test tab without extra
+// tooling. The document has two test suites:
+//   - drift: one testcase per resource, failed if it drifted or is missing,
+//     errored if its live state could not be fetched
+//   - policy: one testcase per policy and resource, failed for violations;
+//     warnings come from advisory rules that never fail the scan, so they
+//     are passing testcases with the finding in system-out; policies without
+//     findings are a single passing testcase
+type JUnitFormatter struct{}
+
+// NewJUnitFormatter creates a new JUnit formatter.
+func NewJUnitFormatter() *JUnitFormatter {
+	return &JUnitFormatter{}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+type junitOutput struct {
+	Body string `xml:",cdata"`
+}
+
+// Format writes the scan result as a JUnit XML document.
+func (f *JUnitFormatter) Format(w io.Writer, result ScanResult) error {
+	elapsed := fmt.Sprintf("%.3f", float64(result.ScanDuration)/1000)
+
+	suites := []junitTestSuite{f.driftSuite(result)}
+	if result.PolicyResult != nil {
+		suites = append(suites, f.policySuite(result))
+	}
+
+	doc := junitTestSuites{Name: "cloudrift", Time: elapsed}
+	for i := range suites {
+		s := &suites[i]
+		s.Time = elapsed
+		s.Timestamp = result.Timestamp
+		s.Properties = junitProperties(result)
+		for _, c := range s.Cases {
+			switch {
+			case c.Failure != nil:
+				s.Failures++
+			case c.Error != nil:
+				s.Errors++
+			}
+		}
+		s.Tests = len(s.Cases)
+
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+	}
+	doc.Suites = suites
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// driftSuite builds one testcase per scanned resource.
+func (f *JUnitFormatter) driftSuite(result ScanResult) junitTestSuite {
+	fetchErrors := make(map[string]string)
+	for _, e := range result.Errors {
+		msg := e.Message
+		if e.ErrorCode != "" {
+			msg = e.ErrorCode + ": " + msg
+		}
+		fetchErrors[models.ResourceKey(e.ResourceType, e.Resource)] = fmt.Sprintf("%s failed: %s", e.Operation, msg)
+	}
+
+	suite := junitTestSuite{Name: "cloudrift.drift." + strings.ToLower(result.Service)}
+	for _, d := range result.Drifts {
+		tc := junitTestCase{
+			Name:      resourceLabel(d),
+			ClassName: "drift." + d.ResourceType,
+		}
+		if d.Location != nil {
+			tc.File, tc.Line = d.Location.File, d.Location.StartLine
+		}
+
+		switch driftStatus(d) {
+		case "unknown":
+			// EC2 errors are recorded by instance ID, the others by name
+			body := fetchErrors[models.ResourceKey(d.ResourceType, d.ResourceID)]
+			if body == "" {
+				body = fetchErrors[models.ResourceKey(d.ResourceType, d.ResourceName)]
+			}
+			if body == "" {
+				body = "live state could not be fetched"
+			}
+			tc.Error = &junitProblem{Message: "drift could not be determined", Type: "unknown", Body: body}
+		case "missing":
+			tc.Failure = &junitProblem{Message: "resource is in the plan but not in AWS", Type: "missing"}
+		case "drifted":
+			tc.Failure = &junitProblem{
+				Message: fmt.Sprintf("%d attribute(s) drifted", len(d.Diffs)+len(d.ExtraAttributes)),
+				Type:    "drift",
+				Body:    junitDiff(attributeRows(d, ", ")),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
+
+// policySuite builds one testcase per policy finding, plus a passing
+// testcase for each evaluated policy without findings.
+func (f *JUnitFormatter) policySuite(result ScanResult) junitTestSuite {
+	pr := result.PolicyResult
+	suite := junitTestSuite{Name: "cloudrift.policy." + strings.ToLower(result.Service)}
+
+	reported := make(map[string]bool)
+	finding := func(v PolicyViolationOutput) junitTestCase {
+		reported[v.PolicyID] = true
+		tc := junitTestCase{
+			Name:      v.ResourceAddress,
+			ClassName: "policy." + v.PolicyID,
+		}
+		if v.Location != nil {
+			tc.File, tc.Line = v.Location.File, v.Location.StartLine
+		}
+		return tc
+	}
+
+	for _, v := range sortedFindings(pr.Violations) {
+		tc := finding(v)
+		tc.Failure = &junitProblem{Message: v.Message, Type: v.Severity, Body: junitPolicyBody(v)}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, v := range sortedFindings(pr.Warnings) {
+		tc := finding(v)
+		tc.SystemOut = &junitOutput{Body: "warning: " + v.Message + "\n" + junitPolicyBody(v)}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, rule := range pr.Rules {
+		if reported[rule.ID] {
+			continue
+		}
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      "all resources",
+			ClassName: "policy." + rule.ID,
+		})
+	}
+	return suite
+}
+
+// junitDiff renders attribute rows as the body of a drift failure.
+func junitDiff(rows []attributeRow) string {
+	var b strings.Builder
+	for _, r := range rows {
+		if r.Extra {
+			fmt.Fprintf(&b, "%s (not in plan)\n  actual:   %s\n", r.Attribute, r.Actual)
+			continue
+		}
+		fmt.Fprintf(&b, "%s\n  expected: %s\n  actual:   %s\n", r.Attribute, r.Expected, r.Actual)
+	}
+	return b.String()
+}
+
+// junitPolicyBody renders the details of a policy violation.
+func junitPolicyBody(v PolicyViolationOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Policy: %s", v.PolicyID)
+	if v.PolicyName != "" {
+		fmt.Fprintf(&b, " (%s)", v.PolicyName)
+	}
+	fmt.Fprintf(&b, "\nSeverity: %s\n", v.Severity)
+	if v.Location != nil {
+		fmt.Fprintf(&b, "Location: %s\n", v.Location)
+	}
+	if v.Remediation != "" {
+		fmt.Fprintf(&b, "Remediation: %s\n", v.Remediation)
+	}
+	if len(v.Frameworks) > 0 {
+		fmt.Fprintf(&b, "Frameworks: %s\n", strings.Join(v.Frameworks, ", "))
+	}
+	return b.String()
+}
+
+// junitProperties records the scan context on each test suite.
+func junitProperties(result ScanResult) []junitProperty {
+	var props []junitProperty
+	if result.AccountID != "" {
+		props = append(props, junitProperty{Name: "account_id", Value: result.AccountID})
+	}
+	if result.Region != "" {
+		props = append(props, junitProperty{Name: "region", Value: result.Region})
+	}
+	if result.Incomplete {
+		props = append(props, junitProperty{Name: "incomplete", Value: "true"})
+	}
+	return props
+}
+
+// Name returns the format name.
+func (f *JUnitFormatter) Name() string {
+	return "junit"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *JUnitFormatter) FileExtension() string {
+	return ".xml"
+}
+
+func init() {
+	Register(FormatJUnit, NewJUnitFormatter())
+}
@@ -0,0 +1,275 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/remediation"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
+)
+
+// DefaultMarkdownLength keeps the summary under GitHub's 65,536 character
+// limit for pull request comments, with room for a bot's own header.
+const DefaultMarkdownLength = 65000
+
+// markdownReserve is the space kept free for closing tags and truncation
+// notes once the length limit is reached.
+const markdownReserve = 512
+
+// MarkdownFormatter outputs scan results as a GitHub/GitLab-flavoured
+// Markdown summary for pull request comments.
+//
+// The summary starts with the counts, compliance score and a drift table;
+// per-resource diffs, policy findings and remediation follow in collapsible
+// <details> sections. When the document would exceed MaxLength, table rows
+// and detail entries are dropped from the end and replaced by a note saying
+// how many were omitted, so the comment is always postable.
+type MarkdownFormatter struct {
+	// MaxLength is the maximum length of the output in bytes, or 0 for no
+	// limit.
+	MaxLength int
+}
+
+// NewMarkdownFormatter creates a new Markdown formatter limited to
+// DefaultMarkdownLength.
+func NewMarkdownFormatter() *MarkdownFormatter {
+	return &MarkdownFormatter{MaxLength: DefaultMarkdownLength}
+}
+
+// markdownWriter accumulates the document and tracks the length budget.
+type markdownWriter struct {
+	b     strings.Builder
+	limit int
+}
+
+// fits reports whether s can be added while leaving the reserve free.
+func (m *markdownWriter) fits(s string) bool {
+	return m.limit <= 0 || m.b.Len()+len(s)+markdownReserve <= m.limit
+}
+
+func (m *markdownWriter) write(s string) {
+	m.b.WriteString(s)
+}
+
+// items writes entries until the budget runs out, then a note naming how
+// many were left out. It returns false if anything was omitted.
+func (m *markdownWriter) items(entries []string, noun string) bool {
+	for i, e := range entries {
+		if !m.fits(e) {
+			m.write(fmt.Sprintf("\n_%d more %s omitted to fit the comment size limit._\n", len(entries)-i, noun))
+			return false
+		}
+		m.write(e)
+	}
+	return true
+}
+
+// Format writes the scan result as Markdown.
+func (f *MarkdownFormatter) Format(w io.Writer, result ScanResult) error {
+	m := &markdownWriter{limit: f.MaxLength}
+	complete := true
+
+	m.write(fmt.Sprintf("## Cloudrift %s scan\n\n", result.Service))
+	if result.Incomplete {
+		m.write("> [!WARNING]\n> Scan incomplete: resources that were not fetched are reported as unknown.\n\n")
+	}
+
+	summary := []string{
+		fmt.Sprintf("**%d** resources scanned", result.TotalResources),
+		fmt.Sprintf("**%d** with drift", result.DriftCount),
+	}
+	if pr := result.PolicyResult; pr != nil {
+		summary = append(summary,
+			fmt.Sprintf("**%d** policy violations", len(pr.Violations)),
+			fmt.Sprintf("**%d** warnings", len(pr.Warnings)))
+	}
+	if len(result.Errors) > 0 {
+		summary = append(summary, fmt.Sprintf("**%d** unknown", len(result.Errors)))
+	}
+	if len(result.Unmanaged) > 0 {
+		summary = append(summary, fmt.Sprintf("**%d** unmanaged", len(result.Unmanaged)))
+	}
+	m.write(strings.Join(summary, " · ") + "\n\n")
+
+	if pr := result.PolicyResult; pr != nil {
+		if c := pr.ComplianceResult; c != nil {
+			m.write(fmt.Sprintf("**Compliance: %.1f%%** (%d/%d policies passing)\n\n", c.OverallPercentage, c.PassingPolicies, c.TotalPolicies))
+			if len(c.Frameworks) > 0 {
+				m.write("| Framework | Score | Passing |\n|---|---:|---:|\n")
+				for _, name := range sortedKeys(c.Frameworks) {
+					s := c.Frameworks[name]
+					m.write(fmt.Sprintf("| %s | %.1f%% | %d/%d |\n", mdText(name), s.Percentage, s.Passed, s.Total))
+				}
+				m.write("\n")
+			}
+		}
+
+		if len(pr.Violations)+len(pr.Warnings) > 0 {
+			violations, warnings := severityCounts(pr.Violations), severityCounts(pr.Warnings)
+			m.write("| Severity | Violations | Warnings |\n|---|---:|---:|\n")
+			for _, s := range severityOrder {
+				if violations[s]+warnings[s] > 0 {
+					m.write(fmt.Sprintf("| %s | %d | %d |\n", s, violations[s], warnings[s]))
+				}
+			}
+			if v, w := unrankedCount(violations), unrankedCount(warnings); v+w > 0 {
+				m.write(fmt.Sprintf("| other | %d | %d |\n", v, w))
+			}
+			m.write("\n")
+		}
+	}
+
+	drifts := reportedDrifts(result.Drifts)
+	if len(drifts) == 0 {
+		m.write("No drift detected.\n")
+	} else {
+		m.write("### Drift\n\n| Resource | Status | Attributes | Location |\n|---|---|---|---|\n")
+		rows := make([]string, len(drifts))
+		for i, d := range drifts {
+			attrs := make([]string, 0, len(d.Diffs)+len(d.ExtraAttributes))
+			for _, r := range attributeRows(d, ", ") {
+				attrs = append(attrs, r.Attribute)
+			}
+			rows[i] = fmt.Sprintf("| %s | %s | %s | %s |\n", mdCode(resourceLabel(d)), driftStatus(d), mdText(strings.Join(attrs, ", ")), mdLocation(d.Location))
+		}
+		complete = m.items(rows, "resources") && complete
+
+		var details []string
+		for _, d := range drifts {
+			if rows := attributeRows(d, ", "); len(rows) > 0 {
+				details = append(details, mdDriftDetail(resourceLabel(d), rows))
+			}
+		}
+		if len(details) > 0 && m.fits("") {
+			m.write(fmt.Sprintf("\n<details>\n<summary>Drift details (%d)</summary>\n", len(details)))
+			complete = m.items(details, "resources") && complete
+			m.write("\n</details>\n")
+		}
+	}
+
+	if pr := result.PolicyResult; pr != nil && len(pr.Violations)+len(pr.Warnings) > 0 && m.fits("") {
+		var findings []string
+		for _, v := range sortedFindings(pr.Violations) {
+			findings = append(findings, mdFinding(v, ""))
+		}
+		for _, v := range sortedFindings(pr.Warnings) {
+			findings = append(findings, mdFinding(v, " (warning)"))
+		}
+		m.write(fmt.Sprintf("\n<details>\n<summary>Policy findings (%d)</summary>\n\n", len(findings)))
+		complete = m.items(findings, "findings") && complete
+		m.write("\n</details>\n")
+	}
+
+	if len(result.Remediation) > 0 && m.fits("") {
+		blocks := make([]string, len(result.Remediation))
+		for i, p := range result.Remediation {
+			blocks[i] = string(remediation.HCL([]remediation.Patch{p}))
+			if i > 0 {
+				blocks[i] = "\n" + blocks[i]
+			}
+		}
+		m.write(fmt.Sprintf("\n<details>\n<summary>Remediation (%d)</summary>\n\n```hcl\n", len(result.Remediation)))
+		ok := true
+		for i, block := range blocks {
+			if !m.fits(block) {
+				m.write(fmt.Sprintf("```\n\n_%d more patches omitted to fit the comment size limit._\n", len(blocks)-i))
+				ok = false
+				break
+			}
+			m.write(block)
+		}
+		if ok {
+			m.write("```\n")
+		}
+		complete = ok && complete
+		m.write("\n</details>\n")
+	}
+
+	if !complete {
+		m.write("\n_Output truncated. Use `--format=html` or `--format=json` for the full report._\n")
+	}
+
+	_, err := io.WriteString(w, m.b.String())
+	return err
+}
+
+// mdDriftDetail renders the attribute diff of one resource.
+func mdDriftDetail(label string, rows []attributeRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n#### %s\n\n| Attribute | Expected (plan) | Actual (AWS) |\n|---|---|---|\n", mdCode(label))
+	for _, r := range rows {
+		expected := mdCode(r.Expected)
+		if r.Extra {
+			expected = "_not in plan_"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", mdText(r.Attribute), expected, mdCode(r.Actual))
+	}
+	return b.String()
+}
+
+// mdFinding renders one policy finding as a list item.
+func mdFinding(v PolicyViolationOutput, suffix string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- **%s** %s%s: %s on %s", v.Severity, mdText(v.PolicyID), suffix, mdText(v.Message), mdCode(v.ResourceAddress))
+	if v.Location != nil {
+		fmt.Fprintf(&b, " (%s)", mdLocation(v.Location))
+	}
+	b.WriteString("\n")
+	if v.Remediation != "" {
+		fmt.Fprintf(&b, "  - Remediation: %s\n", mdText(v.Remediation))
+	}
+	return b.String()
+}
+
+// unrankedCount sums the counts of severities outside severityOrder.
+func unrankedCount(counts map[string]int) int {
+	n := 0
+	for s, c := range counts {
+		if severityRank(s) == len(severityOrder) {
+			n += c
+		}
+	}
+	return n
+}
+
+// mdLocation renders a source location, or an empty string.
+func mdLocation(loc *tfconfig.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return mdCode(loc.String())
+}
+
+// mdText escapes text for a Markdown table cell or list item. HTML is
+// escaped because GitHub renders inline tags.
+func mdText(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "\\|", "\r", "", "\n", "<br>")
+	return r.Replace(s)
+}
+
+// mdCode renders s as an inline code span that is safe inside a table.
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.NewReplacer("|", "\\|", "\r", "", "\n", " ").Replace(s)
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// Name returns the format name.
+func (f *MarkdownFormatter) Name() string {
+	return "markdown"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *MarkdownFormatter) FileExtension() string {
+	return ".md"
+}
+
+func init() {
+	Register(FormatMarkdown, NewMarkdownFormatter())
+}
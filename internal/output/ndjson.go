@@ -0,0 +1,201 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+)
+
+// NDJSON record types, in the order a scan produces them.
+const (
+	RecordError       = "error"
+	RecordDrift       = "drift"
+	RecordUnmanaged   = "unmanaged"
+	RecordRemediation = "remediation"
+	RecordViolation   = "violation"
+	RecordWarning     = "warning"
+	RecordSummary     = "summary"
+)
+
+// ndjsonRecord is one line of NDJSON output. Exactly one payload field is
+// set, named after the record type.
+type ndjsonRecord struct {
+	Type      string `json:"type"`
+	Service   string `json:"service"`
+	AccountID string `json:"account_id,omitempty"`
+
+	Error       *models.FetchError          `json:"error,omitempty"`
+	Drift       *detector.DriftInfo         `json:"drift,omitempty"`
+	Unmanaged   *detector.UnmanagedResource `json:"unmanaged,omitempty"`
+	Remediation *remediation.Patch          `json:"remediation,omitempty"`
+	Violation   *PolicyViolationOutput      `json:"violation,omitempty"`
+	Warning     *PolicyViolationOutput      `json:"warning,omitempty"`
+	Summary     *ndjsonSummary              `json:"summary,omitempty"`
+}
+
+// ndjsonSummary is the payload of the final record. It carries the scan
+// totals, so consumers do not have to count records.
+type ndjsonSummary struct {
+	SchemaVersion  string            `json:"schema_version"`
+	Region         string            `json:"region,omitempty"`
+	TotalResources int               `json:"total_resources"`
+	DriftCount     int               `json:"drift_count"`
+	Errors         int               `json:"errors"`
+	Unmanaged      int               `json:"unmanaged"`
+	Violations     int               `json:"violations"`
+	Warnings       int               `json:"warnings"`
+	Compliance     *ComplianceOutput `json:"compliance,omitempty"`
+	Incomplete     bool              `json:"incomplete,omitempty"`
+	ScanDuration   int64             `json:"scan_duration_ms"`
+	Timestamp      string            `json:"timestamp"`
+}
+
+// NDJSONWriter writes scan results as newline-delimited JSON while the scan
+// runs: one record per fetch error, drift, unmanaged resource, remediation
+// patch, policy violation and warning, followed by a summary record.
+//
+// Records are written as soon as they are passed in, so log shippers can
+// tail the output; the scan command passes in fetch errors and drifts
+// resource by resource while the live state is fetched. NDJSONWriter is
+// safe for concurrent use. The first write error is kept and returned by
+// every later call.
+type NDJSONWriter struct {
+	mu        sync.Mutex
+	enc       *json.Encoder
+	service   string
+	accountID string
+	err       error
+}
+
+// NewNDJSONWriter creates a writer for the records of one service scan.
+//
+// Parameters:
+//   - w: destination of the records
+//   - service: the scanned service, repeated in every record
+//   - accountID: the scanned AWS account, repeated in every record
+//
+// Returns:
+//   - *NDJSONWriter: writer ready for records
+func NewNDJSONWriter(w io.Writer, service, accountID string) *NDJSONWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &NDJSONWriter{enc: enc, service: service, accountID: accountID}
+}
+
+// WriteError writes a fetch error record.
+func (n *NDJSONWriter) WriteError(e models.FetchError) error {
+	return n.write(ndjsonRecord{Type: RecordError, Error: &e})
+}
+
+// WriteDrift writes a drift record.
+func (n *NDJSONWriter) WriteDrift(d detector.DriftInfo) error {
+	return n.write(ndjsonRecord{Type: RecordDrift, Drift: &d})
+}
+
+// WriteUnmanaged writes an unmanaged resource record.
+func (n *NDJSONWriter) WriteUnmanaged(u detector.UnmanagedResource) error {
+	return n.write(ndjsonRecord{Type: RecordUnmanaged, Unmanaged: &u})
+}
+
+// WriteRemediation writes a remediation patch record.
+func (n *NDJSONWriter) WriteRemediation(p remediation.Patch) error {
+	return n.write(ndjsonRecord{Type: RecordRemediation, Remediation: &p})
+}
+
+// WriteViolation writes a policy violation record.
+func (n *NDJSONWriter) WriteViolation(v PolicyViolationOutput) error {
+	return n.write(ndjsonRecord{Type: RecordViolation, Violation: &v})
+}
+
+// WriteWarning writes a policy warning record.
+func (n *NDJSONWriter) WriteWarning(v PolicyViolationOutput) error {
+	return n.write(ndjsonRecord{Type: RecordWarning, Warning: &v})
+}
+
+// WriteSummary writes the summary record of a finished scan. The counts are
+// taken from result, not from the records written before.
+func (n *NDJSONWriter) WriteSummary(result ScanResult) error {
+	s := &ndjsonSummary{
+		SchemaVersion:  SchemaVersion,
+		Region:         result.Region,
+		TotalResources: result.TotalResources,
+		DriftCount:     result.DriftCount,
+		Errors:         len(result.Errors),
+		Unmanaged:      len(result.Unmanaged),
+		Incomplete:     result.Incomplete,
+		ScanDuration:   result.ScanDuration,
+		Timestamp:      result.Timestamp,
+	}
+	if pr := result.PolicyResult; pr != nil {
+		s.Violations = len(pr.Violations)
+		s.Warnings = len(pr.Warnings)
+		s.Compliance = pr.ComplianceResult
+	}
+	return n.write(ndjsonRecord{Type: RecordSummary, Summary: s})
+}
+
+// write encodes one record on its own line.
+func (n *NDJSONWriter) write(r ndjsonRecord) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	r.Service = n.service
+	r.AccountID = n.accountID
+	n.err = n.enc.Encode(r)
+	return n.err
+}
+
+// NDJSONFormatter outputs a finished scan result as newline-delimited JSON,
+// with the same records NDJSONWriter streams during a scan.
+type NDJSONFormatter struct{}
+
+// NewNDJSONFormatter creates a new NDJSON formatter.
+func NewNDJSONFormatter() *NDJSONFormatter {
+	return &NDJSONFormatter{}
+}
+
+// Format writes every record of the scan result, then the summary record.
+func (f *NDJSONFormatter) Format(w io.Writer, result ScanResult) error {
+	n := NewNDJSONWriter(w, result.Service, result.AccountID)
+	for _, e := range result.Errors {
+		n.WriteError(e)
+	}
+	for _, d := range result.Drifts {
+		n.WriteDrift(d)
+	}
+	for _, u := range result.Unmanaged {
+		n.WriteUnmanaged(u)
+	}
+	for _, p := range result.Remediation {
+		n.WriteRemediation(p)
+	}
+	if pr := result.PolicyResult; pr != nil {
+		for _, v := range pr.Violations {
+			n.WriteViolation(v)
+		}
+		for _, v := range pr.Warnings {
+			n.WriteWarning(v)
+		}
+	}
+	return n.WriteSummary(result)
+}
+
+// Name returns the format name.
+func (f *NDJSONFormatter) Name() string {
+	return "ndjson"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *NDJSONFormatter) FileExtension() string {
+	return ".ndjson"
+}
+
+func init() {
+	Register(FormatNDJSON, NewNDJSONFormatter())
+}
@@ -0,0 +1,233 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// OCSFFormatter outputs scan results as OCSF Compliance Finding events
+// (class 2003, schema 1.1.0) for SIEMs and security data lakes.
+//
+// Each drifted or missing resource and each policy violation or warning is
+// one event, written as a JSON array. finding_info.uid is deterministic and
+// matches the ASFF finding ID, so consumers can deduplicate across scans.
+//
+// Specification: https://schema.ocsf.io/1.1.0/classes/compliance_finding
+type OCSFFormatter struct{}
+
+// NewOCSFFormatter creates a new OCSF formatter.
+func NewOCSFFormatter() *OCSFFormatter {
+	return &OCSFFormatter{}
+}
+
+type ocsfEvent struct {
+	ActivityID   int    `json:"activity_id"`
+	ActivityName string `json:"activity_name"`
+	CategoryUID  int    `json:"category_uid"`
+	CategoryName string `json:"category_name"`
+	ClassUID     int    `json:"class_uid"`
+	ClassName    string `json:"class_name"`
+	TypeUID      int    `json:"type_uid"`
+	TypeName     string `json:"type_name"`
+	Time         int64  `json:"time"`
+	SeverityID   int    `json:"severity_id"`
+	Severity     string `json:"severity"`
+	StatusID     int    `json:"status_id"`
+	Status       string `json:"status"`
+	Message      string `json:"message"`
+
+	Metadata    ocsfMetadata     `json:"metadata"`
+	FindingInfo ocsfFindingInfo  `json:"finding_info"`
+	Compliance  ocsfCompliance   `json:"compliance"`
+	Cloud       ocsfCloud        `json:"cloud"`
+	Resources   []ocsfResource   `json:"resources"`
+	Remediation *ocsfRemediation `json:"remediation,omitempty"`
+}
+
+type ocsfMetadata struct {
+	Version string      `json:"version"`
+	Product ocsfProduct `json:"product"`
+}
+
+type ocsfProduct struct {
+	Name       string `json:"name"`
+	VendorName string `json:"vendor_name"`
+}
+
+type ocsfFindingInfo struct {
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	Desc        string   `json:"desc,omitempty"`
+	Types       []string `json:"types"`
+	CreatedTime int64    `json:"created_time"`
+}
+
+type ocsfCompliance struct {
+	Control      string   `json:"control"`
+	Requirements []string `json:"requirements"`
+	Standards    []string `json:"standards,omitempty"`
+	StatusID     int      `json:"status_id"`
+	Status       string   `json:"status"`
+}
+
+type ocsfCloud struct {
+	Provider string       `json:"provider"`
+	Region   string       `json:"region,omitempty"`
+	Account  *ocsfAccount `json:"account,omitempty"`
+}
+
+type ocsfAccount struct {
+	UID    string `json:"uid"`
+	Type   string `json:"type"`
+	TypeID int    `json:"type_id"`
+}
+
+type ocsfResource struct {
+	UID            string            `json:"uid"`
+	Name           string            `json:"name,omitempty"`
+	Type           string            `json:"type"`
+	Region         string            `json:"region,omitempty"`
+	CloudPartition string            `json:"cloud_partition"`
+	Data           map[string]string `json:"data,omitempty"`
+}
+
+type ocsfRemediation struct {
+	Desc string `json:"desc"`
+}
+
+// ocsfSeverities maps normalized severities to OCSF severity IDs and names.
+var ocsfSeverities = map[string]struct {
+	ID   int
+	Name string
+}{
+	"info":     {1, "Informational"},
+	"low":      {2, "Low"},
+	"medium":   {3, "Medium"},
+	"high":     {4, "High"},
+	"critical": {5, "Critical"},
+}
+
+// Format writes the scan result as a JSON array of OCSF events.
+func (f *OCSFFormatter) Format(w io.Writer, result ScanResult) error {
+	scanned, err := time.Parse(time.RFC3339, result.Timestamp)
+	if err != nil {
+		scanned = time.Now().UTC()
+	}
+	ms := scanned.UnixMilli()
+
+	cloud := ocsfCloud{Provider: "AWS", Region: result.Region}
+	if result.AccountID != "" {
+		cloud.Account = &ocsfAccount{UID: result.AccountID, Type: "AWS Account", TypeID: 10}
+	}
+
+	events := []ocsfEvent{}
+	for _, sf := range securityFindings(result) {
+		severity := ocsfSeverities[sf.Severity]
+		event := ocsfEvent{
+			ActivityID:   1,
+			ActivityName: "Create",
+			CategoryUID:  2,
+			CategoryName: "Findings",
+			ClassUID:     2003,
+			ClassName:    "Compliance Finding",
+			TypeUID:      200301,
+			TypeName:     "Compliance Finding: Create",
+			Time:         ms,
+			SeverityID:   severity.ID,
+			Severity:     severity.Name,
+			StatusID:     1,
+			Status:       "New",
+			Message:      sf.Title,
+			Metadata: ocsfMetadata{
+				Version: "1.1.0",
+				Product: ocsfProduct{Name: "Cloudrift", VendorName: "Cloudrift"},
+			},
+			FindingInfo: ocsfFindingInfo{
+				UID:         sf.ID,
+				Title:       sf.Title,
+				Desc:        sf.Description,
+				Types:       []string{ocsfType(sf)},
+				CreatedTime: ms,
+			},
+			Compliance: ocsfComplianceFor(sf),
+			Cloud:      cloud,
+			Resources:  []ocsfResource{ocsfResourceFor(sf, result.Region)},
+		}
+		if sf.Remediation != "" {
+			event.Remediation = &ocsfRemediation{Desc: sf.Remediation}
+		}
+		events = append(events, event)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(events)
+}
+
+// ocsfType names the kind of finding.
+func ocsfType(sf securityFinding) string {
+	if sf.Drift {
+		return "Configuration Drift"
+	}
+	return "Policy Violation"
+}
+
+// ocsfComplianceFor maps a finding onto the OCSF compliance object. Drift
+// fails its own control; policies list their frameworks as both the
+// requirements and the standards.
+func ocsfComplianceFor(sf securityFinding) ocsfCompliance {
+	c := ocsfCompliance{Control: sf.Control, StatusID: 3, Status: "Fail"}
+	if sf.Warning {
+		c.StatusID, c.Status = 2, "Warning"
+	}
+	c.Standards = frameworkRequirements(sf.Frameworks)
+	c.Requirements = c.Standards
+	if len(c.Requirements) == 0 {
+		c.Requirements = []string{sf.Control}
+	}
+	return c
+}
+
+// ocsfResourceFor describes the resource of a finding. The ARN is used as
+// the UID when known, then the AWS identifier, then the Terraform address.
+func ocsfResourceFor(sf securityFinding, region string) ocsfResource {
+	uid := sf.ARN
+	if uid == "" {
+		uid = sf.ResourceID
+	}
+	if uid == "" {
+		uid = sf.ResourceAddress
+	}
+	r := ocsfResource{
+		UID:            uid,
+		Name:           sf.ResourceID,
+		Type:           sf.ResourceType,
+		Region:         region,
+		CloudPartition: awsPartition(region),
+	}
+	if sf.ResourceAddress != "" || sf.Location != nil {
+		r.Data = map[string]string{}
+		if sf.ResourceAddress != "" {
+			r.Data["terraform_address"] = sf.ResourceAddress
+		}
+		if sf.Location != nil {
+			r.Data["location"] = sf.Location.String()
+		}
+	}
+	return r
+}
+
+// Name returns the format name.
+func (f *OCSFFormatter) Name() string {
+	return "ocsf"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *OCSFFormatter) FileExtension() string {
+	return ".json"
+}
+
+func init() {
+	Register(FormatOCSF, NewOCSFFormatter())
+}
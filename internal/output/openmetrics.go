@@ -0,0 +1,183 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenMetricsFormatter outputs scan results as OpenMetrics text exposition.
+//
+// Every metric is a gauge without a timestamp, so the output can be dropped
+// into the node_exporter textfile collector directory (as a .prom file) or
+// pushed to a Pushgateway from CI. All series carry service, account and
+// region labels, so the files of several scans can be collected side by
+// side. Policy and compliance metrics are left out when policies were
+// skipped.
+type OpenMetricsFormatter struct{}
+
+// NewOpenMetricsFormatter creates a new OpenMetrics formatter.
+func NewOpenMetricsFormatter() *OpenMetricsFormatter {
+	return &OpenMetricsFormatter{}
+}
+
+// metricFamily is one metric with its samples.
+type metricFamily struct {
+	name    string
+	help    string
+	unit    string
+	samples []metricSample
+}
+
+// metricSample is one series of a metric family. Labels are name/value pairs.
+type metricSample struct {
+	labels []string
+	value  float64
+}
+
+// Format writes the scan result as OpenMetrics text.
+func (f *OpenMetricsFormatter) Format(w io.Writer, result ScanResult) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range f.families(result) {
+		fmt.Fprintf(bw, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", family.name)
+		if family.unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", family.name, family.unit)
+		}
+		for _, s := range family.samples {
+			fmt.Fprintf(bw, "%s{%s} %s\n", family.name, formatLabels(s.labels), strconv.FormatFloat(s.value, 'f', -1, 64))
+		}
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// families builds the metric families of a scan result.
+func (f *OpenMetricsFormatter) families(result ScanResult) []metricFamily {
+	base := []string{
+		"service", strings.ToLower(result.Service),
+		"account", result.AccountID,
+		"region", result.Region,
+	}
+	gauge := func(name, help string, value float64) metricFamily {
+		return metricFamily{name: name, help: help, samples: []metricSample{{labels: base, value: value}}}
+	}
+	with := func(labels ...string) []string {
+		return append(append([]string(nil), base...), labels...)
+	}
+
+	unknown := 0
+	for _, d := range result.Drifts {
+		if d.Unknown {
+			unknown++
+		}
+	}
+	incomplete := 0.0
+	if result.Incomplete {
+		incomplete = 1
+	}
+
+	families := []metricFamily{
+		gauge("cloudrift_resources", "Planned resources that were scanned.", float64(result.TotalResources)),
+		gauge("cloudrift_drifted_resources", "Resources whose live state differs from the plan.", float64(result.DriftCount)),
+		gauge("cloudrift_unknown_resources", "Resources whose live state could not be fetched.", float64(unknown)),
+		gauge("cloudrift_scan_incomplete", "1 if the scan was cancelled or timed out before it finished.", incomplete),
+	}
+	duration := gauge("cloudrift_scan_duration_seconds", "How long the scan took.", float64(result.ScanDuration)/1000)
+	duration.unit = "seconds"
+	families = append(families, duration)
+	if ts, err := time.Parse(time.RFC3339, result.Timestamp); err == nil {
+		last := gauge("cloudrift_scan_timestamp_seconds", "When the scan was performed, as a Unix timestamp.", float64(ts.Unix()))
+		last.unit = "seconds"
+		families = append(families, last)
+	}
+
+	pr := result.PolicyResult
+	if pr == nil {
+		return families
+	}
+
+	findings := func(name, help string, list []PolicyViolationOutput) metricFamily {
+		counts := make(map[[2]string]int)
+		seen := make(map[string]bool)
+		for _, v := range list {
+			counts[[2]string{v.PolicyID, v.Severity}]++
+			seen[v.PolicyID] = true
+		}
+		// Evaluated policies without findings report zero, so their series
+		// do not disappear from graphs when a finding is fixed.
+		for _, r := range pr.Rules {
+			if !seen[r.ID] {
+				counts[[2]string{r.ID, r.Severity}] = 0
+			}
+		}
+		keys := make([][2]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i][0] != keys[j][0] {
+				return keys[i][0] < keys[j][0]
+			}
+			return keys[i][1] < keys[j][1]
+		})
+		family := metricFamily{name: name, help: help}
+		for _, k := range keys {
+			family.samples = append(family.samples, metricSample{
+				labels: with("severity", k[1], "policy_id", k[0]),
+				value:  float64(counts[k]),
+			})
+		}
+		return family
+	}
+	families = append(families,
+		findings("cloudrift_policy_violations", "Policy violations by policy and severity.", pr.Violations),
+		findings("cloudrift_policy_warnings", "Policy warnings by policy and severity.", pr.Warnings),
+	)
+
+	if c := pr.ComplianceResult; c != nil {
+		families = append(families, gauge("cloudrift_compliance_overall_percentage", "Share of evaluated policies that passed (0-100).", c.OverallPercentage))
+
+		frameworks := metricFamily{name: "cloudrift_compliance_percentage", help: "Share of each framework's policies that passed (0-100)."}
+		for _, fw := range sortedKeys(c.Frameworks) {
+			frameworks.samples = append(frameworks.samples, metricSample{labels: with("framework", fw), value: c.Frameworks[fw].Percentage})
+		}
+		categories := metricFamily{name: "cloudrift_compliance_category_percentage", help: "Share of each category's policies that passed (0-100)."}
+		for _, cat := range sortedKeys(c.Categories) {
+			categories.samples = append(categories.samples, metricSample{labels: with("category", cat), value: c.Categories[cat].Percentage})
+		}
+		families = append(families, frameworks, categories)
+	}
+	return families
+}
+
+// formatLabels renders name/value label pairs as name="value",...
+func formatLabels(labels []string) string {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Name returns the format name.
+func (f *OpenMetricsFormatter) Name() string {
+	return "openmetrics"
+}
+
+// FileExtension returns the recommended file extension. The node_exporter
+// textfile collector only reads .prom files.
+func (f *OpenMetricsFormatter) FileExtension() string {
+	return ".prom"
+}
+
+func init() {
+	Register(FormatOpenMetrics, NewOpenMetricsFormatter())
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"io"
+
+	"github.com/inayathulla/cloudrift/internal/remediation"
+)
+
+// RemediationFormatter outputs the remediation patches as HCL.
+//
+// Each drifted resource becomes a partial resource block, preceded by a
+// comment with its address, containing only the arguments whose values must
+// change for the configuration to match AWS. Copy the arguments into the
+// .tf files, or use "cloudrift scan --apply-remediation" to rewrite them.
+type RemediationFormatter struct{}
+
+// NewRemediationFormatter creates a new remediation formatter.
+func NewRemediationFormatter() *RemediationFormatter {
+	return &RemediationFormatter{}
+}
+
+// Format writes the remediation patches in the scan result as HCL.
+// Nothing is written if there are no patches.
+func (f *RemediationFormatter) Format(w io.Writer, result ScanResult) error {
+	if len(result.Remediation) == 0 {
+		return nil
+	}
+	_, err := w.Write(remediation.HCL(result.Remediation))
+	return err
+}
+
+// Name returns the format name.
+func (f *RemediationFormatter) Name() string {
+	return "remediation"
+}
+
+// FileExtension returns the recommended file extension.
+func (f *RemediationFormatter) FileExtension() string {
+	return ".tf"
+}
+
+func init() {
+	Register(FormatRemediation, NewRemediationFormatter())
+}
@@ -0,0 +1,173 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+)
+
+// This file holds the view helpers shared by the document formats (HTML,
+// Markdown, JUnit). Unlike the console formatter they produce plain text
+// and must be deterministic, so map-backed fields are always sorted.
+
+// attributeRow is one attribute of a drifted resource, ready for display.
+type attributeRow struct {
+	Attribute string
+	Expected  string
+	Actual    string
+
+	// Extra is true for attributes present in AWS but not in the plan;
+	// Expected is empty for those.
+	Extra bool
+}
+
+// attributeRows returns the attribute diffs of a drift, followed by its
+// extra attributes, each group sorted by attribute name. List values are
+// joined with sep.
+func attributeRows(d detector.DriftInfo, sep string) []attributeRow {
+	rows := make([]attributeRow, 0, len(d.Diffs)+len(d.ExtraAttributes))
+	for _, attr := range sortedKeys(d.Diffs) {
+		v := d.Diffs[attr]
+		rows = append(rows, attributeRow{
+			Attribute: attr,
+			Expected:  plainValue(v[0], sep),
+			Actual:    plainValue(v[1], sep),
+		})
+	}
+	for _, attr := range sortedKeys(d.ExtraAttributes) {
+		rows = append(rows, attributeRow{
+			Attribute: attr,
+			Actual:    plainValue(d.ExtraAttributes[attr], sep),
+			Extra:     true,
+		})
+	}
+	return rows
+}
+
+// driftStatus summarises the state of a resource in one word.
+func driftStatus(d detector.DriftInfo) string {
+	switch {
+	case d.Unknown:
+		return "unknown"
+	case d.Missing:
+		return "missing"
+	case d.HasDrift():
+		return "drifted"
+	default:
+		return "in sync"
+	}
+}
+
+// reportedDrifts returns the drift entries worth reporting: resources with
+// drift and resources whose state is unknown.
+func reportedDrifts(drifts []detector.DriftInfo) []detector.DriftInfo {
+	var out []detector.DriftInfo
+	for _, d := range drifts {
+		if d.HasDrift() || d.Unknown {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// plainValue renders a drift value as text. List elements are joined with
+// sep and maps are rendered as sorted key=value pairs.
+func plainValue(v interface{}, sep string) string {
+	switch val := v.(type) {
+	case nil:
+		return "<not set>"
+	case string:
+		if val == "" {
+			return "<empty>"
+		}
+		return val
+	case []string:
+		if len(val) == 0 {
+			return "[]"
+		}
+		return strings.Join(val, sep)
+	case []interface{}:
+		if len(val) == 0 {
+			return "[]"
+		}
+		parts := make([]string, len(val))
+		for i, e := range val {
+			parts[i] = fmt.Sprintf("%v", e)
+		}
+		return strings.Join(parts, sep)
+	case map[string]interface{}:
+		parts := make([]string, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			parts = append(parts, fmt.Sprintf("%s=%v", k, val[k]))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case map[string]string:
+		parts := make([]string, 0, len(val))
+		for _, k := range sortedKeys(val) {
+			parts = append(parts, k+"="+val[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// severityOrder lists policy severities from most to least severe.
+var severityOrder = []string{"critical", "high", "medium", "low", "info"}
+
+// severityRank orders policy severities; unknown severities sort last.
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severityOrder)
+}
+
+// sortedFindings returns policy findings ordered by severity, policy ID and
+// resource address.
+func sortedFindings(findings []PolicyViolationOutput) []PolicyViolationOutput {
+	out := append([]PolicyViolationOutput(nil), findings...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.PolicyID != b.PolicyID {
+			return a.PolicyID < b.PolicyID
+		}
+		return a.ResourceAddress < b.ResourceAddress
+	})
+	return out
+}
+
+// severityCounts counts findings per severity.
+func severityCounts(findings []PolicyViolationOutput) map[string]int {
+	counts := make(map[string]int)
+	for _, v := range findings {
+		counts[v.Severity]++
+	}
+	return counts
+}
+
+// resourceLabel identifies a drifted resource: its address if known,
+// otherwise "name (type)".
+func resourceLabel(d detector.DriftInfo) string {
+	if d.ResourceAddress != "" {
+		return d.ResourceAddress
+	}
+	return fmt.Sprintf("%s (%s)", d.ResourceName, d.ResourceType)
+}
+
+// sortedKeys returns the keys of a string-keyed map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
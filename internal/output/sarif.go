@@ -1,9 +1,16 @@
 package output
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
 )
 
 // SARIFFormatter outputs scan results in SARIF 2.1.0 format.
@@ -28,8 +35,13 @@ type sarifDocument struct {
 }
 
 type sarifRun struct {
-	Tool    sarifTool     `json:"tool"`
-	Results []sarifResult `json:"results"`
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool `json:"executionSuccessful"`
 }
 
 type sarifTool struct {
@@ -45,13 +57,13 @@ type sarifDriver struct {
 }
 
 type sarifRule struct {
-	ID               string              `json:"id"`
-	Name             string              `json:"name"`
-	ShortDescription sarifMessage        `json:"shortDescription"`
-	FullDescription  sarifMessage        `json:"fullDescription,omitempty"`
-	Help             sarifMessage        `json:"help,omitempty"`
-	DefaultConfig    sarifDefaultConfig  `json:"defaultConfiguration"`
-	Properties       map[string][]string `json:"properties,omitempty"`
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	FullDescription  sarifMessage           `json:"fullDescription,omitempty"`
+	Help             sarifMessage           `json:"help,omitempty"`
+	DefaultConfig    sarifDefaultConfig     `json:"defaultConfiguration"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
 }
 
 type sarifDefaultConfig struct {
@@ -63,13 +75,14 @@ type sarifMessage struct {
 }
 
 type sarifResult struct {
-	RuleID     string                 `json:"ruleId"`
-	RuleIndex  int                    `json:"ruleIndex"`
-	Level      string                 `json:"level"`
-	Message    sarifMessage           `json:"message"`
-	Locations  []sarifLocation        `json:"locations,omitempty"`
-	Fixes      []sarifFix             `json:"fixes,omitempty"`
-	Properties map[string]interface{} `json:"properties,omitempty"`
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Fixes               []sarifFix             `json:"fixes,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
 }
 
 type sarifLocation struct {
@@ -89,6 +102,7 @@ type sarifArtifactLocation struct {
 type sarifRegion struct {
 	StartLine   int `json:"startLine,omitempty"`
 	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
 }
 
 type sarifLogicalLocation struct {
@@ -113,6 +127,15 @@ func (f *SARIFFormatter) Format(w io.Writer, result ScanResult) error {
 func (f *SARIFFormatter) buildDocument(result ScanResult) sarifDocument {
 	rules := f.buildRules()
 	results := f.buildResults(result)
+	policyRules, policyResults := f.buildPolicyFindings(result, len(rules))
+	rules = append(rules, policyRules...)
+	results = append(results, policyResults...)
+
+	// Only an incomplete scan reports its invocation, marking it unsuccessful
+	var invocations []sarifInvocation
+	if result.Incomplete {
+		invocations = []sarifInvocation{{ExecutionSuccessful: false}}
+	}
 
 	return sarifDocument{
 		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
@@ -128,7 +151,8 @@ func (f *SARIFFormatter) buildDocument(result ScanResult) sarifDocument {
 						Rules:           rules,
 					},
 				},
-				Results: results,
+				Invocations: invocations,
+				Results:     results,
 			},
 		},
 	}
@@ -149,8 +173,8 @@ func (f *SARIFFormatter) buildRules() []sarifRule {
 				Text: "Run 'terraform apply' to create the resource, or remove it from your Terraform configuration if it's no longer needed.",
 			},
 			DefaultConfig: sarifDefaultConfig{Level: "error"},
-			Properties: map[string][]string{
-				"tags": {"drift", "infrastructure", "terraform"},
+			Properties: map[string]interface{}{
+				"tags": []string{"drift", "infrastructure", "terraform"},
 			},
 		},
 		{
@@ -166,8 +190,8 @@ func (f *SARIFFormatter) buildRules() []sarifRule {
 				Text: "Review the drift details and either update your Terraform configuration to match AWS, or run 'terraform apply' to enforce your planned state.",
 			},
 			DefaultConfig: sarifDefaultConfig{Level: "warning"},
-			Properties: map[string][]string{
-				"tags": {"drift", "infrastructure", "terraform"},
+			Properties: map[string]interface{}{
+				"tags": []string{"drift", "infrastructure", "terraform"},
 			},
 		},
 		{
@@ -183,8 +207,42 @@ func (f *SARIFFormatter) buildRules() []sarifRule {
 				Text: "Consider importing the extra attributes into your Terraform configuration to prevent them from being overwritten.",
 			},
 			DefaultConfig: sarifDefaultConfig{Level: "note"},
-			Properties: map[string][]string{
-				"tags": {"drift", "infrastructure", "terraform"},
+			Properties: map[string]interface{}{
+				"tags": []string{"drift", "infrastructure", "terraform"},
+			},
+		},
+		{
+			ID:   "FETCH001",
+			Name: "resource-state-unknown",
+			ShortDescription: sarifMessage{
+				Text: "Live state of a resource could not be fetched from AWS",
+			},
+			FullDescription: sarifMessage{
+				Text: "An AWS API call needed to read the resource failed, so drift could not be determined. The resource may or may not match your Terraform plan.",
+			},
+			Help: sarifMessage{
+				Text: "Check that the scanning credentials have read access to the resource (for example s3:GetBucketAcl or iam:GetPolicyVersion) and re-run the scan.",
+			},
+			DefaultConfig: sarifDefaultConfig{Level: "warning"},
+			Properties: map[string]interface{}{
+				"tags": []string{"drift", "infrastructure", "fetch-error"},
+			},
+		},
+		{
+			ID:   "UNMANAGED001",
+			Name: "resource-unmanaged",
+			ShortDescription: sarifMessage{
+				Text: "Resource exists in AWS but is not managed by Terraform",
+			},
+			FullDescription: sarifMessage{
+				Text: "A live AWS resource is not referenced by any resource in the Terraform plan. It may have been created manually or by another tool, outside of code review.",
+			},
+			Help: sarifMessage{
+				Text: "Import the resource into Terraform, delete it if it is no longer needed, or add an exclusion rule if it is managed elsewhere on purpose.",
+			},
+			DefaultConfig: sarifDefaultConfig{Level: "warning"},
+			Properties: map[string]interface{}{
+				"tags": []string{"drift", "infrastructure", "unmanaged"},
 			},
 		},
 	}
@@ -204,11 +262,7 @@ func (f *SARIFFormatter) buildResults(scanResult ScanResult) []sarifResult {
 				},
 				Locations: []sarifLocation{
 					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{
-								URI: "terraform.tfstate",
-							},
-						},
+						PhysicalLocation: physicalLocation(drift.Location),
 						LogicalLocations: []sarifLogicalLocation{
 							{
 								Name:               drift.ResourceName,
@@ -237,11 +291,7 @@ func (f *SARIFFormatter) buildResults(scanResult ScanResult) []sarifResult {
 				},
 				Locations: []sarifLocation{
 					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{
-								URI: "terraform.tfstate",
-							},
-						},
+						PhysicalLocation: physicalLocation(drift.Location),
 						LogicalLocations: []sarifLogicalLocation{
 							{
 								Name:               drift.ResourceName,
@@ -273,11 +323,7 @@ func (f *SARIFFormatter) buildResults(scanResult ScanResult) []sarifResult {
 				},
 				Locations: []sarifLocation{
 					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{
-								URI: "terraform.tfstate",
-							},
-						},
+						PhysicalLocation: physicalLocation(drift.Location),
 						LogicalLocations: []sarifLogicalLocation{
 							{
 								Name:               drift.ResourceName,
@@ -298,9 +344,274 @@ func (f *SARIFFormatter) buildResults(scanResult ScanResult) []sarifResult {
 		}
 	}
 
+	// Fetch errors are located through the Unknown drift entry of the
+	// resource. EC2 errors are recorded by instance ID, the others by name.
+	unknownLocations := make(map[string]*tfconfig.Location)
+	for _, drift := range scanResult.Drifts {
+		if drift.Unknown {
+			unknownLocations[models.ResourceKey(drift.ResourceType, drift.ResourceName)] = drift.Location
+			unknownLocations[models.ResourceKey(drift.ResourceType, drift.ResourceID)] = drift.Location
+		}
+	}
+
+	for _, e := range scanResult.Errors {
+		results = append(results, sarifResult{
+			RuleID:    "FETCH001",
+			RuleIndex: 3,
+			Level:     "warning",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("Live state of %s (%s) is unknown: %s failed: %s",
+					e.Resource, e.ResourceType, e.Operation, e.Message),
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: physicalLocation(unknownLocations[models.ResourceKey(e.ResourceType, e.Resource)]),
+					LogicalLocations: []sarifLogicalLocation{
+						{
+							Name:               e.Resource,
+							FullyQualifiedName: fmt.Sprintf("%s.%s", e.ResourceType, e.Resource),
+							Kind:               "resource",
+						},
+					},
+				},
+			},
+			Properties: map[string]interface{}{
+				"operation":    e.Operation,
+				"errorCode":    e.ErrorCode,
+				"resourceType": e.ResourceType,
+				"resourceName": e.Resource,
+				"service":      scanResult.Service,
+			},
+		})
+	}
+
+	for _, u := range scanResult.Unmanaged {
+		results = append(results, sarifResult{
+			RuleID:    "UNMANAGED001",
+			RuleIndex: 4,
+			Level:     "warning",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("Resource %s (%s) exists in AWS but is not managed by Terraform", u.ResourceName, u.ResourceType),
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: physicalLocation(nil),
+					LogicalLocations: []sarifLogicalLocation{
+						{
+							Name:               u.ResourceID,
+							FullyQualifiedName: fmt.Sprintf("%s.%s", u.ResourceType, u.ResourceID),
+							Kind:               "resource",
+						},
+					},
+				},
+			},
+			Properties: map[string]interface{}{
+				"resourceType": u.ResourceType,
+				"resourceId":   u.ResourceID,
+				"resourceName": u.ResourceName,
+				"service":      scanResult.Service,
+			},
+		})
+	}
+
 	return results
 }
 
+// policyFingerprintKey names the partial fingerprint of policy results.
+// Bump the version if the fingerprint inputs change.
+const policyFingerprintKey = "cloudriftPolicyFinding/v1"
+
+// buildPolicyFindings emits one rule per evaluated policy and one result per
+// policy violation or warning.
+//
+// Rules come from PolicyOutput.Rules, so policies without findings are
+// listed too; a policy that has findings but no rule entry gets one built
+// from its first finding. Each result carries a partial fingerprint derived
+// from the policy ID, resource address and message, so code scanning
+// platforms can match alerts across runs.
+//
+// Parameters:
+//   - scanResult: the scan result; nothing is emitted if PolicyResult is nil
+//   - firstIndex: the rule index of the first policy rule
+//
+// Returns:
+//   - []sarifRule: policy rules, sorted by ID
+//   - []sarifResult: violations followed by warnings
+func (f *SARIFFormatter) buildPolicyFindings(scanResult ScanResult, firstIndex int) ([]sarifRule, []sarifResult) {
+	pr := scanResult.PolicyResult
+	if pr == nil {
+		return nil, nil
+	}
+
+	defs := make(map[string]PolicyRuleOutput, len(pr.Rules))
+	for _, r := range pr.Rules {
+		defs[r.ID] = r
+	}
+	for _, findings := range [][]PolicyViolationOutput{pr.Violations, pr.Warnings} {
+		for _, v := range findings {
+			if _, ok := defs[v.PolicyID]; !ok {
+				defs[v.PolicyID] = PolicyRuleOutput{
+					ID:          v.PolicyID,
+					Name:        v.PolicyName,
+					Severity:    v.Severity,
+					Remediation: v.Remediation,
+					Category:    v.Category,
+					Frameworks:  v.Frameworks,
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(defs))
+	for id := range defs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rules := make([]sarifRule, len(ids))
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		rules[i] = policyRule(defs[id])
+		index[id] = firstIndex + i
+	}
+
+	var results []sarifResult
+	add := func(v PolicyViolationOutput, kind string) {
+		level := policySeverityToLevel(v.Severity)
+		if kind == "warning" && level == "error" {
+			// Warnings never block, whatever their severity
+			level = "warning"
+		}
+		props := map[string]interface{}{
+			"kind":            kind,
+			"severity":        v.Severity,
+			"resourceType":    v.ResourceType,
+			"resourceAddress": v.ResourceAddress,
+			"service":         scanResult.Service,
+		}
+		if v.Category != "" {
+			props["category"] = v.Category
+		}
+		if len(v.Frameworks) > 0 {
+			props["frameworks"] = v.Frameworks
+		}
+		results = append(results, sarifResult{
+			RuleID:    v.PolicyID,
+			RuleIndex: index[v.PolicyID],
+			Level:     level,
+			Message:   sarifMessage{Text: v.Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: physicalLocation(v.Location),
+					LogicalLocations: []sarifLogicalLocation{
+						{
+							Name:               v.ResourceAddress,
+							FullyQualifiedName: v.ResourceAddress,
+							Kind:               "resource",
+						},
+					},
+				},
+			},
+			PartialFingerprints: map[string]string{
+				policyFingerprintKey: fingerprint(v.PolicyID, v.ResourceAddress, v.Message),
+			},
+			Properties: props,
+		})
+	}
+	for _, v := range pr.Violations {
+		add(v, "violation")
+	}
+	for _, w := range pr.Warnings {
+		add(w, "warning")
+	}
+	return rules, results
+}
+
+// policyRule builds the SARIF rule for a policy.
+func policyRule(p PolicyRuleOutput) sarifRule {
+	name := p.Name
+	if name == "" {
+		name = p.ID
+	}
+	help := p.Remediation
+	if help == "" {
+		help = name
+	}
+	tags := []string{"policy"}
+	if p.Category != "" {
+		tags = append(tags, p.Category)
+	}
+	props := map[string]interface{}{
+		"tags": append(tags, p.Frameworks...),
+	}
+	if p.Category != "" {
+		props["category"] = p.Category
+	}
+	if len(p.Frameworks) > 0 {
+		props["frameworks"] = p.Frameworks
+	}
+	if score, ok := securitySeverity[p.Severity]; ok && p.Category == "security" {
+		props["security-severity"] = score
+	}
+	return sarifRule{
+		ID:               p.ID,
+		Name:             name,
+		ShortDescription: sarifMessage{Text: name},
+		Help:             sarifMessage{Text: help},
+		DefaultConfig:    sarifDefaultConfig{Level: policySeverityToLevel(p.Severity)},
+		Properties:       props,
+	}
+}
+
+// securitySeverity maps policy severities to the numeric scores GitHub code
+// scanning uses to rank security alerts.
+var securitySeverity = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.1",
+}
+
+// policySeverityToLevel maps a policy severity to a SARIF level.
+func policySeverityToLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium", "low":
+		return "warning"
+	case "info":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// fingerprint returns a stable hash of its parts.
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// physicalLocation points a result at the resource block in the Terraform
+// configuration. Without a known location, results fall back to the state
+// file, with the resource identified only by its logical location.
+func physicalLocation(loc *tfconfig.Location) sarifPhysicalLocation {
+	if loc == nil {
+		return sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "terraform.tfstate"},
+		}
+	}
+	return sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: loc.File},
+		Region: &sarifRegion{
+			StartLine:   loc.StartLine,
+			StartColumn: loc.StartColumn,
+			EndLine:     loc.EndLine,
+		},
+	}
+}
+
 func (f *SARIFFormatter) severityToLevel(severity string) string {
 	switch severity {
 	case "critical":
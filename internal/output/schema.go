@@ -0,0 +1,65 @@
+package output
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:generate go run gen_schema.go
+
+// SchemaVersion is the version of the JSON output schema, reported in the
+// schema_version field.
+//
+// The minor version is bumped when fields are added and the major version
+// when fields are removed, renamed or change type.
+const SchemaVersion = "1.0.0"
+
+//go:embed schema/scan-result.schema.json
+var scanResultSchema []byte
+
+var (
+	compileSchema  sync.Once
+	compiledSchema *gojsonschema.Schema
+	compileErr     error
+)
+
+// Schema returns the JSON Schema (draft-07) of the JSON output.
+//
+// The schema is generated from the ScanResult type by go generate and
+// embedded in the binary.
+func Schema() []byte {
+	return append([]byte(nil), scanResultSchema...)
+}
+
+// ValidateJSON checks a JSON document against the embedded schema.
+//
+// Parameters:
+//   - data: the JSON output of a scan
+//
+// Returns:
+//   - error: lists every schema violation, nil if the document is valid
+func ValidateJSON(data []byte) error {
+	compileSchema.Do(func() {
+		compiledSchema, compileErr = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(scanResultSchema))
+	})
+	if compileErr != nil {
+		return fmt.Errorf("failed to load schema: %w", compileErr)
+	}
+
+	res, err := compiledSchema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if res.Valid() {
+		return nil
+	}
+	msgs := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		msgs = append(msgs, e.String())
+	}
+	return fmt.Errorf("does not match schema %s:\n  - %s", SchemaVersion, strings.Join(msgs, "\n  - "))
+}
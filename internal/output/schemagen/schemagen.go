@@ -0,0 +1,349 @@
+// Package schemagen generates the JSON Schema of the JSON output from the Go
+// types in the output package.
+//
+// The schema follows the encoding/json rules the JSON formatter relies on:
+// fields without omitempty are required, nil slices, maps and pointers of
+// required fields may be null, and fields tagged "-" are left out. Field and
+// type descriptions are taken from the doc comments in the Go sources.
+//
+// The generated schema is embedded in the output package; regenerate it with
+//
+//	go generate ./internal/output
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+)
+
+// modulePath is the import path prefix of the packages whose sources are
+// read for doc comments.
+const modulePath = "github.com/inayathulla/cloudrift"
+
+// schemaID identifies the published schema.
+const schemaID = "https://raw.githubusercontent.com/inayathulla/cloudrift/main/internal/output/schema/scan-result.schema.json"
+
+// schema is the subset of JSON Schema (draft-07) the generator emits.
+type schema struct {
+	Schema               string      `json:"$schema,omitempty"`
+	ID                   string      `json:"$id,omitempty"`
+	Ref                  string      `json:"$ref,omitempty"`
+	Title                string      `json:"title,omitempty"`
+	Description          string      `json:"description,omitempty"`
+	Type                 interface{} `json:"type,omitempty"`
+	Const                interface{} `json:"const,omitempty"`
+	Properties           *schemaMap  `json:"properties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Items                *schema     `json:"items,omitempty"`
+	MinItems             *int        `json:"minItems,omitempty"`
+	MaxItems             *int        `json:"maxItems,omitempty"`
+	AnyOf                []*schema   `json:"anyOf,omitempty"`
+	Definitions          *schemaMap  `json:"definitions,omitempty"`
+}
+
+// schemaMap is a JSON object of schemas that keeps insertion order, so
+// properties are listed in field order.
+type schemaMap struct {
+	names   []string
+	schemas map[string]*schema
+}
+
+func (m *schemaMap) set(name string, s *schema) {
+	if m.schemas == nil {
+		m.schemas = make(map[string]*schema)
+	}
+	if _, ok := m.schemas[name]; !ok {
+		m.names = append(m.names, name)
+	}
+	m.schemas[name] = s
+}
+
+// MarshalJSON writes the schemas in insertion order.
+func (m *schemaMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range m.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshal(m.schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshal encodes v without HTML escaping, so descriptions stay readable.
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Generate builds the JSON Schema of output.ScanResult.
+//
+// Parameters:
+//   - root: the module root directory, used to read doc comments
+//
+// Returns:
+//   - []byte: the schema as indented JSON, ending with a newline
+//   - error: if a source file cannot be parsed or a type is not supported
+func Generate(root string) ([]byte, error) {
+	g := &generator{root: root, docs: make(map[string]map[string]string)}
+
+	t := reflect.TypeOf(output.ScanResult{})
+	top, err := g.object(t)
+	if err != nil {
+		return nil, err
+	}
+	top.Schema = "http://json-schema.org/draft-07/schema#"
+	top.ID = schemaID
+	top.Title = fmt.Sprintf("Cloudrift scan result (schema %s)", output.SchemaVersion)
+	top.Properties.schemas["schema_version"].Const = output.SchemaVersion
+
+	if len(g.defs.names) > 0 {
+		sort.Strings(g.defs.names)
+		top.Definitions = &g.defs
+	}
+
+	data, err := marshal(top)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// generator walks the Go types and collects named structs as definitions.
+type generator struct {
+	root string
+
+	// docs maps an import path to the doc comments of its types and
+	// struct fields, keyed by "Type" and "Type.Field".
+	docs map[string]map[string]string
+
+	defs schemaMap
+}
+
+// object returns the schema of struct type t with its properties inline.
+func (g *generator) object(t reflect.Type) (*schema, error) {
+	s := &schema{
+		Description:          g.doc(t, ""),
+		Type:                 "object",
+		Properties:           &schemaMap{},
+		AdditionalProperties: false,
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			return nil, fmt.Errorf("%s.%s: embedded fields are not supported", t.Name(), f.Name)
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+
+		fs, err := g.field(f.Type, !omitempty)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+		if doc := g.doc(t, f.Name); doc != "" {
+			fs.Description = doc
+		}
+		s.Properties.set(name, fs)
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+// field returns the schema of a struct field of type t. Nil slices, maps and
+// pointers are encoded as null unless the field is omitted when empty.
+func (g *generator) field(t reflect.Type, nullable bool) (*schema, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		s, err := g.field(t.Elem(), false)
+		if err != nil || !nullable {
+			return s, err
+		}
+		return &schema{AnyOf: []*schema{s, {Type: "null"}}}, nil
+	case reflect.Slice, reflect.Map:
+		s, err := g.value(t)
+		if err != nil || !nullable {
+			return s, err
+		}
+		s.Type = []string{s.Type.(string), "null"}
+		return s, nil
+	}
+	return g.value(t)
+}
+
+// value returns the schema of a non-nil value of type t.
+func (g *generator) value(t reflect.Type) (*schema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}, nil
+	case reflect.Interface:
+		return &schema{}, nil
+	case reflect.Ptr:
+		return g.value(t.Elem())
+	case reflect.Slice, reflect.Array:
+		items, err := g.field(t.Elem(), true)
+		if err != nil {
+			return nil, err
+		}
+		s := &schema{Type: "array", Items: items}
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key %s is not a string", t.Key())
+		}
+		values, err := g.field(t.Elem(), true)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.ref(t)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// ref adds struct type t to the definitions and returns a reference to it.
+func (g *generator) ref(t reflect.Type) (*schema, error) {
+	name := t.Name()
+	if name == "" {
+		return nil, fmt.Errorf("anonymous structs are not supported")
+	}
+	if _, ok := g.defs.schemas[name]; !ok {
+		// Reserve the name first so recursive types terminate.
+		g.defs.set(name, nil)
+		def, err := g.object(t)
+		if err != nil {
+			return nil, err
+		}
+		g.defs.set(name, def)
+	}
+	return &schema{Ref: "#/definitions/" + name}, nil
+}
+
+// doc returns the doc comment of type t, or of its field when field is set,
+// as a single line.
+func (g *generator) doc(t reflect.Type, field string) string {
+	docs, ok := g.docs[t.PkgPath()]
+	if !ok {
+		docs = g.loadDocs(t.PkgPath())
+		g.docs[t.PkgPath()] = docs
+	}
+	key := t.Name()
+	if field != "" {
+		key += "." + field
+	}
+	return docs[key]
+}
+
+// loadDocs reads the doc comments of the types declared in a package of
+// this module. Packages outside the module have no docs.
+func (g *generator) loadDocs(pkgPath string) map[string]string {
+	docs := make(map[string]string)
+	rel, ok := strings.CutPrefix(pkgPath, modulePath)
+	if !ok {
+		return docs
+	}
+	files, _ := filepath.Glob(filepath.Join(g.root, filepath.FromSlash(rel), "*.go"))
+
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				typeDoc := ts.Doc
+				if typeDoc == nil && len(gd.Specs) == 1 {
+					typeDoc = gd.Doc
+				}
+				docs[ts.Name.Name] = commentText(typeDoc)
+
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, f := range st.Fields.List {
+					text := commentText(f.Doc)
+					if text == "" {
+						text = commentText(f.Comment)
+					}
+					for _, name := range f.Names {
+						docs[ts.Name.Name+"."+name.Name] = text
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// commentText joins the lines of a comment into one line.
+func commentText(c *ast.CommentGroup) string {
+	if c == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(c.Text()), " ")
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// OutputSpec is one output written from a scan, given on the command line
+// as "<format>:<path>".
+type OutputSpec struct {
+	// Format is the output format.
+	Format FormatType
+
+	// Path is the file the output is written to.
+	Path string
+}
+
+// ParseOutputSpec parses an output given on the command line as
+// "<format>:<path>", e.g. "sarif:drift.sarif". The format is matched
+// case-insensitively and must be registered or be "template".
+func ParseOutputSpec(s string) (OutputSpec, error) {
+	format, path, ok := strings.Cut(s, ":")
+	if !ok || format == "" || path == "" {
+		return OutputSpec{}, fmt.Errorf("invalid output %q: expected <format>:<path>", s)
+	}
+	spec := OutputSpec{Format: FormatType(strings.ToLower(format)), Path: path}
+	if _, ok := Get(spec.Format); !ok && spec.Format != FormatTemplate {
+		return OutputSpec{}, fmt.Errorf("invalid output %q: unsupported format %q", s, format)
+	}
+	return spec, nil
+}
+
+// ParseOutputSpecs parses a list of outputs and rejects two outputs
+// written to the same file.
+func ParseOutputSpecs(values []string) ([]OutputSpec, error) {
+	specs := make([]OutputSpec, 0, len(values))
+	seen := make(map[string]bool)
+	for _, v := range values {
+		spec, err := ParseOutputSpec(v)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Clean(spec.Path)
+		if seen[path] {
+			return nil, fmt.Errorf("invalid output %q: %s is already an output", v, spec.Path)
+		}
+		seen[path] = true
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
@@ -0,0 +1,303 @@
+package output
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+)
+
+// builtinTemplates holds the templates that ship with Cloudrift.
+//
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// TemplateFormatter renders scan results through a user-supplied
+// text/template, for shapes the built-in formats do not cover (CSV for
+// spreadsheets, chat webhook payloads, and so on).
+//
+// The template receives the ScanResult as its data, so fields are reached
+// as {{.Service}}, {{range .Drifts}} or {{with .PolicyResult}}. The helper
+// functions are listed in TemplateFuncs.
+type TemplateFormatter struct {
+	tmpl *template.Template
+	ext  string
+}
+
+// BuiltinTemplates returns the names of the embedded templates, sorted.
+func BuiltinTemplates() []string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".tmpl"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTemplate loads a built-in template by name ("csv", "table") or a
+// template file by path. Built-in names take precedence; use "./csv" for a
+// file with the same name.
+//
+// Parameters:
+//   - ref: a built-in template name or a file path
+//
+// Returns:
+//   - *TemplateFormatter: the formatter for the template
+//   - error: if the file cannot be read or the template does not parse
+func LoadTemplate(ref string) (*TemplateFormatter, error) {
+	if text, err := builtinTemplates.ReadFile(path.Join("templates", ref+".tmpl")); err == nil {
+		ext := ".txt"
+		if ref == "csv" {
+			ext = ".csv"
+		}
+		return NewTemplateFormatter(ref, string(text), ext)
+	}
+	text, err := os.ReadFile(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	ext := filepath.Ext(strings.TrimSuffix(ref, ".tmpl"))
+	if ext == "" {
+		ext = ".txt"
+	}
+	return NewTemplateFormatter(filepath.Base(ref), string(text), ext)
+}
+
+// NewTemplateFormatter parses a template.
+//
+// Parameters:
+//   - name: the template name, used in error messages
+//   - text: the template source
+//   - ext: the recommended file extension for the output
+//
+// Returns:
+//   - *TemplateFormatter: the formatter for the template
+//   - error: if the template does not parse
+func NewTemplateFormatter(name, text, ext string) (*TemplateFormatter, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl, ext: ext}, nil
+}
+
+// Format renders the scan result through the template.
+func (f *TemplateFormatter) Format(w io.Writer, result ScanResult) error {
+	return f.tmpl.Execute(w, result)
+}
+
+// Name returns the format name.
+func (f *TemplateFormatter) Name() string {
+	return "template"
+}
+
+// FileExtension returns the recommended file extension: the extension of
+// the template file name without ".tmpl" (report.csv.tmpl and report.csv
+// give ".csv"), or ".txt".
+func (f *TemplateFormatter) FileExtension() string {
+	return f.ext
+}
+
+// TemplateFuncs returns the helper functions available to templates:
+//   - severity MIN LIST: items of LIST (findings or drifts) at severity MIN
+//     or above
+//   - sortBy FIELD LIST: LIST sorted by a struct field or map key;
+//     "Severity" sorts most severe first
+//   - join SEP LIST: LIST joined with SEP
+//   - json VALUE: VALUE encoded as compact JSON
+//   - pad N S / padLeft N S: S padded with spaces to N characters
+//   - csv S: S quoted as a CSV field when needed
+//   - upper S / lower S: S in upper or lower case
+//   - drifted DRIFTS: drifts with changes or unknown state
+//   - status DRIFT: "drifted", "missing", "unknown" or "in sync"
+//   - label DRIFT: the resource address, or "name (type)"
+//   - diffs DRIFT: attribute rows with Attribute, Expected, Actual and Extra
+//   - value V: a drift value as plain text
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"severity": filterSeverity,
+		"sortBy":   sortBy,
+		"join":     joinList,
+		"json":     jsonValue,
+		"pad":      func(n int, s string) string { return padString(n, s, false) },
+		"padLeft":  func(n int, s string) string { return padString(n, s, true) },
+		"csv":      csvField,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"drifted":  reportedDrifts,
+		"status":   driftStatus,
+		"label":    resourceLabel,
+		"diffs":    func(d detector.DriftInfo) []attributeRow { return attributeRows(d, ", ") },
+		"value":    func(v interface{}) string { return plainValue(v, ", ") },
+	}
+}
+
+// filterSeverity keeps the elements of a slice whose Severity field is at
+// least min. Drift severities are normalized first ("warning" is medium).
+func filterSeverity(min string, list interface{}) (interface{}, error) {
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	limit := severityRank(normalizeSeverity(min))
+	out := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		s, ok := fieldValue(v.Index(i), "Severity")
+		if !ok {
+			return nil, fmt.Errorf("severity: %s has no Severity field", v.Index(i).Type())
+		}
+		if severityRank(normalizeSeverity(fmt.Sprint(s.Interface()))) <= limit {
+			out = reflect.Append(out, v.Index(i))
+		}
+	}
+	return out.Interface(), nil
+}
+
+// sortBy returns a sorted copy of a slice. Elements are compared by a
+// struct field or map key; an empty field compares the elements themselves.
+func sortBy(field string, list interface{}) (interface{}, error) {
+	v, err := sliceValue(list)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]reflect.Value, v.Len())
+	for i := range keys {
+		k, ok := fieldValue(v.Index(i), field)
+		if !ok {
+			return nil, fmt.Errorf("sortBy: %s has no field %q", v.Index(i).Type(), field)
+		}
+		keys[i] = k
+	}
+
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return lessValue(keys[idx[a]], keys[idx[b]], field == "Severity")
+	})
+
+	sorted := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for _, i := range idx {
+		sorted = reflect.Append(sorted, v.Index(i))
+	}
+	return sorted.Interface(), nil
+}
+
+// lessValue orders two values of the same kind. Severities are ordered from
+// most to least severe.
+func lessValue(a, b reflect.Value, severity bool) bool {
+	if severity {
+		return severityRank(normalizeSeverity(fmt.Sprint(a.Interface()))) < severityRank(normalizeSeverity(fmt.Sprint(b.Interface())))
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
+// sliceValue returns the reflect value of a slice or array, or an error.
+// A nil list is treated as an empty one.
+func sliceValue(list interface{}) (reflect.Value, error) {
+	if list == nil {
+		return reflect.ValueOf([]interface{}{}), nil
+	}
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("expected a list, got %T", list)
+	}
+	return v, nil
+}
+
+// fieldValue returns a field of a struct, a key of a map, or the value
+// itself for an empty name, following pointers and interfaces.
+func fieldValue(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if name == "" {
+		return v, true
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		f := v.FieldByName(name)
+		return f, f.IsValid()
+	case reflect.Map:
+		f := v.MapIndex(reflect.ValueOf(name))
+		return f, f.IsValid()
+	}
+	return reflect.Value{}, false
+}
+
+// joinList joins the elements of a list with sep.
+func joinList(sep string, list interface{}) (string, error) {
+	if s, ok := list.([]string); ok {
+		return strings.Join(s, sep), nil
+	}
+	v, err := sliceValue(list)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// jsonValue encodes v as compact JSON without HTML escaping.
+func jsonValue(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// padString pads s with spaces to n characters, on the right or the left.
+func padString(n int, s string, left bool) string {
+	fill := n - utf8.RuneCountInString(s)
+	if fill <= 0 {
+		return s
+	}
+	if left {
+		return strings.Repeat(" ", fill) + s
+	}
+	return s + strings.Repeat(" ", fill)
+}
+
+// csvField quotes s as a CSV field if it contains a separator, quote or
+// line break.
+func csvField(s string) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{s})
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"sort"
+
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
@@ -14,7 +18,9 @@ import (
 //   - Network configuration (subnet, VPC, security groups)
 //   - Tags
 //   - EBS optimization and monitoring settings
-//   - Root block device configuration
+//   - Root and additional EBS block device configuration
+//   - Metadata options (IMDS), network interfaces and user data hash
+//   - Termination and stop protection
 //
 // Resources being deleted (with nil "after" state) are skipped.
 //
@@ -78,6 +84,23 @@ func ParseEC2Instances(plan *TerraformPlan) []models.EC2Instance {
 		if v, ok := after["source_dest_check"].(bool); ok {
 			instance.SourceDestCheck = v
 		}
+		if v, ok := after["disable_api_termination"].(bool); ok {
+			instance.DisableAPITermination = v
+		}
+		if v, ok := after["disable_api_stop"].(bool); ok {
+			instance.DisableAPIStop = v
+		}
+
+		// User data - the provider stores user_data as a SHA-1 hash, while
+		// user_data_base64 is kept encoded
+		if v, ok := after["user_data"].(string); ok && v != "" {
+			instance.UserDataHash = userDataHash(v)
+		}
+		if v, ok := after["user_data_base64"].(string); ok && v != "" {
+			if raw, err := base64.StdEncoding.DecodeString(v); err == nil {
+				instance.UserDataHash = models.HashUserData(raw)
+			}
+		}
 
 		// Security groups - can be vpc_security_group_ids or security_groups
 		if sgs, ok := after["vpc_security_group_ids"].([]interface{}); ok {
@@ -122,6 +145,37 @@ func ParseEC2Instances(plan *TerraformPlan) []models.EC2Instance {
 			}
 		}
 
+		// Additional EBS block devices
+		if ebs, ok := after["ebs_block_device"].([]interface{}); ok {
+			for _, bd := range ebs {
+				if bdMap, ok := bd.(map[string]interface{}); ok {
+					instance.EBSBlockDevices = append(instance.EBSBlockDevices, parseBlockDevice(bdMap))
+				}
+			}
+			sort.Slice(instance.EBSBlockDevices, func(i, j int) bool {
+				return instance.EBSBlockDevices[i].DeviceName < instance.EBSBlockDevices[j].DeviceName
+			})
+		}
+
+		// Metadata options
+		if mo, ok := after["metadata_options"].([]interface{}); ok && len(mo) > 0 {
+			if moMap, ok := mo[0].(map[string]interface{}); ok {
+				instance.MetadataOptions = parseMetadataOptions(moMap)
+			}
+		}
+
+		// Network interfaces
+		if enis, ok := after["network_interface"].([]interface{}); ok {
+			for _, eni := range enis {
+				if eniMap, ok := eni.(map[string]interface{}); ok {
+					instance.NetworkInterfaces = append(instance.NetworkInterfaces, parseNetworkInterface(eniMap))
+				}
+			}
+			sort.Slice(instance.NetworkInterfaces, func(i, j int) bool {
+				return instance.NetworkInterfaces[i].DeviceIndex < instance.NetworkInterfaces[j].DeviceIndex
+			})
+		}
+
 		instances = append(instances, instance)
 	}
 
@@ -132,6 +186,9 @@ func ParseEC2Instances(plan *TerraformPlan) []models.EC2Instance {
 func parseBlockDevice(bd map[string]interface{}) models.BlockDevice {
 	device := models.BlockDevice{}
 
+	if v, ok := bd["device_name"].(string); ok {
+		device.DeviceName = v
+	}
 	if v, ok := bd["volume_type"].(string); ok {
 		device.VolumeType = v
 	}
@@ -142,7 +199,7 @@ func parseBlockDevice(bd map[string]interface{}) models.BlockDevice {
 		device.DeleteOnTermination = v
 	}
 	if v, ok := bd["encrypted"].(bool); ok {
-		device.Encrypted = v
+		device.Encrypted = &v
 	}
 	if v, ok := bd["iops"].(float64); ok {
 		device.IOPS = int(v)
@@ -153,3 +210,54 @@ func parseBlockDevice(bd map[string]interface{}) models.BlockDevice {
 
 	return device
 }
+
+// parseMetadataOptions parses a metadata_options block from Terraform plan.
+func parseMetadataOptions(mo map[string]interface{}) models.MetadataOptions {
+	opts := models.MetadataOptions{}
+
+	if v, ok := mo["http_tokens"].(string); ok {
+		opts.HTTPTokens = v
+	}
+	if v, ok := mo["http_endpoint"].(string); ok {
+		opts.HTTPEndpoint = v
+	}
+	if v, ok := mo["http_put_response_hop_limit"].(float64); ok {
+		opts.HTTPPutResponseHopLimit = int(v)
+	}
+	if v, ok := mo["instance_metadata_tags"].(string); ok {
+		opts.InstanceMetadataTags = v
+	}
+
+	return opts
+}
+
+// parseNetworkInterface parses a network_interface block from Terraform plan.
+func parseNetworkInterface(ni map[string]interface{}) models.NetworkInterface {
+	eni := models.NetworkInterface{}
+
+	if v, ok := ni["network_interface_id"].(string); ok {
+		eni.NetworkInterfaceID = v
+	}
+	if v, ok := ni["device_index"].(float64); ok {
+		eni.DeviceIndex = int(v)
+	}
+	if v, ok := ni["delete_on_termination"].(bool); ok {
+		eni.DeleteOnTermination = v
+	}
+
+	return eni
+}
+
+// userDataHash normalises a planned user_data value to its SHA-1 hash.
+//
+// Plans normally carry the hash the provider computed already; raw scripts
+// (e.g. from older providers) are hashed here so both forms compare equal
+// to the live value.
+func userDataHash(v string) string {
+	if len(v) == 40 {
+		if _, err := hex.DecodeString(v); err == nil {
+			return v
+		}
+	}
+	return models.HashUserData([]byte(v))
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
@@ -10,6 +13,8 @@ import (
 //   - name, path, description
 //   - assume_role_policy (JSON trust policy)
 //   - max_session_duration
+//   - permissions_boundary
+//   - managed_policy_arns and inline_policy blocks
 //   - tags
 //
 // Resources being deleted (with nil "after" state) are skipped.
@@ -46,6 +51,34 @@ func ParseIAMRoles(plan *TerraformPlan) []models.IAMRole {
 		if v, ok := after["max_session_duration"].(float64); ok {
 			role.MaxSessionDuration = int(v)
 		}
+		if v, ok := after["permissions_boundary"].(string); ok {
+			role.PermissionsBoundary = v
+		}
+
+		// Exclusive managed policy attachments and inline policies
+		for _, arn := range stringList(after["managed_policy_arns"]) {
+			role.AttachedPolicies = appendUnique(role.AttachedPolicies, arn)
+		}
+		if inline, ok := after["inline_policy"].([]interface{}); ok {
+			for _, raw := range inline {
+				m, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := m["name"].(string)
+				doc, _ := m["policy"].(string)
+				switch {
+				case name == "" && doc == "":
+					// The provider reports an unset inline_policy as one empty block
+				case name == "" || doc == "":
+					// A generated name, or a document built from values
+					// only known after apply
+					role.InlinePoliciesUnknown = true
+				default:
+					role.InlinePolicies = setInlinePolicy(role.InlinePolicies, name, doc)
+				}
+			}
+		}
 
 		// Tags
 		if tags, ok := after["tags"].(map[string]interface{}); ok {
@@ -75,6 +108,7 @@ func ParseIAMRoles(plan *TerraformPlan) []models.IAMRole {
 //
 // Parses the following attributes from each user:
 //   - name, path
+//   - permissions_boundary
 //   - tags
 //
 // Resources being deleted (with nil "after" state) are skipped.
@@ -102,6 +136,9 @@ func ParseIAMUsers(plan *TerraformPlan) []models.IAMUser {
 		if v, ok := after["path"].(string); ok {
 			user.Path = v
 		}
+		if v, ok := after["permissions_boundary"].(string); ok {
+			user.PermissionsBoundary = v
+		}
 
 		// Tags
 		if tags, ok := after["tags"].(map[string]interface{}); ok {
@@ -226,13 +263,185 @@ func ParseIAMGroups(plan *TerraformPlan) []models.IAMGroup {
 	return groups
 }
 
+// ParseIAMInstanceProfiles extracts aws_iam_instance_profile resources from a Terraform plan.
+//
+// Parses the following attributes from each instance profile:
+//   - name, path
+//   - role
+//   - tags
+//
+// Resources being deleted (with nil "after" state) are skipped.
+func ParseIAMInstanceProfiles(plan *TerraformPlan) []models.IAMInstanceProfile {
+	var profiles []models.IAMInstanceProfile
+
+	for _, rc := range plan.ResourceChanges {
+		if rc.Type != "aws_iam_instance_profile" {
+			continue
+		}
+		after := rc.Change.After
+		if after == nil {
+			continue
+		}
+
+		profile := models.IAMInstanceProfile{
+			TerraformAddress: rc.Address,
+			Roles:            make([]string, 0, 1),
+			Tags:             make(map[string]string),
+		}
+
+		if v, ok := after["name"].(string); ok {
+			profile.InstanceProfileName = v
+		}
+		if v, ok := after["path"].(string); ok {
+			profile.Path = v
+		}
+		if v, ok := after["role"].(string); ok && v != "" {
+			profile.Roles = append(profile.Roles, v)
+		}
+
+		// Tags
+		if tags, ok := after["tags"].(map[string]interface{}); ok {
+			for k, v := range tags {
+				if vStr, ok := v.(string); ok {
+					profile.Tags[k] = vStr
+				}
+			}
+		}
+		if tags, ok := after["tags_all"].(map[string]interface{}); ok {
+			for k, v := range tags {
+				if vStr, ok := v.(string); ok {
+					if _, exists := profile.Tags[k]; !exists {
+						profile.Tags[k] = vStr
+					}
+				}
+			}
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	return profiles
+}
+
 // ParseAllIAMResources extracts all IAM resources from a Terraform plan.
-// This is a convenience function that calls all four IAM parsers.
+// This is a convenience function that calls all five IAM parsers, then
+// merges inline policies and policy attachments declared as standalone
+// resources (see applyIAMSubresources).
 func ParseAllIAMResources(plan *TerraformPlan) *models.IAMPlanResources {
-	return &models.IAMPlanResources{
-		Roles:    ParseIAMRoles(plan),
-		Users:    ParseIAMUsers(plan),
-		Policies: ParseIAMPolicies(plan),
-		Groups:   ParseIAMGroups(plan),
+	res := &models.IAMPlanResources{
+		Roles:            ParseIAMRoles(plan),
+		Users:            ParseIAMUsers(plan),
+		Policies:         ParseIAMPolicies(plan),
+		Groups:           ParseIAMGroups(plan),
+		InstanceProfiles: ParseIAMInstanceProfiles(plan),
+	}
+	applyIAMSubresources(plan, res)
+	return res
+}
+
+// applyIAMSubresources merges standalone inline policy and policy attachment
+// resources into the role, user or group they belong to.
+//
+// Each resource is matched to its principal by name. Resources whose
+// principal is unknown at plan time or not in the plan are ignored. An
+// inline policy whose name or document is unknown at plan time marks the
+// principal's inline policies as unknown, since comparing them would report
+// the policy as drifted.
+//
+// Supported resources:
+//   - aws_iam_role_policy, aws_iam_user_policy, aws_iam_group_policy
+//   - aws_iam_role_policy_attachment, aws_iam_user_policy_attachment,
+//     aws_iam_group_policy_attachment
+func applyIAMSubresources(plan *TerraformPlan, res *models.IAMPlanResources) {
+	roles := make(map[string]*models.IAMRole, len(res.Roles))
+	for i := range res.Roles {
+		roles[res.Roles[i].RoleName] = &res.Roles[i]
+	}
+	users := make(map[string]*models.IAMUser, len(res.Users))
+	for i := range res.Users {
+		users[res.Users[i].UserName] = &res.Users[i]
+	}
+	groups := make(map[string]*models.IAMGroup, len(res.Groups))
+	for i := range res.Groups {
+		groups[res.Groups[i].GroupName] = &res.Groups[i]
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		after := rc.Change.After
+		if after == nil {
+			continue
+		}
+		name, nameOK := knownString(rc, "name")
+		doc, docOK := knownString(rc, "policy")
+		known := nameOK && docOK
+		arn, _ := after["policy_arn"].(string)
+
+		switch rc.Type {
+		case "aws_iam_role_policy":
+			if r := roles[principal(after, "role")]; r != nil && known {
+				r.InlinePolicies = setInlinePolicy(r.InlinePolicies, name, doc)
+			} else if r != nil {
+				r.InlinePoliciesUnknown = true
+			}
+		case "aws_iam_user_policy":
+			if u := users[principal(after, "user")]; u != nil && known {
+				u.InlinePolicies = setInlinePolicy(u.InlinePolicies, name, doc)
+			} else if u != nil {
+				u.InlinePoliciesUnknown = true
+			}
+		case "aws_iam_group_policy":
+			if g := groups[principal(after, "group")]; g != nil && known {
+				g.InlinePolicies = setInlinePolicy(g.InlinePolicies, name, doc)
+			} else if g != nil {
+				g.InlinePoliciesUnknown = true
+			}
+		case "aws_iam_role_policy_attachment":
+			if r := roles[principal(after, "role")]; r != nil && arn != "" {
+				r.AttachedPolicies = appendUnique(r.AttachedPolicies, arn)
+			}
+		case "aws_iam_user_policy_attachment":
+			if u := users[principal(after, "user")]; u != nil && arn != "" {
+				u.AttachedPolicies = appendUnique(u.AttachedPolicies, arn)
+			}
+		case "aws_iam_group_policy_attachment":
+			if g := groups[principal(after, "group")]; g != nil && arn != "" {
+				g.AttachedPolicies = appendUnique(g.AttachedPolicies, arn)
+			}
+		}
+	}
+}
+
+// principal returns the role, user or group name a standalone resource
+// refers to. Role attachments may reference the role by ARN, so only the
+// final path segment is kept.
+func principal(after map[string]interface{}, key string) string {
+	v, _ := after[key].(string)
+	return v[strings.LastIndex(v, "/")+1:]
+}
+
+// knownString returns the string value of an attribute of a planned
+// resource, and false if it is unset or only known after apply.
+func knownString(rc ResourceChange, key string) (string, bool) {
+	if unknown, _ := rc.Change.AfterUnknown[key].(bool); unknown {
+		return "", false
+	}
+	v, ok := rc.Change.After[key].(string)
+	return v, ok
+}
+
+// setInlinePolicy records an inline policy document, allocating the map on first use.
+func setInlinePolicy(policies map[string]string, name, doc string) map[string]string {
+	if policies == nil {
+		policies = make(map[string]string)
+	}
+	policies[name] = doc
+	return policies
+}
+
+// appendUnique appends s to list unless it is already present.
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
 	}
+	return append(list, s)
 }
@@ -53,6 +53,11 @@ type Change struct {
 	// For create/update actions, this represents the desired state.
 	// For delete actions, this is null.
 	After map[string]interface{} `json:"after"`
+
+	// AfterUnknown mirrors After with true for each value that is only known
+	// after apply, such as a generated name. Such values are absent from
+	// After.
+	AfterUnknown map[string]interface{} `json:"after_unknown"`
 }
 
 // LoadPlan reads a Terraform JSON plan file and extracts S3 bucket configurations.
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strconv"
+
 	"github.com/inayathulla/cloudrift/internal/models"
 )
 
@@ -10,13 +12,17 @@ import (
 // S3 bucket configurations from resources with type "aws_s3_bucket". It parses
 // the following attributes from each bucket:
 //
-//   - Bucket name and ACL
+//   - Bucket name, canned ACL and grants
 //   - Tags
 //   - Versioning configuration
 //   - Server-side encryption settings
 //   - Access logging configuration
 //   - Public Access Block settings
 //   - Lifecycle rules
+//   - Bucket policy, CORS rules, replication and object lock configuration
+//
+// Settings declared through standalone resources such as aws_s3_bucket_policy
+// are merged into their bucket (see applyS3BucketSubresources).
 //
 // Resources being deleted (with nil "after" state) are skipped.
 //
@@ -47,10 +53,13 @@ func ParseS3Buckets(plan *TerraformPlan) []models.S3Bucket {
 			bucket.Name = name
 		}
 
-		// 2) ACL
+		// 2) ACL and explicit grants
 		if acl, ok := after["acl"].(string); ok {
 			bucket.Acl = acl
 		}
+		if grants, ok := after["grant"].([]interface{}); ok {
+			bucket.AclGrants = parseGrants(grants)
+		}
 
 		// 3) Tags
 		if tagsRaw, ok := after["tags"].(map[string]interface{}); ok {
@@ -69,16 +78,8 @@ func ParseS3Buckets(plan *TerraformPlan) []models.S3Bucket {
 		}
 
 		// 5) Encryption
-		if encRaw, ok := after["server_side_encryption_configuration"].(map[string]interface{}); ok {
-			if rules, ok := encRaw["rules"].([]interface{}); ok && len(rules) > 0 {
-				if rule0, ok := rules[0].(map[string]interface{}); ok {
-					if apply, ok := rule0["apply_server_side_encryption_by_default"].(map[string]interface{}); ok {
-						if algo, ok := apply["sse_algorithm"].(string); ok {
-							bucket.EncryptionAlgorithm = algo
-						}
-					}
-				}
-			}
+		if encRaw, ok := block(after["server_side_encryption_configuration"]); ok {
+			parseEncryption(encRaw, &bucket)
 		}
 
 		// 6) Access Logging
@@ -112,39 +113,434 @@ func ParseS3Buckets(plan *TerraformPlan) []models.S3Bucket {
 
 		// 8) Lifecycle Rules
 		if lcRaw, ok := after["lifecycle_rule"].([]interface{}); ok {
-			for _, raw := range lcRaw {
-				if m, ok := raw.(map[string]interface{}); ok {
-					var rule models.LifecycleRuleSummary
-
-					// ID
-					if id, ok := m["id"].(string); ok {
-						rule.ID = id
-					}
-
-					// Status
-					if st, ok := m["status"].(string); ok {
-						rule.Status = st
-					}
-
-					// Prefix (if provided)
-					if prefix, ok := m["prefix"].(string); ok {
-						rule.Prefix = prefix
-					}
-
-					// Expiration days
-					if expRaw, ok := m["expiration"].(map[string]interface{}); ok {
-						if daysF, ok := expRaw["days"].(float64); ok {
-							rule.ExpirationDays = int(daysF)
-						}
-					}
-
-					bucket.LifecycleRules = append(bucket.LifecycleRules, rule)
-				}
-			}
+			bucket.LifecycleRules = parseLifecycleRules(lcRaw)
+		}
+
+		// 9) Bucket policy
+		if pol, ok := after["policy"].(string); ok {
+			bucket.Policy = pol
+		}
+
+		// 10) CORS rules
+		if cors, ok := after["cors_rule"].([]interface{}); ok {
+			bucket.CORSRules = parseCORSRules(cors)
+		}
+
+		// 11) Replication
+		if repRaw, ok := block(after["replication_configuration"]); ok {
+			bucket.Replication = parseReplication(repRaw)
+		}
+
+		// 12) Object lock
+		if enabled, ok := after["object_lock_enabled"].(bool); ok {
+			bucket.ObjectLock.Enabled = enabled
+		}
+		if olRaw, ok := block(after["object_lock_configuration"]); ok {
+			bucket.ObjectLock = parseObjectLock(olRaw, bucket.ObjectLock.Enabled)
 		}
 
 		buckets = append(buckets, bucket)
 	}
 
+	applyS3BucketSubresources(plan, buckets)
+
 	return buckets
 }
+
+// applyS3BucketSubresources merges the standalone bucket configuration
+// resources introduced in AWS provider v4 into the parsed buckets.
+//
+// Each resource is matched to its bucket by the "bucket" attribute. Resources
+// whose bucket is unknown at plan time or not in the plan are ignored.
+//
+// Supported resources:
+//   - aws_s3_bucket_policy
+//   - aws_s3_bucket_ownership_controls
+//   - aws_s3_bucket_server_side_encryption_configuration
+//   - aws_s3_bucket_cors_configuration
+//   - aws_s3_bucket_replication_configuration
+//   - aws_s3_bucket_object_lock_configuration
+//   - aws_s3_bucket_lifecycle_configuration
+func applyS3BucketSubresources(plan *TerraformPlan, buckets []models.S3Bucket) {
+	byName := make(map[string]*models.S3Bucket, len(buckets))
+	for i := range buckets {
+		byName[buckets[i].Name] = &buckets[i]
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		after := rc.Change.After
+		if after == nil {
+			continue
+		}
+		name, _ := after["bucket"].(string)
+		bucket := byName[name]
+		if bucket == nil {
+			continue
+		}
+
+		switch rc.Type {
+		case "aws_s3_bucket_policy":
+			if pol, ok := after["policy"].(string); ok {
+				bucket.Policy = pol
+			}
+		case "aws_s3_bucket_ownership_controls":
+			if rule, ok := block(after["rule"]); ok {
+				if v, ok := rule["object_ownership"].(string); ok {
+					bucket.ObjectOwnership = v
+				}
+			}
+		case "aws_s3_bucket_server_side_encryption_configuration":
+			parseEncryption(after, bucket)
+		case "aws_s3_bucket_cors_configuration":
+			if cors, ok := after["cors_rule"].([]interface{}); ok {
+				bucket.CORSRules = parseCORSRules(cors)
+			}
+		case "aws_s3_bucket_replication_configuration":
+			bucket.Replication = parseReplication(after)
+		case "aws_s3_bucket_object_lock_configuration":
+			bucket.ObjectLock = parseObjectLock(after, true)
+		case "aws_s3_bucket_lifecycle_configuration":
+			if rules, ok := after["rule"].([]interface{}); ok {
+				bucket.LifecycleRules = parseLifecycleRules(rules)
+			}
+		}
+	}
+}
+
+// block returns a nested configuration block. Plans encode blocks as a
+// single-element list, while older fixtures use a plain object; both are accepted.
+func block(v interface{}) (map[string]interface{}, bool) {
+	switch b := v.(type) {
+	case map[string]interface{}:
+		return b, true
+	case []interface{}:
+		if len(b) > 0 {
+			m, ok := b[0].(map[string]interface{})
+			return m, ok
+		}
+	}
+	return nil, false
+}
+
+// stringList converts a plan list of strings to a []string.
+func stringList(v interface{}) []string {
+	raw, _ := v.([]interface{})
+	var out []string
+	for _, r := range raw {
+		if s, ok := r.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// intValue reads a plan number. Some provider versions encode numeric
+// attributes (e.g., newer_noncurrent_versions) as strings; both are accepted.
+func intValue(v interface{}) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+// dateOnly reduces an RFC 3339 plan date to YYYY-MM-DD, the form the
+// fetcher reports.
+func dateOnly(v interface{}) string {
+	s, _ := v.(string)
+	if len(s) > 10 {
+		return s[:10]
+	}
+	return s
+}
+
+// parseEncryption reads the first server-side encryption rule (the "rules"
+// list on aws_s3_bucket, or the "rule" block on the standalone resource).
+func parseEncryption(enc map[string]interface{}, bucket *models.S3Bucket) {
+	rule, ok := block(enc["rules"])
+	if !ok {
+		rule, ok = block(enc["rule"])
+	}
+	if !ok {
+		return
+	}
+	if apply, ok := block(rule["apply_server_side_encryption_by_default"]); ok {
+		if algo, ok := apply["sse_algorithm"].(string); ok {
+			bucket.EncryptionAlgorithm = algo
+		}
+		if key, ok := apply["kms_master_key_id"].(string); ok {
+			bucket.KMSKeyID = key
+		}
+	}
+	if v, ok := rule["bucket_key_enabled"].(bool); ok {
+		bucket.BucketKeyEnabled = v
+	}
+}
+
+// parseCORSRules converts cors_rule blocks into CORS rules.
+func parseCORSRules(raw []interface{}) []models.CORSRule {
+	var rules []models.CORSRule
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rule := models.CORSRule{
+			AllowedHeaders: stringList(m["allowed_headers"]),
+			AllowedMethods: stringList(m["allowed_methods"]),
+			AllowedOrigins: stringList(m["allowed_origins"]),
+			ExposeHeaders:  stringList(m["expose_headers"]),
+		}
+		if id, ok := m["id"].(string); ok {
+			rule.ID = id
+		}
+		if age, ok := m["max_age_seconds"].(float64); ok {
+			rule.MaxAgeSeconds = int(age)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// parseReplication converts a replication configuration. The inline block
+// names its rules "rules"; the standalone resource uses "rule".
+func parseReplication(rep map[string]interface{}) models.ReplicationConfig {
+	var cfg models.ReplicationConfig
+	if role, ok := rep["role"].(string); ok {
+		cfg.Role = role
+	}
+
+	raw, ok := rep["rules"].([]interface{})
+	if !ok {
+		raw, _ = rep["rule"].([]interface{})
+	}
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var rule models.ReplicationRule
+		if v, ok := m["id"].(string); ok {
+			rule.ID = v
+		}
+		if v, ok := m["status"].(string); ok {
+			rule.Status = v
+		}
+		if v, ok := m["priority"].(float64); ok {
+			rule.Priority = int(v)
+		}
+		if v, ok := m["prefix"].(string); ok {
+			rule.Prefix = v
+		}
+		if filter, ok := block(m["filter"]); ok {
+			if v, ok := filter["prefix"].(string); ok && v != "" {
+				rule.Prefix = v
+			}
+		}
+		if dest, ok := block(m["destination"]); ok {
+			if v, ok := dest["bucket"].(string); ok {
+				rule.DestinationBucket = v
+			}
+			if v, ok := dest["storage_class"].(string); ok {
+				rule.StorageClass = v
+			}
+		}
+		// Inline rules carry a status string; standalone rules a nested block
+		if v, ok := m["delete_marker_replication_status"].(string); ok {
+			rule.DeleteMarkerReplication = v == "Enabled"
+		}
+		if dmr, ok := block(m["delete_marker_replication"]); ok {
+			if v, ok := dmr["status"].(string); ok {
+				rule.DeleteMarkerReplication = v == "Enabled"
+			}
+		}
+		cfg.Rules = append(cfg.Rules, rule)
+	}
+	return cfg
+}
+
+// parseObjectLock converts an object lock configuration. enabled is the
+// value implied by the enclosing resource; an explicit object_lock_enabled
+// of "Enabled" also turns it on.
+func parseObjectLock(ol map[string]interface{}, enabled bool) models.ObjectLockConfig {
+	cfg := models.ObjectLockConfig{Enabled: enabled}
+	if v, ok := ol["object_lock_enabled"].(string); ok && v == "Enabled" {
+		cfg.Enabled = true
+	}
+	if rule, ok := block(ol["rule"]); ok {
+		if ret, ok := block(rule["default_retention"]); ok {
+			if v, ok := ret["mode"].(string); ok {
+				cfg.Mode = v
+			}
+			if v, ok := ret["days"].(float64); ok {
+				cfg.Days = int(v)
+			}
+			if v, ok := ret["years"].(float64); ok {
+				cfg.Years = int(v)
+			}
+		}
+	}
+	return cfg
+}
+
+// parseGrants converts aws_s3_bucket grant blocks into normalized grants.
+// Each block carries a list of permissions, which is expanded to one grant
+// per permission.
+func parseGrants(raw []interface{}) []models.AclGrant {
+	var grants []models.AclGrant
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		granteeType, _ := m["type"].(string)
+		id, _ := m["id"].(string)
+		uri, _ := m["uri"].(string)
+		email, _ := m["email"].(string)
+		perms, _ := m["permissions"].([]interface{})
+		for _, p := range perms {
+			if perm, ok := p.(string); ok {
+				grants = append(grants, models.NewAclGrant(granteeType, id, uri, email, perm))
+			}
+		}
+	}
+	models.SortAclGrants(grants)
+	return grants
+}
+
+// parseLifecycleRules converts lifecycle rules. It accepts both the inline
+// lifecycle_rule blocks of aws_s3_bucket (provider v3: enabled, tags,
+// abort_incomplete_multipart_upload_days) and the rule blocks of
+// aws_s3_bucket_lifecycle_configuration (provider v4+: status, filter,
+// nested abort_incomplete_multipart_upload).
+func parseLifecycleRules(raw []interface{}) []models.LifecycleRuleSummary {
+	var rules []models.LifecycleRuleSummary
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var rule models.LifecycleRuleSummary
+
+		if v, ok := m["id"].(string); ok {
+			rule.ID = v
+		}
+		if v, ok := m["status"].(string); ok {
+			rule.Status = v
+		}
+		if v, ok := m["enabled"].(bool); ok {
+			rule.Status = "Disabled"
+			if v {
+				rule.Status = "Enabled"
+			}
+		}
+		if v, ok := m["prefix"].(string); ok {
+			rule.Prefix = v
+		}
+		if tags, ok := m["tags"].(map[string]interface{}); ok {
+			rule.FilterTags = stringMap(tags)
+		}
+
+		// Filter (standalone resource)
+		if f, ok := block(m["filter"]); ok {
+			parseLifecycleFilter(f, &rule)
+			if and, ok := block(f["and"]); ok {
+				parseLifecycleFilter(and, &rule)
+				if tags, ok := and["tags"].(map[string]interface{}); ok && len(tags) > 0 {
+					rule.FilterTags = stringMap(tags)
+				}
+			}
+			if tag, ok := block(f["tag"]); ok {
+				key, _ := tag["key"].(string)
+				value, _ := tag["value"].(string)
+				rule.FilterTags = map[string]string{key: value}
+			}
+		}
+
+		// Expiration
+		if exp, ok := block(m["expiration"]); ok {
+			rule.ExpirationDays = int(intValue(exp["days"]))
+			rule.ExpirationDate = dateOnly(exp["date"])
+			if v, ok := exp["expired_object_delete_marker"].(bool); ok {
+				rule.ExpiredObjectDeleteMarker = v
+			}
+		}
+		if exp, ok := block(m["noncurrent_version_expiration"]); ok {
+			rule.NoncurrentExpirationDays = int(intValue(exp["days"]))
+			if v := intValue(exp["noncurrent_days"]); v != 0 {
+				rule.NoncurrentExpirationDays = int(v)
+			}
+			rule.NewerNoncurrentVersions = int(intValue(exp["newer_noncurrent_versions"]))
+		}
+
+		// Transitions
+		if ts, ok := m["transition"].([]interface{}); ok {
+			rule.Transitions = parseLifecycleTransitions(ts)
+		}
+		if ts, ok := m["noncurrent_version_transition"].([]interface{}); ok {
+			rule.NoncurrentTransitions = parseLifecycleTransitions(ts)
+		}
+
+		// Abort incomplete multipart uploads
+		rule.AbortIncompleteMultipartDays = int(intValue(m["abort_incomplete_multipart_upload_days"]))
+		if abort, ok := block(m["abort_incomplete_multipart_upload"]); ok {
+			rule.AbortIncompleteMultipartDays = int(intValue(abort["days_after_initiation"]))
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// parseLifecycleFilter reads the prefix and object size limits of a
+// lifecycle filter or its "and" operator.
+func parseLifecycleFilter(f map[string]interface{}, rule *models.LifecycleRuleSummary) {
+	if v, ok := f["prefix"].(string); ok && v != "" {
+		rule.Prefix = v
+	}
+	if v := intValue(f["object_size_greater_than"]); v != 0 {
+		rule.ObjectSizeGreaterThan = v
+	}
+	if v := intValue(f["object_size_less_than"]); v != 0 {
+		rule.ObjectSizeLessThan = v
+	}
+}
+
+// parseLifecycleTransitions converts transition or noncurrent_version_transition
+// blocks, which name the day count "days" or "noncurrent_days".
+func parseLifecycleTransitions(raw []interface{}) []models.LifecycleTransition {
+	var ts []models.LifecycleTransition
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t := models.LifecycleTransition{
+			Days: int(intValue(m["days"])),
+			Date: dateOnly(m["date"]),
+		}
+		if v := intValue(m["noncurrent_days"]); v != 0 {
+			t.Days = int(v)
+		}
+		if v, ok := m["storage_class"].(string); ok {
+			t.StorageClass = v
+		}
+		ts = append(ts, t)
+	}
+	models.SortLifecycleTransitions(ts)
+	return ts
+}
+
+// stringMap converts a plan map of strings to a map[string]string.
+func stringMap(raw map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
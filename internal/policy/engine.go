@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/open-policy-agent/opa/ast"
@@ -15,6 +16,7 @@ import (
 // Engine evaluates resources against OPA policies.
 type Engine struct {
 	modules     map[string]*ast.Module
+	sources     map[string]string
 	compiler    *ast.Compiler
 	policyPaths []string
 }
@@ -23,6 +25,7 @@ type Engine struct {
 func NewEngine(policyPaths ...string) (*Engine, error) {
 	e := &Engine{
 		modules:     make(map[string]*ast.Module),
+		sources:     make(map[string]string),
 		policyPaths: policyPaths,
 	}
 
@@ -79,6 +82,7 @@ func (e *Engine) loadPolicyFile(path string) error {
 	}
 
 	e.modules[path] = module
+	e.sources[path] = string(content)
 	return nil
 }
 
@@ -118,6 +122,9 @@ func (e *Engine) Evaluate(ctx context.Context, input *PolicyInput) (*EvaluationR
 }
 
 // EvaluateAll runs policies against multiple inputs.
+//
+// It stops at the first input evaluated after ctx is cancelled and returns
+// ctx.Err(), so a cancelled scan never reports a partial policy result.
 func (e *Engine) EvaluateAll(ctx context.Context, inputs []*PolicyInput) (*EvaluationResult, error) {
 	combined := &EvaluationResult{
 		Violations: make([]Violation, 0),
@@ -125,6 +132,9 @@ func (e *Engine) EvaluateAll(ctx context.Context, inputs []*PolicyInput) (*Evalu
 	}
 
 	for _, input := range inputs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		result, err := e.Evaluate(ctx, input)
 		if err != nil {
 			return nil, err
@@ -288,6 +298,48 @@ func (e *Engine) PolicyCount() int {
 	return len(e.modules)
 }
 
+// Policies returns metadata for every loaded policy, built-in and custom,
+// sorted by ID.
+//
+// Metadata is read from the result objects in each module, as for the
+// built-in registry. A module with deny or warn rules but no policy_id
+// fields is listed under its package path, which is the ID its violations
+// are reported with.
+func (e *Engine) Policies() []PolicyInfo {
+	reg := &PolicyRegistry{Policies: make(map[string]PolicyInfo)}
+
+	paths := make([]string, 0, len(e.modules))
+	for path := range e.modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		source := e.sources[path]
+		if policyIDRe.MatchString(source) {
+			extractPolicyMetadata(reg, source, inferCategoryFromPath(filepath.ToSlash(path)))
+			continue
+		}
+		module := e.modules[path]
+		for _, rule := range module.Rules {
+			if name := rule.Head.Name.String(); name == "deny" || name == "warn" {
+				pkg := strings.TrimPrefix(module.Package.Path.String(), "data.")
+				if _, exists := reg.Policies[pkg]; !exists {
+					reg.Policies[pkg] = PolicyInfo{ID: pkg, Category: inferCategoryFromPath(filepath.ToSlash(path))}
+				}
+				break
+			}
+		}
+	}
+
+	policies := make([]PolicyInfo, 0, len(reg.Policies))
+	for _, p := range reg.Policies {
+		policies = append(policies, p)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+	return policies
+}
+
 // toMap converts a struct to map[string]interface{}.
 func toMap(v interface{}) (map[string]interface{}, error) {
 	data, err := json.Marshal(v)
@@ -4,14 +4,22 @@ import (
 	"io/fs"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 // PolicyInfo holds metadata for a single built-in policy rule.
+//
+// Name, Severity and Remediation are taken from the first result object
+// declaring the policy ID; multi-rule policies may report other severities
+// or remediation text for individual violations.
 type PolicyInfo struct {
-	ID         string   `json:"id"`
-	Category   string   `json:"category"`
-	Frameworks []string `json:"frameworks"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	Category    string   `json:"category"`
+	Frameworks  []string `json:"frameworks"`
 }
 
 // PolicyRegistry provides aggregated metadata about all built-in policies.
@@ -26,6 +34,9 @@ type PolicyRegistry struct {
 // Regex patterns for extracting policy metadata from .rego files.
 var (
 	policyIDRe     = regexp.MustCompile(`"policy_id"\s*:\s*"([^"]+)"`)
+	policyNameRe   = regexp.MustCompile(`"policy_name"\s*:\s*"([^"]+)"`)
+	severityRe     = regexp.MustCompile(`"severity"\s*:\s*"([^"]+)"`)
+	remediationRe  = regexp.MustCompile(`"remediation"\s*:\s*("(?:[^"\\]|\\.)*")`)
 	categoryRe     = regexp.MustCompile(`"category"\s*:\s*"([^"]+)"`)
 	frameworksRe   = regexp.MustCompile(`"frameworks"\s*:\s*\[([^\]]*)\]`)
 	quotedStringRe = regexp.MustCompile(`"([^"]+)"`)
@@ -207,10 +218,22 @@ func extractPolicyMetadata(reg *PolicyRegistry, content, dirCategory string) {
 			}
 		}
 
-		reg.Policies[policyID] = PolicyInfo{
+		info := PolicyInfo{
 			ID:         policyID,
 			Category:   category,
 			Frameworks: frameworks,
 		}
+		if m := policyNameRe.FindStringSubmatch(region); len(m) > 1 {
+			info.Name = m[1]
+		}
+		if m := severityRe.FindStringSubmatch(region); len(m) > 1 {
+			info.Severity = m[1]
+		}
+		if m := remediationRe.FindStringSubmatch(region); len(m) > 1 {
+			if text, err := strconv.Unquote(m[1]); err == nil {
+				info.Remediation = text
+			}
+		}
+		reg.Policies[policyID] = info
 	}
 }
@@ -0,0 +1,145 @@
+package remediation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// argumentIndent is the indentation of arguments in a top-level block, as
+// written by terraform fmt.
+const argumentIndent = 2
+
+// ApplyResult reports what Apply changed.
+type ApplyResult struct {
+	// Applied lists the addresses whose resource block was updated.
+	Applied []string
+
+	// Files lists the .tf files that were rewritten.
+	Files []string
+
+	// Skipped maps addresses that could not be applied to the reason.
+	Skipped map[string]string
+}
+
+// Apply writes patches into the .tf files of a root module directory.
+//
+// Each patch is applied to the resource block with the matching type and
+// name: its arguments are set to the live values, replacing any existing
+// expression, and the rest of the file is left untouched: only the tokens
+// of the written arguments are formatted. Resources inside
+// modules, and resources using count or for_each, are skipped because their
+// block is shared with other instances. Files are only rewritten if a patch
+// applied to them.
+//
+// Parameters:
+//   - dir: the root module directory containing the .tf files
+//   - patches: patches from Build
+//
+// Returns:
+//   - ApplyResult: the applied and skipped addresses and the rewritten files
+//   - error: if a .tf file cannot be read, parsed or written
+func Apply(dir string, patches []Patch) (ApplyResult, error) {
+	result := ApplyResult{Skipped: make(map[string]string)}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return result, err
+	}
+	sort.Strings(paths)
+
+	files := make([]*hclwrite.File, len(paths))
+	for i, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return result, err
+		}
+		f, diags := hclwrite.ParseConfig(src, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return result, fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+		}
+		files[i] = f
+	}
+
+	changed := make([]bool, len(paths))
+	for _, p := range patches {
+		resourceType, name, indexed := splitAddress(p.Address)
+		if indexed {
+			result.Skipped[p.Address] = "resource is in a module or uses count/for_each"
+			continue
+		}
+		found := false
+		for i, f := range files {
+			block := f.Body().FirstMatchingBlock("resource", []string{resourceType, name})
+			if block == nil {
+				continue
+			}
+			for _, c := range p.Changes {
+				setArgument(block.Body(), c.Attribute, ctyValue(c.Value))
+			}
+			changed[i] = true
+			found = true
+			break
+		}
+		if found {
+			result.Applied = append(result.Applied, p.Address)
+		} else {
+			result.Skipped[p.Address] = fmt.Sprintf("no resource block found in %s", dir)
+		}
+	}
+
+	for i, path := range paths {
+		if !changed[i] {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return result, err
+		}
+		// File.Bytes would reformat the whole file, so the tokens are
+		// written as they are.
+		var buf bytes.Buffer
+		if _, err := files[i].BuildTokens(nil).WriteTo(&buf); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, path)
+	}
+	return result, nil
+}
+
+// setArgument sets an argument of a top-level block to value. The written
+// tokens are formatted as terraform fmt would, without alignment with
+// neighbouring arguments; the tokens around them are not touched.
+func setArgument(body *hclwrite.Body, name string, value cty.Value) {
+	// Format the argument on its own, then indent its continuation lines
+	scratch := hclwrite.NewEmptyFile()
+	scratch.Body().SetAttributeValue(name, value)
+	formatted, _ := hclwrite.ParseConfig(scratch.Bytes(), "", hcl.InitialPos)
+	expr := formatted.Body().GetAttribute(name).Expr().BuildTokens(nil)
+	for i := 1; i < len(expr); i++ {
+		if expr[i-1].Type == hclsyntax.TokenNewline {
+			expr[i].SpacesBefore += argumentIndent
+		}
+	}
+
+	existing := body.GetAttribute(name) != nil
+	body.SetAttributeRaw(name, expr)
+	if !existing {
+		// A new argument starts with its name and equals sign, unspaced.
+		// It is looked up again: hcl v2.24 SetAttributeRaw returns nil for
+		// new attributes.
+		tokens := body.GetAttribute(name).BuildTokens(nil)
+		tokens[0].SpacesBefore = argumentIndent
+		tokens[1].SpacesBefore = 1
+	}
+}
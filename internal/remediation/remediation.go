@@ -0,0 +1,407 @@
+// Package remediation turns detected drift into Terraform configuration
+// changes that accept the live AWS state.
+//
+// When drift is detected and AWS is deemed correct, the configuration has to
+// be updated to match. Build produces, for each drifted resource, the
+// attribute values that would make its configuration match AWS. The patches
+// can be printed as HCL, or written into the .tf files with Apply.
+//
+// Only attributes that map one-to-one onto a top-level argument of the
+// resource are patched. Drift in nested blocks (volumes, metadata options),
+// in separate sub-resources (S3 versioning, encryption) and in attachments
+// is still reported by the detectors but has no patch.
+package remediation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// Change sets one argument of a resource to its live value.
+type Change struct {
+	// Attribute is the Terraform argument name (e.g., "instance_type").
+	Attribute string `json:"attribute"`
+
+	// Value is the live value: a string, int, bool, []string or
+	// map[string]string.
+	Value interface{} `json:"value"`
+}
+
+// Patch contains the changes that make one resource's configuration match AWS.
+type Patch struct {
+	// Address is the Terraform resource address (e.g., "aws_instance.web").
+	Address string `json:"address"`
+
+	// ResourceType is the Terraform resource type (e.g., "aws_instance").
+	ResourceType string `json:"resource_type"`
+
+	// ResourceName is the AWS name of the resource.
+	ResourceName string `json:"resource_name"`
+
+	// Changes lists the arguments to update, sorted by name.
+	Changes []Change `json:"changes"`
+}
+
+// Build computes patches for every planned resource whose live state differs.
+//
+// Resources are paired and compared exactly as the service detector does.
+// Missing resources and resources whose live state could not be fetched get
+// no patch.
+//
+// Parameters:
+//   - plan: planned resources ([]models.S3Bucket, []models.EC2Instance or *models.IAMPlanResources)
+//   - live: the live state returned by the matching detector's FetchLiveState
+//
+// Returns:
+//   - []Patch: patches sorted by address
+//   - error: if the plan and live state types are not recognised
+func Build(plan, live interface{}) ([]Patch, error) {
+	var patches []Patch
+	add := func(p Patch) {
+		if len(p.Changes) > 0 && p.Address != "" {
+			sort.Slice(p.Changes, func(i, j int) bool { return p.Changes[i].Attribute < p.Changes[j].Attribute })
+			patches = append(patches, p)
+		}
+	}
+
+	switch p := plan.(type) {
+	case []models.S3Bucket:
+		l, ok := live.(*models.S3LiveState)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.S3LiveState")
+		}
+		failed := models.FailedResources(l.Errors)
+		byName := make(map[string]*models.S3Bucket, len(l.Buckets))
+		for i := range l.Buckets {
+			byName[l.Buckets[i].Name] = &l.Buckets[i]
+		}
+		for _, b := range p {
+			if actual := byName[b.Name]; actual != nil && !failed[models.ResourceKey("aws_s3_bucket", b.Name)] {
+				add(s3Patch(b, actual))
+			}
+		}
+
+	case []models.EC2Instance:
+		l, ok := live.(*models.EC2LiveState)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.EC2LiveState")
+		}
+		failed := models.FailedResources(l.Errors)
+		for _, inst := range p {
+			if actual := matchInstance(inst, l.Instances); actual != nil && !failed[models.ResourceKey("aws_instance", actual.InstanceID)] {
+				add(ec2Patch(inst, actual))
+			}
+		}
+
+	case *models.IAMPlanResources:
+		l, ok := live.(*models.IAMLiveState)
+		if !ok {
+			return nil, fmt.Errorf("live type mismatch: expected *models.IAMLiveState")
+		}
+		failed := models.FailedResources(l.Errors)
+		for _, r := range p.Roles {
+			if actual := findByName(l.Roles, r.RoleName, models.IAMRole.Name); actual != nil && !failed[models.ResourceKey("aws_iam_role", r.RoleName)] {
+				add(iamRolePatch(r, actual))
+			}
+		}
+		for _, u := range p.Users {
+			if actual := findByName(l.Users, u.UserName, models.IAMUser.Name); actual != nil && !failed[models.ResourceKey("aws_iam_user", u.UserName)] {
+				add(iamUserPatch(u, actual))
+			}
+		}
+		for _, pol := range p.Policies {
+			if actual := findByName(l.Policies, pol.PolicyName, models.IAMPolicy.Name); actual != nil && !failed[models.ResourceKey("aws_iam_policy", pol.PolicyName)] {
+				add(iamPolicyPatch(pol, actual))
+			}
+		}
+		for _, g := range p.Groups {
+			if actual := findByName(l.Groups, g.GroupName, models.IAMGroup.Name); actual != nil && !failed[models.ResourceKey("aws_iam_group", g.GroupName)] {
+				add(iamGroupPatch(g, actual))
+			}
+		}
+		for _, ip := range p.InstanceProfiles {
+			if actual := findByName(l.InstanceProfiles, ip.InstanceProfileName, models.IAMInstanceProfile.Name); actual != nil && !failed[models.ResourceKey("aws_iam_instance_profile", ip.InstanceProfileName)] {
+				add(iamInstanceProfilePatch(ip, actual))
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported plan type %T", plan)
+	}
+
+	sort.Slice(patches, func(i, j int) bool { return patches[i].Address < patches[j].Address })
+	return patches, nil
+}
+
+// s3Patch builds the patch for a bucket. Only tags are bucket arguments;
+// everything else lives in separate aws_s3_bucket_* resources.
+func s3Patch(plan models.S3Bucket, actual *models.S3Bucket) Patch {
+	res := detector.DetectS3Drift(plan, actual)
+	p := Patch{Address: plan.Id, ResourceType: "aws_s3_bucket", ResourceName: plan.Name}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// ec2Patch builds the patch for an instance.
+func ec2Patch(plan models.EC2Instance, actual *models.EC2Instance) Patch {
+	res := detector.DetectEC2Drift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_instance", ResourceName: plan.Name()}
+	if res.InstanceTypeDiff {
+		p.set("instance_type", actual.InstanceType)
+	}
+	if res.AMIDiff {
+		p.set("ami", actual.AMI)
+	}
+	if res.SubnetDiff {
+		p.set("subnet_id", actual.SubnetID)
+	}
+	if res.SecurityGroupsDiff {
+		p.set("vpc_security_group_ids", sortedCopy(actual.SecurityGroupIDs))
+	}
+	if res.EBSOptimizedDiff {
+		p.set("ebs_optimized", actual.EBSOptimized)
+	}
+	if res.MonitoringDiff {
+		p.set("monitoring", actual.Monitoring)
+	}
+	if res.KeyNameDiff {
+		p.set("key_name", actual.KeyName)
+	}
+	if res.IAMProfileDiff {
+		p.set("iam_instance_profile", models.InstanceProfileName(actual.IAMInstanceProfile))
+	}
+	if res.TerminationProtectionDiff {
+		p.set("disable_api_termination", actual.DisableAPITermination)
+	}
+	if res.StopProtectionDiff {
+		p.set("disable_api_stop", actual.DisableAPIStop)
+	}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// iamRolePatch builds the patch for a role.
+func iamRolePatch(plan models.IAMRole, actual *models.IAMRole) Patch {
+	res := detector.DetectIAMRoleDrift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_iam_role", ResourceName: plan.RoleName}
+	if res.AssumeRolePolicyDiff {
+		p.set("assume_role_policy", actual.AssumeRolePolicy)
+	}
+	if res.MaxSessionDiff {
+		p.set("max_session_duration", actual.MaxSessionDuration)
+	}
+	if res.DescriptionDiff {
+		p.set("description", actual.Description)
+	}
+	if res.PathDiff {
+		p.set("path", actual.Path)
+	}
+	if res.PermissionsBoundaryDiff {
+		p.set("permissions_boundary", actual.PermissionsBoundary)
+	}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// iamUserPatch builds the patch for a user.
+func iamUserPatch(plan models.IAMUser, actual *models.IAMUser) Patch {
+	res := detector.DetectIAMUserDrift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_iam_user", ResourceName: plan.UserName}
+	if res.PathDiff {
+		p.set("path", actual.Path)
+	}
+	if res.PermissionsBoundaryDiff {
+		p.set("permissions_boundary", actual.PermissionsBoundary)
+	}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// iamPolicyPatch builds the patch for a customer-managed policy.
+func iamPolicyPatch(plan models.IAMPolicy, actual *models.IAMPolicy) Patch {
+	res := detector.DetectIAMPolicyDrift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_iam_policy", ResourceName: plan.PolicyName}
+	if res.PolicyDocumentDiff {
+		p.set("policy", actual.PolicyDocument)
+	}
+	if res.DescriptionDiff {
+		p.set("description", actual.Description)
+	}
+	if res.PathDiff {
+		p.set("path", actual.Path)
+	}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// iamGroupPatch builds the patch for a group. Members and attachments are
+// separate resources and are not patched.
+func iamGroupPatch(plan models.IAMGroup, actual *models.IAMGroup) Patch {
+	res := detector.DetectIAMGroupDrift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_iam_group", ResourceName: plan.GroupName}
+	if res.PathDiff {
+		p.set("path", actual.Path)
+	}
+	return p
+}
+
+// iamInstanceProfilePatch builds the patch for an instance profile. The role
+// is patched only if exactly one role is attached, the most the argument holds.
+func iamInstanceProfilePatch(plan models.IAMInstanceProfile, actual *models.IAMInstanceProfile) Patch {
+	res := detector.DetectIAMInstanceProfileDrift(plan, actual)
+	p := Patch{Address: plan.TerraformAddress, ResourceType: "aws_iam_instance_profile", ResourceName: plan.InstanceProfileName}
+	if res.PathDiff {
+		p.set("path", actual.Path)
+	}
+	if res.RolesDiff && len(actual.Roles) == 1 {
+		p.set("role", actual.Roles[0])
+	}
+	addTags(&p, res.TagDiffs, res.ExtraTags, actual.Tags)
+	return p
+}
+
+// set appends a change.
+func (p *Patch) set(attr string, value interface{}) {
+	p.Changes = append(p.Changes, Change{Attribute: attr, Value: value})
+}
+
+// addTags sets the full live tag map if any tag differs. AWS-reserved
+// "aws:" tags are left out, since Terraform cannot manage them.
+func addTags(p *Patch, diffs map[string][2]string, extras map[string]string, live map[string]string) {
+	if len(diffs) == 0 && len(extras) == 0 {
+		return
+	}
+	tags := make(map[string]string, len(live))
+	for k, v := range live {
+		if !strings.HasPrefix(k, "aws:") {
+			tags[k] = v
+		}
+	}
+	p.set("tags", tags)
+}
+
+// matchInstance finds the live instance for a planned one: by instance ID
+// first, then by Name tag, as the EC2 detector does.
+func matchInstance(plan models.EC2Instance, live []models.EC2Instance) *models.EC2Instance {
+	if plan.InstanceID != "" {
+		for i := range live {
+			if live[i].InstanceID == plan.InstanceID {
+				return &live[i]
+			}
+		}
+	}
+	if name, ok := plan.Tags["Name"]; ok {
+		for i := range live {
+			if live[i].Tags["Name"] == name {
+				return &live[i]
+			}
+		}
+	}
+	return nil
+}
+
+// findByName returns the element of list whose name is name, or nil.
+func findByName[T any](list []T, name string, nameOf func(T) string) *T {
+	for i := range list {
+		if nameOf(list[i]) == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// sortedCopy returns a sorted copy of a string slice.
+func sortedCopy(list []string) []string {
+	out := append([]string(nil), list...)
+	sort.Strings(out)
+	return out
+}
+
+// HCL renders the patches as HCL: one partial resource block per address,
+// containing only the arguments to change.
+//
+// Example output:
+//
+//	# aws_instance.web
+//	resource "aws_instance" "web" {
+//	  instance_type = "t3.large"
+//	}
+func HCL(patches []Patch) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for i, p := range patches {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte("# " + p.Address + "\n")},
+		})
+		_, name, _ := splitAddress(p.Address)
+		block := body.AppendNewBlock("resource", []string{p.ResourceType, name}).Body()
+		for _, c := range p.Changes {
+			block.SetAttributeValue(c.Attribute, ctyValue(c.Value))
+		}
+	}
+	return hclwrite.Format(f.Bytes())
+}
+
+// ctyValue converts a change value into a cty value.
+func ctyValue(v interface{}) cty.Value {
+	switch x := v.(type) {
+	case string:
+		return cty.StringVal(x)
+	case int:
+		return cty.NumberIntVal(int64(x))
+	case int32:
+		return cty.NumberIntVal(int64(x))
+	case int64:
+		return cty.NumberIntVal(x)
+	case bool:
+		return cty.BoolVal(x)
+	case []string:
+		if len(x) == 0 {
+			return cty.ListValEmpty(cty.String)
+		}
+		vals := make([]cty.Value, len(x))
+		for i, s := range x {
+			vals[i] = cty.StringVal(s)
+		}
+		return cty.ListVal(vals)
+	case map[string]string:
+		if len(x) == 0 {
+			return cty.MapValEmpty(cty.String)
+		}
+		vals := make(map[string]cty.Value, len(x))
+		for k, s := range x {
+			vals[k] = cty.StringVal(s)
+		}
+		return cty.MapVal(vals)
+	default:
+		return cty.StringVal(fmt.Sprint(v))
+	}
+}
+
+// splitAddress splits a resource address into its type and name, ignoring
+// any module path and instance key. indexed is true if the address has an
+// instance key (count or for_each) or a module path, which means the
+// resource block is shared with other instances.
+func splitAddress(addr string) (resourceType, name string, indexed bool) {
+	indexed = strings.HasPrefix(addr, "module.")
+	if i := strings.Index(addr, "["); i >= 0 {
+		addr = addr[:i]
+		indexed = true
+	}
+	parts := strings.Split(addr, ".")
+	if len(parts) < 2 {
+		return "", addr, indexed
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], indexed
+}
@@ -0,0 +1,158 @@
+// Package snapshot records live AWS state to a file and replays it later.
+//
+// A snapshot captures the output of each detector's FetchLiveState call so
+// that drift detection and policy evaluation can be re-run without AWS
+// credentials. This is useful for reproducing customer issues, scanning in
+// air-gapped CI environments, and writing deterministic tests against
+// realistic data.
+//
+// Snapshots are versioned JSON documents:
+//
+//	{
+//	  "version": 1,
+//	  "created_at": "2024-01-15T10:30:00Z",
+//	  "account_id": "123456789012",
+//	  "region": "us-east-1",
+//	  "services": { "s3": {"buckets": [...]}, "ec2": {"instances": [...]}, "iam": {...} }
+//	}
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+// CurrentVersion is the snapshot format version written by this build.
+// Bump it whenever a live-state model gains, loses or changes a field that
+// drift detection compares: only snapshots of the current version are
+// replayed, because an older snapshot lacks the fields added since it was
+// recorded and replaying it would report every such field as drifted.
+const CurrentVersion = 1
+
+// Snapshot holds the recorded live state for one or more services.
+type Snapshot struct {
+	// Version is the snapshot format version.
+	Version int `json:"version"`
+
+	// CreatedAt is when the snapshot was recorded (RFC 3339).
+	CreatedAt string `json:"created_at"`
+
+	// AccountID is the AWS account the state was fetched from.
+	AccountID string `json:"account_id,omitempty"`
+
+	// Region is the AWS region the state was fetched from.
+	Region string `json:"region,omitempty"`
+
+	// Services maps a service name (e.g., "s3") to its raw live state.
+	Services map[string]json.RawMessage `json:"services"`
+}
+
+// New creates an empty snapshot for the given account and region.
+func New(accountID, region string) *Snapshot {
+	return &Snapshot{
+		Version:   CurrentVersion,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		AccountID: accountID,
+		Region:    region,
+		Services:  make(map[string]json.RawMessage),
+	}
+}
+
+// Add records the live state for a service, replacing any previous entry.
+//
+// The live value must be the exact type returned by the service's
+// FetchLiveState (e.g., *models.S3LiveState for "s3").
+func (s *Snapshot) Add(service string, live interface{}) error {
+	data, err := json.Marshal(live)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s live state: %w", service, err)
+	}
+	s.Services[service] = data
+	return nil
+}
+
+// ServiceNames returns the recorded service names in sorted order.
+func (s *Snapshot) ServiceNames() []string {
+	names := make([]string, 0, len(s.Services))
+	for name := range s.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LiveState decodes the recorded live state for a service.
+//
+// The returned value has the same concrete type as the corresponding
+// detector's FetchLiveState output, so it can be passed directly to DetectDrift.
+//
+// Returns an error if the service was not recorded or is not supported.
+func (s *Snapshot) LiveState(service string) (interface{}, error) {
+	raw, ok := s.Services[service]
+	if !ok {
+		return nil, fmt.Errorf("snapshot has no live state for service %q (recorded: %v)", service, s.ServiceNames())
+	}
+
+	switch service {
+	case "s3":
+		var state models.S3LiveState
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return nil, fmt.Errorf("failed to decode s3 live state: %w", err)
+		}
+		return &state, nil
+	case "ec2":
+		var state models.EC2LiveState
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return nil, fmt.Errorf("failed to decode ec2 live state: %w", err)
+		}
+		return &state, nil
+	case "iam":
+		var state models.IAMLiveState
+		if err := json.Unmarshal(raw, &state); err != nil {
+			return nil, fmt.Errorf("failed to decode iam live state: %w", err)
+		}
+		return &state, nil
+	default:
+		return nil, fmt.Errorf("unsupported service in snapshot: %s", service)
+	}
+}
+
+// Save writes the snapshot to the given path as indented JSON.
+func Save(path string, s *Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot from the given path.
+//
+// Returns an error if the file cannot be read, is not valid JSON, or was
+// written with a different snapshot version than CurrentVersion.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if s.Version != CurrentVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (current version: %d)", s.Version, CurrentVersion)
+	}
+	if s.Services == nil {
+		s.Services = make(map[string]json.RawMessage)
+	}
+	return &s, nil
+}
@@ -0,0 +1,179 @@
+// Package tfconfig maps Terraform resource addresses to the place in the
+// configuration where the resource is declared.
+//
+// The plan JSON identifies resources by address but carries no source
+// positions, so the .tf files are parsed directly. Load indexes every
+// resource and data block in a root module directory and, recursively, in
+// local child modules ("./" or "../" sources). Modules from registries or
+// remote sources are not followed.
+package tfconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Location is the source range of a resource block.
+type Location struct {
+	// File is the path of the .tf file, joined to the directory given to
+	// Load and using forward slashes (e.g., "infra/main.tf").
+	File string `json:"file"`
+
+	// StartLine is the line of the block header (1-based).
+	StartLine int `json:"start_line"`
+
+	// StartColumn is the column of the block header (1-based).
+	StartColumn int `json:"start_column"`
+
+	// EndLine is the line of the block's closing brace.
+	EndLine int `json:"end_line"`
+}
+
+// String returns the location as "file:line".
+func (l Location) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.StartLine)
+}
+
+// Index maps resource addresses without instance keys (e.g.,
+// "module.app.aws_instance.web") to their declaring block.
+type Index map[string]Location
+
+// Load parses the .tf files of a root module directory and its local child
+// modules.
+//
+// Parameters:
+//   - dir: the root module directory
+//
+// Returns:
+//   - Index: the location of every resource and data block
+//   - error: if a .tf file cannot be read or parsed, or a local module loops
+func Load(dir string) (Index, error) {
+	idx := make(Index)
+	if err := idx.load(hclparse.NewParser(), dir, "", map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// load indexes one module directory. prefix is the module path of the
+// directory (e.g., "module.app."); visiting guards against source cycles.
+func (idx Index) load(parser *hclparse.Parser, dir, prefix string, visiting map[string]bool) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if visiting[abs] {
+		return fmt.Errorf("module source cycle at %s", dir)
+	}
+	visiting[abs] = true
+	defer delete(visiting, abs)
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		f, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			return fmt.Errorf("failed to parse %s: %s", path, diags.Error())
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		file := filepath.ToSlash(filepath.Clean(path))
+		for _, block := range body.Blocks {
+			switch {
+			case block.Type == "resource" && len(block.Labels) == 2:
+				idx[prefix+block.Labels[0]+"."+block.Labels[1]] = blockLocation(file, block)
+			case block.Type == "data" && len(block.Labels) == 2:
+				idx[prefix+"data."+block.Labels[0]+"."+block.Labels[1]] = blockLocation(file, block)
+			case block.Type == "module" && len(block.Labels) == 1:
+				source := localSource(block)
+				if source == "" {
+					continue
+				}
+				child := prefix + "module." + block.Labels[0] + "."
+				if err := idx.load(parser, filepath.Join(dir, source), child, visiting); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the location of the block declaring a resource. Instance
+// keys are ignored, so "aws_instance.web[0]" and `module.app["a"].aws_instance.web`
+// resolve to the block shared by all instances.
+func (idx Index) Lookup(address string) (Location, bool) {
+	if idx == nil || address == "" {
+		return Location{}, false
+	}
+	loc, ok := idx[StripInstanceKeys(address)]
+	return loc, ok
+}
+
+// StripInstanceKeys removes count and for_each keys from a resource address.
+func StripInstanceKeys(address string) string {
+	var b strings.Builder
+	depth := 0
+	quoted := false
+	for i := 0; i < len(address); i++ {
+		c := address[i]
+		switch {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"' && depth > 0:
+			quoted = true
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// blockLocation returns the location of a block's header and body.
+func blockLocation(file string, block *hclsyntax.Block) Location {
+	header := block.DefRange()
+	return Location{
+		File:        file,
+		StartLine:   header.Start.Line,
+		StartColumn: header.Start.Column,
+		EndLine:     block.Range().End.Line,
+	}
+}
+
+// localSource returns the source of a module block if it is a literal local
+// path, or "" otherwise.
+func localSource(block *hclsyntax.Block) string {
+	attr, ok := block.Body.Attributes["source"]
+	if !ok {
+		return ""
+	}
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || v.Type() != cty.String || v.IsNull() {
+		return ""
+	}
+	source := v.AsString()
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return source
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/snapshot"
+)
+
+// cloudriftBin is the CLI binary built once for the tests in this package.
+var cloudriftBin string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "cloudrift-cmd-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cloudriftBin = filepath.Join(dir, "cloudrift")
+	build := exec.Command("go", "build", "-o", cloudriftBin, ".")
+	build.Dir = filepath.Join("..", "..")
+	if out, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build cloudrift: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// writeScanConfig writes a config file pointing at the example S3 plan.
+func writeScanConfig(t *testing.T, dir string) string {
+	t.Helper()
+	planPath, err := filepath.Abs(filepath.Join("..", "..", "examples", "plan.json"))
+	require.NoError(t, err)
+	configPath := filepath.Join(dir, "cloudrift.yml")
+	config := fmt.Sprintf("aws_profile: \"\"\nregion: us-east-1\nplan_path: %s\n", planPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+	return configPath
+}
+
+// runScan runs the CLI and returns its stdout. A non-zero exit code is not
+// an error: a scan that finds drift or fails to reach AWS exits non-zero.
+func runScan(t *testing.T, env []string, args ...string) []byte {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(cloudriftBin, args...)
+	c.Dir = t.TempDir()
+	c.Env = append(os.Environ(), env...)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("failed to run cloudrift: %v", err)
+	}
+	return stdout.Bytes()
+}
+
+// assertJSONLines asserts that every line of out is a JSON object.
+func assertJSONLines(t *testing.T, out []byte) int {
+	t.Helper()
+	lines := 0
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		lines++
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(sc.Bytes(), &record), "stdout line %d is not JSON: %q", lines, sc.Text())
+	}
+	require.NoError(t, sc.Err())
+	return lines
+}
+
+func TestScan_NDJSONStdoutIsJSONOnly(t *testing.T) {
+	dir := t.TempDir()
+	snap := snapshot.New("123456789012", "us-east-1")
+	require.NoError(t, snap.Add("s3", &models.S3LiveState{
+		Buckets: []models.S3Bucket{{
+			Name:                "cloudrift",
+			Acl:                 "private",
+			Tags:                map[string]string{"env": "prod"},
+			VersioningEnabled:   true,
+			EncryptionAlgorithm: "AES256",
+		}},
+	}))
+	snapPath := filepath.Join(dir, "s3-live.json")
+	require.NoError(t, snapshot.Save(snapPath, snap))
+
+	out := runScan(t, nil,
+		"scan", "--service=s3", "--format=ndjson",
+		"--config="+writeScanConfig(t, dir), "--live-snapshot="+snapPath)
+
+	lines := assertJSONLines(t, out)
+	assert.Greater(t, lines, 1, "expected drift records and a summary on stdout")
+}
+
+func TestScan_NDJSONStdoutIsJSONOnlyWhenAWSIsUnreachable(t *testing.T) {
+	dir := t.TempDir()
+	env := []string{
+		"AWS_ACCESS_KEY_ID=test",
+		"AWS_SECRET_ACCESS_KEY=test",
+		"AWS_ENDPOINT_URL=http://127.0.0.1:1",
+		"AWS_MAX_ATTEMPTS=1",
+		"AWS_EC2_METADATA_DISABLED=true",
+		"AWS_CONFIG_FILE=" + filepath.Join(dir, "aws-config"),
+		"AWS_SHARED_CREDENTIALS_FILE=" + filepath.Join(dir, "aws-credentials"),
+	}
+
+	out := runScan(t, env,
+		"scan", "--service=s3", "--format=ndjson", "--timeout=10s",
+		"--config="+writeScanConfig(t, dir))
+
+	assertJSONLines(t, out)
+}
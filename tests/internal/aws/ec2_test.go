@@ -0,0 +1,213 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/aws"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+func ec2Instance(id string, state ec2types.InstanceStateName) ec2types.Instance {
+	return ec2types.Instance{
+		InstanceId:     sdkaws.String(id),
+		InstanceType:   ec2types.InstanceTypeT3Micro,
+		ImageId:        sdkaws.String("ami-123"),
+		State:          &ec2types.InstanceState{Name: state},
+		RootDeviceName: sdkaws.String("/dev/xvda"),
+		BlockDeviceMappings: []ec2types.InstanceBlockDeviceMapping{{
+			DeviceName: sdkaws.String("/dev/xvda"),
+			Ebs: &ec2types.EbsInstanceBlockDevice{
+				VolumeId:            sdkaws.String("vol-" + id),
+				DeleteOnTermination: sdkaws.Bool(true),
+			},
+		}},
+		Tags: []ec2types.Tag{{Key: sdkaws.String("Name"), Value: sdkaws.String(id + "-name")}},
+	}
+}
+
+func TestFetchEC2Instances_Pagination(t *testing.T) {
+	client := &fakeEC2{pages: []*ec2.DescribeInstancesOutput{
+		{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+			ec2Instance("i-1", ec2types.InstanceStateNameRunning),
+		}}}},
+		{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+			ec2Instance("i-2", ec2types.InstanceStateNameStopped),
+		}}}},
+	}}
+
+	state, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	instances := state.Instances
+	assert.Equal(t, 2, client.calls)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "i-1", instances[0].InstanceID)
+	assert.Equal(t, "i-2", instances[1].InstanceID)
+	assert.Equal(t, "stopped", instances[1].State)
+	assert.Equal(t, "i-1-name", instances[0].Name())
+	assert.True(t, instances[0].RootBlockDevice.DeleteOnTermination)
+}
+
+func TestFetchEC2Instances_SkipsTerminated(t *testing.T) {
+	client := &fakeEC2{pages: []*ec2.DescribeInstancesOutput{
+		{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+			ec2Instance("i-live", ec2types.InstanceStateNameRunning),
+			ec2Instance("i-dead", ec2types.InstanceStateNameTerminated),
+		}}}},
+	}}
+
+	state, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	instances := state.Instances
+	require.Len(t, instances, 1)
+	assert.Equal(t, "i-live", instances[0].InstanceID)
+}
+
+func TestFetchEC2Instances_Error(t *testing.T) {
+	client := &fakeEC2{err: apiError("UnauthorizedOperation")}
+
+	_, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DescribeInstances")
+}
+
+func TestFetchEC2Instances_VolumeAndAttributeDetails(t *testing.T) {
+	inst := ec2Instance("i-1", ec2types.InstanceStateNameRunning)
+	inst.BlockDeviceMappings = append(inst.BlockDeviceMappings, ec2types.InstanceBlockDeviceMapping{
+		DeviceName: sdkaws.String("/dev/sdf"),
+		Ebs:        &ec2types.EbsInstanceBlockDevice{VolumeId: sdkaws.String("vol-data")},
+	})
+	inst.MetadataOptions = &ec2types.InstanceMetadataOptionsResponse{
+		HttpTokens:              ec2types.HttpTokensStateRequired,
+		HttpEndpoint:            ec2types.InstanceMetadataEndpointStateEnabled,
+		HttpPutResponseHopLimit: sdkaws.Int32(2),
+	}
+	inst.NetworkInterfaces = []ec2types.InstanceNetworkInterface{{
+		NetworkInterfaceId: sdkaws.String("eni-1"),
+		SubnetId:           sdkaws.String("subnet-1"),
+		Groups:             []ec2types.GroupIdentifier{{GroupId: sdkaws.String("sg-1")}},
+		Attachment: &ec2types.InstanceNetworkInterfaceAttachment{
+			DeviceIndex:         sdkaws.Int32(0),
+			DeleteOnTermination: sdkaws.Bool(true),
+		},
+	}}
+
+	client := &fakeEC2{
+		pages: []*ec2.DescribeInstancesOutput{
+			{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{inst}}}},
+		},
+		volumes: map[string]ec2types.Volume{
+			"vol-i-1": {
+				VolumeId:   sdkaws.String("vol-i-1"),
+				VolumeType: ec2types.VolumeTypeGp3,
+				Size:       sdkaws.Int32(20),
+				Encrypted:  sdkaws.Bool(true),
+				Iops:       sdkaws.Int32(3000),
+				Throughput: sdkaws.Int32(125),
+			},
+			"vol-data": {
+				VolumeId:   sdkaws.String("vol-data"),
+				VolumeType: ec2types.VolumeTypeIo2,
+				Size:       sdkaws.Int32(100),
+				Iops:       sdkaws.Int32(5000),
+			},
+		},
+		userData:    map[string]string{"i-1": base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\necho hi\n"))},
+		termination: map[string]bool{"i-1": true},
+	}
+
+	state, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Errors)
+	require.Len(t, state.Instances, 1)
+	got := state.Instances[0]
+
+	assert.Equal(t, models.BlockDevice{
+		DeviceName:          "/dev/xvda",
+		VolumeID:            "vol-i-1",
+		VolumeType:          "gp3",
+		VolumeSize:          20,
+		DeleteOnTermination: true,
+		Encrypted:           sdkaws.Bool(true),
+		IOPS:                3000,
+		Throughput:          125,
+	}, got.RootBlockDevice)
+	require.Len(t, got.EBSBlockDevices, 1)
+	assert.Equal(t, "/dev/sdf", got.EBSBlockDevices[0].DeviceName)
+	assert.Equal(t, "io2", got.EBSBlockDevices[0].VolumeType)
+	assert.Equal(t, 5000, got.EBSBlockDevices[0].IOPS)
+
+	assert.Equal(t, "required", got.MetadataOptions.HTTPTokens)
+	assert.Equal(t, 2, got.MetadataOptions.HTTPPutResponseHopLimit)
+	require.Len(t, got.NetworkInterfaces, 1)
+	assert.Equal(t, "eni-1", got.NetworkInterfaces[0].NetworkInterfaceID)
+	assert.Equal(t, []string{"sg-1"}, got.NetworkInterfaces[0].SecurityGroupIDs)
+	assert.True(t, got.NetworkInterfaces[0].DeleteOnTermination)
+
+	assert.Equal(t, models.HashUserData([]byte("#!/bin/bash\necho hi\n")), got.UserDataHash)
+	assert.True(t, got.DisableAPITermination)
+	assert.False(t, got.DisableAPIStop)
+}
+
+func TestFetchEC2Instances_DeletedVolumeOnlyFailsItsInstance(t *testing.T) {
+	client := &fakeEC2{
+		pages: []*ec2.DescribeInstancesOutput{
+			{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+				ec2Instance("i-1", ec2types.InstanceStateNameRunning),
+				ec2Instance("i-2", ec2types.InstanceStateNameRunning),
+			}}}},
+		},
+		volumes: map[string]ec2types.Volume{
+			"vol-i-1": {VolumeId: sdkaws.String("vol-i-1"), VolumeType: ec2types.VolumeTypeGp3},
+		},
+		opErrs: map[string]error{
+			"DescribeVolumes/vol-i-2": apiError("InvalidVolume.NotFound"),
+		},
+	}
+
+	state, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Instances, 2)
+	assert.Equal(t, "gp3", state.Instances[0].RootBlockDevice.VolumeType)
+
+	require.Len(t, state.Errors, 1)
+	assert.Equal(t, "i-2", state.Errors[0].Resource)
+	assert.Equal(t, "DescribeVolumes", state.Errors[0].Operation)
+	assert.Equal(t, "InvalidVolume.NotFound", state.Errors[0].ErrorCode)
+}
+
+func TestFetchEC2Instances_DetailFailuresRecorded(t *testing.T) {
+	client := &fakeEC2{
+		pages: []*ec2.DescribeInstancesOutput{
+			{Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+				ec2Instance("i-1", ec2types.InstanceStateNameRunning),
+				ec2Instance("i-2", ec2types.InstanceStateNameRunning),
+			}}}},
+		},
+		opErrs: map[string]error{
+			"DescribeVolumes":               apiError("UnauthorizedOperation"),
+			"DescribeInstanceAttribute/i-2": apiError("UnauthorizedOperation"),
+		},
+	}
+
+	state, err := aws.FetchEC2InstancesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Instances, 2, "instances are kept so they are not reported missing")
+
+	ops := map[string][]string{}
+	for _, e := range state.Errors {
+		assert.Equal(t, "aws_instance", e.ResourceType)
+		assert.Equal(t, "UnauthorizedOperation", e.ErrorCode)
+		ops[e.Operation] = append(ops[e.Operation], e.Resource)
+	}
+	// Errors are recorded by instance ID, which unlike the Name tag is unique
+	assert.ElementsMatch(t, []string{"i-1", "i-2"}, ops["DescribeVolumes"])
+	assert.Equal(t, []string{"i-2"}, ops["DescribeInstanceAttribute"])
+}
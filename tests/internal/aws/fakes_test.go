@@ -0,0 +1,630 @@
+package aws
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// apiError builds a smithy API error with the given code, as returned by the AWS SDK.
+func apiError(code string) error {
+	return &smithy.GenericAPIError{Code: code, Message: code}
+}
+
+// fakeS3 is an in-memory S3API. Responses and errors are keyed by bucket name;
+// errors are keyed by "<Operation>/<bucket>". With a pageSize, ListBuckets
+// serves the buckets in pages whose ContinuationToken is the index of the
+// next bucket.
+type fakeS3 struct {
+	buckets    []string
+	pageSize   int
+	listCalls  int
+	listErr    error
+	acl        map[string]*s3.GetBucketAclOutput
+	tagging    map[string]*s3.GetBucketTaggingOutput
+	versioning map[string]*s3.GetBucketVersioningOutput
+	encryption map[string]*s3.GetBucketEncryptionOutput
+	logging    map[string]*s3.GetBucketLoggingOutput
+	pab        map[string]*s3.GetPublicAccessBlockOutput
+	lifecycle  map[string]*s3.GetBucketLifecycleConfigurationOutput
+	ownership  map[string]*s3.GetBucketOwnershipControlsOutput
+	policy     map[string]string
+	cors       map[string]*s3.GetBucketCorsOutput
+	repl       map[string]*s3.GetBucketReplicationOutput
+	objectLock map[string]*s3.GetObjectLockConfigurationOutput
+	errs       map[string]error
+
+	// afterList, if set, is called once ListBuckets has returned (e.g., to cancel the scan).
+	afterList func()
+}
+
+func (f *fakeS3) err(ctx context.Context, op string, bucket *string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if f.errs == nil || bucket == nil {
+		return nil
+	}
+	return f.errs[op+"/"+*bucket]
+}
+
+func (f *fakeS3) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	f.listCalls++
+	start, end := 0, len(f.buckets)
+	if params.ContinuationToken != nil {
+		start, _ = strconv.Atoi(*params.ContinuationToken)
+	}
+	out := &s3.ListBucketsOutput{}
+	if f.pageSize > 0 && start+f.pageSize < end {
+		end = start + f.pageSize
+		next := strconv.Itoa(end)
+		out.ContinuationToken = &next
+	}
+	for _, name := range f.buckets[start:end] {
+		out.Buckets = append(out.Buckets, s3Bucket(name))
+	}
+	if f.afterList != nil && out.ContinuationToken == nil {
+		f.afterList()
+	}
+	return out, nil
+}
+
+func (f *fakeS3) GetBucketAcl(ctx context.Context, params *s3.GetBucketAclInput, optFns ...func(*s3.Options)) (*s3.GetBucketAclOutput, error) {
+	if err := f.err(ctx, "GetBucketAcl", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.acl[*params.Bucket]; ok {
+		return out, nil
+	}
+	return &s3.GetBucketAclOutput{}, nil
+}
+
+func (f *fakeS3) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if err := f.err(ctx, "GetBucketTagging", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.tagging[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("NoSuchTagSet")
+}
+
+func (f *fakeS3) GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	if err := f.err(ctx, "GetBucketVersioning", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.versioning[*params.Bucket]; ok {
+		return out, nil
+	}
+	return &s3.GetBucketVersioningOutput{}, nil
+}
+
+func (f *fakeS3) GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	if err := f.err(ctx, "GetBucketEncryption", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.encryption[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("ServerSideEncryptionConfigurationNotFoundError")
+}
+
+func (f *fakeS3) GetBucketLogging(ctx context.Context, params *s3.GetBucketLoggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketLoggingOutput, error) {
+	if err := f.err(ctx, "GetBucketLogging", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.logging[*params.Bucket]; ok {
+		return out, nil
+	}
+	return &s3.GetBucketLoggingOutput{}, nil
+}
+
+func (f *fakeS3) GetPublicAccessBlock(ctx context.Context, params *s3.GetPublicAccessBlockInput, optFns ...func(*s3.Options)) (*s3.GetPublicAccessBlockOutput, error) {
+	if err := f.err(ctx, "GetPublicAccessBlock", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.pab[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("NoSuchPublicAccessBlockConfiguration")
+}
+
+func (f *fakeS3) GetBucketLifecycleConfiguration(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	if err := f.err(ctx, "GetBucketLifecycleConfiguration", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.lifecycle[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("NoSuchLifecycleConfiguration")
+}
+
+func (f *fakeS3) GetBucketOwnershipControls(ctx context.Context, params *s3.GetBucketOwnershipControlsInput, optFns ...func(*s3.Options)) (*s3.GetBucketOwnershipControlsOutput, error) {
+	if err := f.err(ctx, "GetBucketOwnershipControls", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.ownership[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("OwnershipControlsNotFoundError")
+}
+
+func (f *fakeS3) GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	if err := f.err(ctx, "GetBucketPolicy", params.Bucket); err != nil {
+		return nil, err
+	}
+	if doc, ok := f.policy[*params.Bucket]; ok {
+		return &s3.GetBucketPolicyOutput{Policy: &doc}, nil
+	}
+	return nil, apiError("NoSuchBucketPolicy")
+}
+
+func (f *fakeS3) GetBucketCors(ctx context.Context, params *s3.GetBucketCorsInput, optFns ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+	if err := f.err(ctx, "GetBucketCors", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.cors[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("NoSuchCORSConfiguration")
+}
+
+func (f *fakeS3) GetBucketReplication(ctx context.Context, params *s3.GetBucketReplicationInput, optFns ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+	if err := f.err(ctx, "GetBucketReplication", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.repl[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("ReplicationConfigurationNotFoundError")
+}
+
+func (f *fakeS3) GetObjectLockConfiguration(ctx context.Context, params *s3.GetObjectLockConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+	if err := f.err(ctx, "GetObjectLockConfiguration", params.Bucket); err != nil {
+		return nil, err
+	}
+	if out, ok := f.objectLock[*params.Bucket]; ok {
+		return out, nil
+	}
+	return nil, apiError("ObjectLockConfigurationNotFoundError")
+}
+
+// fakeEC2 is an in-memory EC2API that serves DescribeInstances in pages.
+// The NextToken is the index of the next page. Volumes and instance attributes
+// are keyed by volume and instance ID; opErrs is keyed by "<Operation>" or
+// "<Operation>/<id>".
+type fakeEC2 struct {
+	pages       []*ec2.DescribeInstancesOutput
+	err         error
+	calls       int
+	volumes     map[string]ec2types.Volume
+	userData    map[string]string
+	termination map[string]bool
+	stop        map[string]bool
+	opErrs      map[string]error
+}
+
+func (f *fakeEC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	idx := 0
+	if params.NextToken != nil {
+		idx, _ = strconv.Atoi(*params.NextToken)
+	}
+	page := *f.pages[idx]
+	if idx+1 < len(f.pages) {
+		next := strconv.Itoa(idx + 1)
+		page.NextToken = &next
+	}
+	return &page, nil
+}
+
+func (f *fakeEC2) opErr(op, id string) error {
+	if err, ok := f.opErrs[op+"/"+id]; ok {
+		return err
+	}
+	return f.opErrs[op]
+}
+
+func (f *fakeEC2) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	out := &ec2.DescribeVolumesOutput{}
+	for _, id := range params.VolumeIds {
+		if err := f.opErr("DescribeVolumes", id); err != nil {
+			return nil, err
+		}
+		if vol, ok := f.volumes[id]; ok {
+			out.Volumes = append(out.Volumes, vol)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEC2) DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error) {
+	id := *params.InstanceId
+	if err := f.opErr("DescribeInstanceAttribute", id); err != nil {
+		return nil, err
+	}
+	out := &ec2.DescribeInstanceAttributeOutput{InstanceId: params.InstanceId}
+	switch params.Attribute {
+	case ec2types.InstanceAttributeNameUserData:
+		if v, ok := f.userData[id]; ok {
+			out.UserData = &ec2types.AttributeValue{Value: &v}
+		}
+	case ec2types.InstanceAttributeNameDisableApiTermination:
+		out.DisableApiTermination = &ec2types.AttributeBooleanValue{Value: sdkaws.Bool(f.termination[id])}
+	case ec2types.InstanceAttributeNameDisableApiStop:
+		out.DisableApiStop = &ec2types.AttributeBooleanValue{Value: sdkaws.Bool(f.stop[id])}
+	}
+	return out, nil
+}
+
+// fakeIAM is an in-memory IAMAPI. ListRoles is served in pages (Marker is the
+// page index); per-entity responses are keyed by name or ARN. Errors are keyed
+// by "<Operation>" or "<Operation>/<name>".
+type fakeIAM struct {
+	rolePages        [][]iamRole
+	users            []iamUser
+	policies         []iamPolicy
+	groups           []iamGroup
+	attachedRole     map[string][]string
+	attachedUser     map[string][]string
+	attachedGroup    map[string][]string
+	userTags         map[string]map[string]string
+	policyTags       map[string]map[string]string
+	policyDocuments  map[string]string
+	groupMembers     map[string][]string
+	roleInline       map[string]map[string]string
+	userInline       map[string]map[string]string
+	groupInline      map[string]map[string]string
+	instanceProfiles []iamInstanceProfile
+	profileTags      map[string]map[string]string
+	errs             map[string]error
+	listRolesCallCnt int
+}
+
+func (f *fakeIAM) err(ctx context.Context, op, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if f.errs == nil {
+		return nil
+	}
+	if err := f.errs[op]; err != nil {
+		return err
+	}
+	return f.errs[op+"/"+name]
+}
+
+func (f *fakeIAM) ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+	f.listRolesCallCnt++
+	if err := f.err(ctx, "ListRoles", ""); err != nil {
+		return nil, err
+	}
+	out := &iam.ListRolesOutput{}
+	if len(f.rolePages) == 0 {
+		return out, nil
+	}
+	idx := 0
+	if params.Marker != nil {
+		idx, _ = strconv.Atoi(*params.Marker)
+	}
+	for _, r := range f.rolePages[idx] {
+		out.Roles = append(out.Roles, r.sdk())
+	}
+	if idx+1 < len(f.rolePages) {
+		next := strconv.Itoa(idx + 1)
+		out.Marker = &next
+		out.IsTruncated = true
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	if err := f.err(ctx, "ListAttachedRolePolicies", *params.RoleName); err != nil {
+		return nil, err
+	}
+	return &iam.ListAttachedRolePoliciesOutput{AttachedPolicies: attachedPolicies(f.attachedRole[*params.RoleName])}, nil
+}
+
+func (f *fakeIAM) ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error) {
+	if err := f.err(ctx, "ListUsers", ""); err != nil {
+		return nil, err
+	}
+	out := &iam.ListUsersOutput{}
+	for _, u := range f.users {
+		out.Users = append(out.Users, u.sdk())
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) ListUserTags(ctx context.Context, params *iam.ListUserTagsInput, optFns ...func(*iam.Options)) (*iam.ListUserTagsOutput, error) {
+	if err := f.err(ctx, "ListUserTags", *params.UserName); err != nil {
+		return nil, err
+	}
+	return &iam.ListUserTagsOutput{Tags: iamTags(f.userTags[*params.UserName])}, nil
+}
+
+func (f *fakeIAM) ListAttachedUserPolicies(ctx context.Context, params *iam.ListAttachedUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+	if err := f.err(ctx, "ListAttachedUserPolicies", *params.UserName); err != nil {
+		return nil, err
+	}
+	return &iam.ListAttachedUserPoliciesOutput{AttachedPolicies: attachedPolicies(f.attachedUser[*params.UserName])}, nil
+}
+
+func (f *fakeIAM) ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	if err := f.err(ctx, "ListPolicies", ""); err != nil {
+		return nil, err
+	}
+	out := &iam.ListPoliciesOutput{}
+	for _, p := range f.policies {
+		out.Policies = append(out.Policies, p.sdk())
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) GetPolicyVersion(ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error) {
+	if err := f.err(ctx, "GetPolicyVersion", *params.PolicyArn); err != nil {
+		return nil, err
+	}
+	doc := f.policyDocuments[*params.PolicyArn]
+	return &iam.GetPolicyVersionOutput{PolicyVersion: policyVersion(doc)}, nil
+}
+
+func (f *fakeIAM) ListPolicyTags(ctx context.Context, params *iam.ListPolicyTagsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyTagsOutput, error) {
+	if err := f.err(ctx, "ListPolicyTags", *params.PolicyArn); err != nil {
+		return nil, err
+	}
+	return &iam.ListPolicyTagsOutput{Tags: iamTags(f.policyTags[*params.PolicyArn])}, nil
+}
+
+func (f *fakeIAM) ListGroups(ctx context.Context, params *iam.ListGroupsInput, optFns ...func(*iam.Options)) (*iam.ListGroupsOutput, error) {
+	if err := f.err(ctx, "ListGroups", ""); err != nil {
+		return nil, err
+	}
+	out := &iam.ListGroupsOutput{}
+	for _, g := range f.groups {
+		out.Groups = append(out.Groups, g.sdk())
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) ListAttachedGroupPolicies(ctx context.Context, params *iam.ListAttachedGroupPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedGroupPoliciesOutput, error) {
+	if err := f.err(ctx, "ListAttachedGroupPolicies", *params.GroupName); err != nil {
+		return nil, err
+	}
+	return &iam.ListAttachedGroupPoliciesOutput{AttachedPolicies: attachedPolicies(f.attachedGroup[*params.GroupName])}, nil
+}
+
+func (f *fakeIAM) GetGroup(ctx context.Context, params *iam.GetGroupInput, optFns ...func(*iam.Options)) (*iam.GetGroupOutput, error) {
+	if err := f.err(ctx, "GetGroup", *params.GroupName); err != nil {
+		return nil, err
+	}
+	out := &iam.GetGroupOutput{}
+	for _, name := range f.groupMembers[*params.GroupName] {
+		out.Users = append(out.Users, iamUser{name: name}.sdk())
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	if err := f.err(ctx, "GetRole", *params.RoleName); err != nil {
+		return nil, err
+	}
+	for _, page := range f.rolePages {
+		for _, r := range page {
+			if r.name == *params.RoleName {
+				role := r.sdk()
+				return &iam.GetRoleOutput{Role: &role}, nil
+			}
+		}
+	}
+	return nil, apiError("NoSuchEntity")
+}
+
+func (f *fakeIAM) GetUser(ctx context.Context, params *iam.GetUserInput, optFns ...func(*iam.Options)) (*iam.GetUserOutput, error) {
+	if err := f.err(ctx, "GetUser", *params.UserName); err != nil {
+		return nil, err
+	}
+	for _, u := range f.users {
+		if u.name == *params.UserName {
+			user := u.sdk()
+			return &iam.GetUserOutput{User: &user}, nil
+		}
+	}
+	return nil, apiError("NoSuchEntity")
+}
+
+func (f *fakeIAM) ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	if err := f.err(ctx, "ListRolePolicies", *params.RoleName); err != nil {
+		return nil, err
+	}
+	return &iam.ListRolePoliciesOutput{PolicyNames: sortedNames(f.roleInline[*params.RoleName])}, nil
+}
+
+func (f *fakeIAM) GetRolePolicy(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error) {
+	if err := f.err(ctx, "GetRolePolicy", *params.RoleName); err != nil {
+		return nil, err
+	}
+	doc := url.QueryEscape(f.roleInline[*params.RoleName][*params.PolicyName])
+	return &iam.GetRolePolicyOutput{PolicyDocument: &doc}, nil
+}
+
+func (f *fakeIAM) ListUserPolicies(ctx context.Context, params *iam.ListUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+	if err := f.err(ctx, "ListUserPolicies", *params.UserName); err != nil {
+		return nil, err
+	}
+	return &iam.ListUserPoliciesOutput{PolicyNames: sortedNames(f.userInline[*params.UserName])}, nil
+}
+
+func (f *fakeIAM) GetUserPolicy(ctx context.Context, params *iam.GetUserPolicyInput, optFns ...func(*iam.Options)) (*iam.GetUserPolicyOutput, error) {
+	if err := f.err(ctx, "GetUserPolicy", *params.UserName); err != nil {
+		return nil, err
+	}
+	doc := url.QueryEscape(f.userInline[*params.UserName][*params.PolicyName])
+	return &iam.GetUserPolicyOutput{PolicyDocument: &doc}, nil
+}
+
+func (f *fakeIAM) ListGroupPolicies(ctx context.Context, params *iam.ListGroupPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListGroupPoliciesOutput, error) {
+	if err := f.err(ctx, "ListGroupPolicies", *params.GroupName); err != nil {
+		return nil, err
+	}
+	return &iam.ListGroupPoliciesOutput{PolicyNames: sortedNames(f.groupInline[*params.GroupName])}, nil
+}
+
+func (f *fakeIAM) GetGroupPolicy(ctx context.Context, params *iam.GetGroupPolicyInput, optFns ...func(*iam.Options)) (*iam.GetGroupPolicyOutput, error) {
+	if err := f.err(ctx, "GetGroupPolicy", *params.GroupName); err != nil {
+		return nil, err
+	}
+	doc := url.QueryEscape(f.groupInline[*params.GroupName][*params.PolicyName])
+	return &iam.GetGroupPolicyOutput{PolicyDocument: &doc}, nil
+}
+
+func (f *fakeIAM) ListInstanceProfiles(ctx context.Context, params *iam.ListInstanceProfilesInput, optFns ...func(*iam.Options)) (*iam.ListInstanceProfilesOutput, error) {
+	if err := f.err(ctx, "ListInstanceProfiles", ""); err != nil {
+		return nil, err
+	}
+	out := &iam.ListInstanceProfilesOutput{}
+	for _, ip := range f.instanceProfiles {
+		out.InstanceProfiles = append(out.InstanceProfiles, ip.sdk())
+	}
+	return out, nil
+}
+
+func (f *fakeIAM) ListInstanceProfileTags(ctx context.Context, params *iam.ListInstanceProfileTagsInput, optFns ...func(*iam.Options)) (*iam.ListInstanceProfileTagsOutput, error) {
+	if err := f.err(ctx, "ListInstanceProfileTags", *params.InstanceProfileName); err != nil {
+		return nil, err
+	}
+	return &iam.ListInstanceProfileTagsOutput{Tags: iamTags(f.profileTags[*params.InstanceProfileName])}, nil
+}
+
+// Small builders for SDK types used by the fakes.
+
+func s3Bucket(name string) s3types.Bucket {
+	return s3types.Bucket{Name: sdkaws.String(name)}
+}
+
+type iamRole struct {
+	name, path, trust, boundary string
+}
+
+func (r iamRole) sdk() iamtypes.Role {
+	path := r.path
+	if path == "" {
+		path = "/"
+	}
+	return iamtypes.Role{
+		RoleName:                 sdkaws.String(r.name),
+		Arn:                      sdkaws.String("arn:aws:iam::123456789012:role" + path + r.name),
+		Path:                     sdkaws.String(path),
+		AssumeRolePolicyDocument: sdkaws.String(r.trust),
+		PermissionsBoundary:      permissionsBoundary(r.boundary),
+	}
+}
+
+type iamUser struct {
+	name, boundary string
+}
+
+func (u iamUser) sdk() iamtypes.User {
+	return iamtypes.User{
+		UserName:            sdkaws.String(u.name),
+		Arn:                 sdkaws.String("arn:aws:iam::123456789012:user/" + u.name),
+		Path:                sdkaws.String("/"),
+		PermissionsBoundary: permissionsBoundary(u.boundary),
+	}
+}
+
+type iamPolicy struct {
+	name, arn string
+}
+
+func (p iamPolicy) sdk() iamtypes.Policy {
+	return iamtypes.Policy{
+		PolicyName:       sdkaws.String(p.name),
+		Arn:              sdkaws.String(p.arn),
+		Path:             sdkaws.String("/"),
+		DefaultVersionId: sdkaws.String("v1"),
+	}
+}
+
+type iamGroup struct {
+	name string
+}
+
+func (g iamGroup) sdk() iamtypes.Group {
+	return iamtypes.Group{
+		GroupName: sdkaws.String(g.name),
+		Arn:       sdkaws.String("arn:aws:iam::123456789012:group/" + g.name),
+		Path:      sdkaws.String("/"),
+	}
+}
+
+type iamInstanceProfile struct {
+	name  string
+	roles []string
+}
+
+func (p iamInstanceProfile) sdk() iamtypes.InstanceProfile {
+	ip := iamtypes.InstanceProfile{
+		InstanceProfileName: sdkaws.String(p.name),
+		Arn:                 sdkaws.String("arn:aws:iam::123456789012:instance-profile/" + p.name),
+		Path:                sdkaws.String("/"),
+	}
+	for _, r := range p.roles {
+		ip.Roles = append(ip.Roles, iamRole{name: r}.sdk())
+	}
+	return ip
+}
+
+// permissionsBoundary returns the SDK boundary for arn, or nil if arn is empty.
+func permissionsBoundary(arn string) *iamtypes.AttachedPermissionsBoundary {
+	if arn == "" {
+		return nil
+	}
+	return &iamtypes.AttachedPermissionsBoundary{PermissionsBoundaryArn: sdkaws.String(arn)}
+}
+
+// sortedNames returns the keys of an inline policy map in sorted order.
+func sortedNames(docs map[string]string) []string {
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func attachedPolicies(arns []string) []iamtypes.AttachedPolicy {
+	out := make([]iamtypes.AttachedPolicy, 0, len(arns))
+	for _, arn := range arns {
+		out = append(out, iamtypes.AttachedPolicy{PolicyArn: sdkaws.String(arn)})
+	}
+	return out
+}
+
+func iamTags(tags map[string]string) []iamtypes.Tag {
+	out := make([]iamtypes.Tag, 0, len(tags))
+	for k, v := range tags {
+		out = append(out, iamtypes.Tag{Key: sdkaws.String(k), Value: sdkaws.String(v)})
+	}
+	return out
+}
+
+func policyVersion(doc string) *iamtypes.PolicyVersion {
+	return &iamtypes.PolicyVersion{Document: sdkaws.String(url.QueryEscape(doc))}
+}
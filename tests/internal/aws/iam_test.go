@@ -0,0 +1,150 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/aws"
+)
+
+const trustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+func TestFetchIAMResources_RolesPaginationAndServiceLinkedSkip(t *testing.T) {
+	client := &fakeIAM{
+		rolePages: [][]iamRole{
+			{{name: "app", trust: trustPolicy}, {name: "AWSServiceRoleForECS", path: "/aws-service-role/ecs.amazonaws.com/"}},
+			{{name: "svc", path: "/svc/"}},
+		},
+		attachedRole: map[string][]string{"app": {"arn:aws:iam::aws:policy/ReadOnlyAccess"}},
+	}
+
+	state, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.listRolesCallCnt)
+	require.Len(t, state.Roles, 2)
+	assert.Equal(t, "app", state.Roles[0].RoleName)
+	assert.JSONEq(t, trustPolicy, state.Roles[0].AssumeRolePolicy)
+	assert.Equal(t, []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"}, state.Roles[0].AttachedPolicies)
+	assert.Equal(t, "svc", state.Roles[1].RoleName)
+	assert.Equal(t, "/svc/", state.Roles[1].Path)
+}
+
+func TestFetchIAMResources_UsersPoliciesGroups(t *testing.T) {
+	policyArn := "arn:aws:iam::123456789012:policy/read"
+	client := &fakeIAM{
+		users:           []iamUser{{name: "alice"}},
+		userTags:        map[string]map[string]string{"alice": {"team": "platform"}},
+		attachedUser:    map[string][]string{"alice": {policyArn}},
+		policies:        []iamPolicy{{name: "read", arn: policyArn}},
+		policyDocuments: map[string]string{policyArn: `{"Version":"2012-10-17","Statement":[]}`},
+		policyTags:      map[string]map[string]string{policyArn: {"owner": "sec"}},
+		groups:          []iamGroup{{name: "admins"}},
+		attachedGroup:   map[string][]string{"admins": {policyArn}},
+		groupMembers:    map[string][]string{"admins": {"alice"}},
+	}
+
+	state, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Errors)
+
+	require.Len(t, state.Users, 1)
+	assert.Equal(t, "platform", state.Users[0].Tags["team"])
+	assert.Equal(t, []string{policyArn}, state.Users[0].AttachedPolicies)
+
+	require.Len(t, state.Policies, 1)
+	assert.JSONEq(t, `{"Version":"2012-10-17","Statement":[]}`, state.Policies[0].PolicyDocument)
+	assert.Equal(t, "sec", state.Policies[0].Tags["owner"])
+
+	require.Len(t, state.Groups, 1)
+	assert.Equal(t, []string{policyArn}, state.Groups[0].AttachedPolicies)
+	assert.Equal(t, []string{"alice"}, state.Groups[0].Members)
+}
+
+func TestFetchIAMResources_PartialFailureRecordsError(t *testing.T) {
+	client := &fakeIAM{
+		rolePages: [][]iamRole{{{name: "app", trust: trustPolicy}}},
+		users:     []iamUser{{name: "bob"}},
+		errs: map[string]error{
+			"ListAttachedRolePolicies/app": apiError("Throttling"),
+			"ListUserTags/bob":             apiError("AccessDenied"),
+		},
+	}
+
+	state, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Roles, 1)
+	assert.Empty(t, state.Roles[0].AttachedPolicies)
+	require.Len(t, state.Users, 1)
+	assert.Empty(t, state.Users[0].Tags)
+
+	require.Len(t, state.Errors, 2)
+	byResource := make(map[string]string)
+	for _, fe := range state.Errors {
+		byResource[fe.ResourceType+"/"+fe.Resource] = fe.Operation + ":" + fe.ErrorCode
+	}
+	assert.Equal(t, "ListAttachedRolePolicies:Throttling", byResource["aws_iam_role/app"])
+	assert.Equal(t, "ListUserTags:AccessDenied", byResource["aws_iam_user/bob"])
+}
+
+func TestFetchIAMResources_InlinePoliciesBoundariesAndInstanceProfiles(t *testing.T) {
+	boundary := "arn:aws:iam::123456789012:policy/boundary"
+	inlineDoc := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	client := &fakeIAM{
+		rolePages:        [][]iamRole{{{name: "app", trust: trustPolicy, boundary: boundary}}},
+		users:            []iamUser{{name: "alice", boundary: boundary}},
+		groups:           []iamGroup{{name: "admins"}},
+		roleInline:       map[string]map[string]string{"app": {"read": inlineDoc}},
+		userInline:       map[string]map[string]string{"alice": {"read": inlineDoc}},
+		groupInline:      map[string]map[string]string{"admins": {"read": inlineDoc}},
+		instanceProfiles: []iamInstanceProfile{{name: "app-profile", roles: []string{"app"}}},
+		profileTags:      map[string]map[string]string{"app-profile": {"env": "prod"}},
+	}
+
+	state, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Errors)
+
+	require.Len(t, state.Roles, 1)
+	assert.Equal(t, boundary, state.Roles[0].PermissionsBoundary)
+	assert.JSONEq(t, inlineDoc, state.Roles[0].InlinePolicies["read"])
+
+	require.Len(t, state.Users, 1)
+	assert.Equal(t, boundary, state.Users[0].PermissionsBoundary)
+	assert.JSONEq(t, inlineDoc, state.Users[0].InlinePolicies["read"])
+
+	require.Len(t, state.Groups, 1)
+	assert.JSONEq(t, inlineDoc, state.Groups[0].InlinePolicies["read"])
+
+	require.Len(t, state.InstanceProfiles, 1)
+	assert.Equal(t, "app-profile", state.InstanceProfiles[0].InstanceProfileName)
+	assert.Equal(t, []string{"app"}, state.InstanceProfiles[0].Roles)
+	assert.Equal(t, "prod", state.InstanceProfiles[0].Tags["env"])
+}
+
+func TestFetchIAMResources_InlinePolicyFailureRecordsError(t *testing.T) {
+	client := &fakeIAM{
+		rolePages:  [][]iamRole{{{name: "app", trust: trustPolicy}}},
+		roleInline: map[string]map[string]string{"app": {"read": `{}`}},
+		errs:       map[string]error{"GetRolePolicy/app": apiError("AccessDenied")},
+	}
+
+	state, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Roles, 1)
+	assert.Nil(t, state.Roles[0].InlinePolicies)
+	require.Len(t, state.Errors, 1)
+	assert.Equal(t, "aws_iam_role", state.Errors[0].ResourceType)
+	assert.Equal(t, "GetRolePolicy", state.Errors[0].Operation)
+	assert.Equal(t, "AccessDenied", state.Errors[0].ErrorCode)
+}
+
+func TestFetchIAMResources_ListError(t *testing.T) {
+	client := &fakeIAM{errs: map[string]error{"ListGroups": apiError("AccessDenied")}}
+
+	_, err := aws.FetchIAMResourcesWithClient(context.Background(), client)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ListGroups")
+}
@@ -0,0 +1,401 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/aws"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+func TestFetchS3Buckets_FullConfiguration(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"data"},
+		tagging: map[string]*s3.GetBucketTaggingOutput{
+			"data": {TagSet: []s3types.Tag{{Key: sdkaws.String("env"), Value: sdkaws.String("prod")}}},
+		},
+		versioning: map[string]*s3.GetBucketVersioningOutput{
+			"data": {Status: s3types.BucketVersioningStatusEnabled},
+		},
+		encryption: map[string]*s3.GetBucketEncryptionOutput{
+			"data": {ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{
+				Rules: []s3types.ServerSideEncryptionRule{{
+					ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{
+						SSEAlgorithm: s3types.ServerSideEncryptionAwsKms,
+					},
+				}},
+			}},
+		},
+		logging: map[string]*s3.GetBucketLoggingOutput{
+			"data": {LoggingEnabled: &s3types.LoggingEnabled{
+				TargetBucket: sdkaws.String("logs"),
+				TargetPrefix: sdkaws.String("data/"),
+			}},
+		},
+		pab: map[string]*s3.GetPublicAccessBlockOutput{
+			"data": {PublicAccessBlockConfiguration: &s3types.PublicAccessBlockConfiguration{
+				BlockPublicAcls:       sdkaws.Bool(true),
+				IgnorePublicAcls:      sdkaws.Bool(true),
+				BlockPublicPolicy:     sdkaws.Bool(true),
+				RestrictPublicBuckets: sdkaws.Bool(false),
+			}},
+		},
+		lifecycle: map[string]*s3.GetBucketLifecycleConfigurationOutput{
+			"data": {Rules: []s3types.LifecycleRule{{ID: sdkaws.String("expire"), Status: s3types.ExpirationStatusEnabled}}},
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Buckets, 1)
+	assert.Empty(t, state.Errors)
+
+	b := state.Buckets[0]
+	assert.Equal(t, "data", b.Name)
+	assert.Equal(t, map[string]string{"env": "prod"}, b.Tags)
+	assert.True(t, b.VersioningEnabled)
+	assert.Equal(t, "aws:kms", b.EncryptionAlgorithm)
+	assert.True(t, b.LoggingEnabled)
+	assert.Equal(t, "logs", b.LoggingTargetBucket)
+	assert.Equal(t, "data/", b.LoggingTargetPrefix)
+	assert.True(t, b.PublicAccessBlock.BlockPublicAcls)
+	assert.False(t, b.PublicAccessBlock.RestrictPublicBuckets)
+	require.Len(t, b.LifecycleRules, 1)
+	assert.Equal(t, "expire", b.LifecycleRules[0].ID)
+	assert.Equal(t, "Enabled", b.LifecycleRules[0].Status)
+}
+
+func TestFetchS3Buckets_Pagination(t *testing.T) {
+	client := &fakeS3{buckets: []string{"a", "b", "c"}, pageSize: 2}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.listCalls)
+	require.Len(t, state.Buckets, 3)
+	assert.Equal(t, "a", state.Buckets[0].Name)
+	assert.Equal(t, "c", state.Buckets[2].Name)
+	assert.Empty(t, state.Errors)
+}
+
+func TestFetchS3Buckets_NotFoundErrorsAreIgnored(t *testing.T) {
+	// The fake returns NoSuchTagSet, ServerSideEncryptionConfigurationNotFoundError,
+	// NoSuchPublicAccessBlockConfiguration and NoSuchLifecycleConfiguration by default.
+	client := &fakeS3{buckets: []string{"bare"}}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Buckets, 1)
+	assert.Empty(t, state.Errors)
+
+	b := state.Buckets[0]
+	assert.Equal(t, "bare", b.Name)
+	assert.NotNil(t, b.Tags)
+	assert.Empty(t, b.Tags)
+	assert.Empty(t, b.EncryptionAlgorithm)
+	assert.Empty(t, b.LifecycleRules)
+	assert.False(t, b.PublicAccessBlock.BlockPublicAcls)
+}
+
+func TestFetchS3Buckets_PartialFailureRecordsError(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"ok", "denied"},
+		errs: map[string]error{
+			"GetBucketVersioning/denied": apiError("AccessDenied"),
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Buckets, 1)
+	assert.Equal(t, "ok", state.Buckets[0].Name)
+
+	require.Len(t, state.Errors, 1)
+	fe := state.Errors[0]
+	assert.Equal(t, "s3", fe.Service)
+	assert.Equal(t, "aws_s3_bucket", fe.ResourceType)
+	assert.Equal(t, "denied", fe.Resource)
+	assert.Equal(t, "GetBucketVersioning", fe.Operation)
+	assert.Equal(t, "AccessDenied", fe.ErrorCode)
+}
+
+func TestFetchS3Buckets_FetchTraceReportsEachBucket(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"ok", "denied"},
+		errs: map[string]error{
+			"GetBucketVersioning/denied": apiError("AccessDenied"),
+		},
+	}
+
+	var fetched []string
+	var failed []models.FetchError
+	ctx := aws.WithFetchTrace(context.Background(), &aws.FetchTrace{
+		Fetched: func(r interface{}) { fetched = append(fetched, r.(models.S3Bucket).Name) },
+		Failed:  func(e models.FetchError) { failed = append(failed, e) },
+	})
+
+	state, err := aws.FetchS3BucketsWithClient(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ok"}, fetched)
+	assert.Equal(t, state.Errors, failed)
+}
+
+func TestFetchS3Buckets_UnexpectedLifecycleErrorFailsBucket(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"broken"},
+		errs: map[string]error{
+			"GetBucketLifecycleConfiguration/broken": apiError("InternalError"),
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Buckets)
+	require.Len(t, state.Errors, 1)
+	assert.Equal(t, "GetBucketLifecycleConfiguration", state.Errors[0].Operation)
+	assert.Equal(t, "InternalError", state.Errors[0].ErrorCode)
+}
+
+func TestFetchS3Buckets_LifecycleRuleDetails(t *testing.T) {
+	date := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeS3{
+		buckets: []string{"data"},
+		lifecycle: map[string]*s3.GetBucketLifecycleConfigurationOutput{
+			"data": {Rules: []s3types.LifecycleRule{
+				{
+					ID:     sdkaws.String("archive"),
+					Status: s3types.ExpirationStatusEnabled,
+					Filter: &s3types.LifecycleRuleFilter{And: &s3types.LifecycleRuleAndOperator{
+						Prefix:                sdkaws.String("logs/"),
+						Tags:                  []s3types.Tag{{Key: sdkaws.String("tier"), Value: sdkaws.String("cold")}},
+						ObjectSizeGreaterThan: sdkaws.Int64(1024),
+					}},
+					Expiration:                  &s3types.LifecycleExpiration{Days: sdkaws.Int32(365)},
+					NoncurrentVersionExpiration: &s3types.NoncurrentVersionExpiration{NoncurrentDays: sdkaws.Int32(30), NewerNoncurrentVersions: sdkaws.Int32(2)},
+					Transitions: []s3types.Transition{
+						{Days: sdkaws.Int32(90), StorageClass: s3types.TransitionStorageClassGlacier},
+						{Days: sdkaws.Int32(30), StorageClass: s3types.TransitionStorageClassStandardIa},
+					},
+					NoncurrentVersionTransitions:   []s3types.NoncurrentVersionTransition{{NoncurrentDays: sdkaws.Int32(7), StorageClass: s3types.TransitionStorageClassGlacierIr}},
+					AbortIncompleteMultipartUpload: &s3types.AbortIncompleteMultipartUpload{DaysAfterInitiation: sdkaws.Int32(3)},
+				},
+				{
+					ID:         sdkaws.String("sunset"),
+					Status:     s3types.ExpirationStatusDisabled,
+					Filter:     &s3types.LifecycleRuleFilter{Prefix: sdkaws.String("tmp/")},
+					Expiration: &s3types.LifecycleExpiration{Date: &date},
+				},
+			}},
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, state.Buckets, 1)
+	require.Len(t, state.Buckets[0].LifecycleRules, 2)
+
+	assert.Equal(t, models.LifecycleRuleSummary{
+		ID:                       "archive",
+		Status:                   "Enabled",
+		Prefix:                   "logs/",
+		FilterTags:               map[string]string{"tier": "cold"},
+		ObjectSizeGreaterThan:    1024,
+		ExpirationDays:           365,
+		NoncurrentExpirationDays: 30,
+		NewerNoncurrentVersions:  2,
+		Transitions: []models.LifecycleTransition{
+			{Days: 30, StorageClass: "STANDARD_IA"},
+			{Days: 90, StorageClass: "GLACIER"},
+		},
+		NoncurrentTransitions:        []models.LifecycleTransition{{Days: 7, StorageClass: "GLACIER_IR"}},
+		AbortIncompleteMultipartDays: 3,
+	}, state.Buckets[0].LifecycleRules[0])
+
+	sunset := state.Buckets[0].LifecycleRules[1]
+	assert.Equal(t, "Disabled", sunset.Status)
+	assert.Equal(t, "tmp/", sunset.Prefix)
+	assert.Equal(t, "2030-01-01", sunset.ExpirationDate)
+}
+
+func TestFetchS3Buckets_ListBucketsError(t *testing.T) {
+	client := &fakeS3{listErr: apiError("AccessDenied")}
+
+	_, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ListBuckets")
+}
+
+func TestFetchS3Buckets_CancelledAfterListRecordsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &fakeS3{buckets: []string{"a", "b"}, afterList: cancel}
+
+	state, err := aws.FetchS3BucketsWithClient(ctx, client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Buckets)
+	require.Len(t, state.Errors, 2)
+	for _, fe := range state.Errors {
+		assert.Contains(t, fe.Message, context.Canceled.Error())
+	}
+}
+
+func s3Grant(granteeType s3types.Type, id, uri string, perm s3types.Permission) s3types.Grant {
+	g := s3types.Grant{Grantee: &s3types.Grantee{Type: granteeType}, Permission: perm}
+	if id != "" {
+		g.Grantee.ID = sdkaws.String(id)
+	}
+	if uri != "" {
+		g.Grantee.URI = sdkaws.String(uri)
+	}
+	return g
+}
+
+func TestFetchS3Buckets_CannedACLFromGrants(t *testing.T) {
+	const (
+		owner       = "owner-id"
+		allUsers    = "http://acs.amazonaws.com/groups/global/AllUsers"
+		authUsers   = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+		logDelivery = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+	)
+	ownerGrant := s3Grant(s3types.TypeCanonicalUser, owner, "", s3types.PermissionFullControl)
+	acl := func(grants ...s3types.Grant) *s3.GetBucketAclOutput {
+		return &s3.GetBucketAclOutput{Owner: &s3types.Owner{ID: sdkaws.String(owner)}, Grants: grants}
+	}
+
+	client := &fakeS3{
+		buckets: []string{"private", "public", "auth", "logs", "cross-account", "enforced"},
+		acl: map[string]*s3.GetBucketAclOutput{
+			"private": acl(ownerGrant),
+			"public":  acl(ownerGrant, s3Grant(s3types.TypeGroup, "", allUsers, s3types.PermissionRead)),
+			"auth":    acl(ownerGrant, s3Grant(s3types.TypeGroup, "", authUsers, s3types.PermissionRead)),
+			"logs": acl(ownerGrant,
+				s3Grant(s3types.TypeGroup, "", logDelivery, s3types.PermissionWrite),
+				s3Grant(s3types.TypeGroup, "", logDelivery, s3types.PermissionReadAcp)),
+			"cross-account": acl(ownerGrant, s3Grant(s3types.TypeCanonicalUser, "other-id", "", s3types.PermissionRead)),
+			"enforced":      acl(ownerGrant, s3Grant(s3types.TypeGroup, "", allUsers, s3types.PermissionRead)),
+		},
+		ownership: map[string]*s3.GetBucketOwnershipControlsOutput{
+			"enforced": {OwnershipControls: &s3types.OwnershipControls{Rules: []s3types.OwnershipControlsRule{
+				{ObjectOwnership: s3types.ObjectOwnershipBucketOwnerEnforced},
+			}}},
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Empty(t, state.Errors)
+
+	got := map[string]string{}
+	for _, b := range state.Buckets {
+		got[b.Name] = b.Acl
+	}
+	assert.Equal(t, map[string]string{
+		"private":       "private",
+		"public":        "public-read",
+		"auth":          "authenticated-read",
+		"logs":          "log-delivery-write",
+		"cross-account": "",
+		"enforced":      "private",
+	}, got)
+
+	for _, b := range state.Buckets {
+		if b.Name == "cross-account" {
+			assert.Equal(t, []models.AclGrant{
+				{Grantee: "id:other-id", Permission: "READ"},
+				{Grantee: "id:owner-id", Permission: "FULL_CONTROL"},
+			}, b.AclGrants)
+		}
+		if b.Name == "enforced" {
+			assert.Equal(t, "BucketOwnerEnforced", b.ObjectOwnership)
+		}
+	}
+}
+
+func TestFetchS3Buckets_PolicyCORSReplicationObjectLock(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"data"},
+		encryption: map[string]*s3.GetBucketEncryptionOutput{
+			"data": {ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{
+				Rules: []s3types.ServerSideEncryptionRule{{
+					ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{
+						SSEAlgorithm:   s3types.ServerSideEncryptionAwsKms,
+						KMSMasterKeyID: sdkaws.String("arn:aws:kms:us-east-1:123456789012:key/abcd"),
+					},
+					BucketKeyEnabled: sdkaws.Bool(true),
+				}},
+			}},
+		},
+		policy: map[string]string{"data": `{"Version":"2012-10-17","Statement":[]}`},
+		cors: map[string]*s3.GetBucketCorsOutput{
+			"data": {CORSRules: []s3types.CORSRule{{
+				AllowedMethods: []string{"GET"},
+				AllowedOrigins: []string{"https://example.com"},
+				MaxAgeSeconds:  sdkaws.Int32(300),
+			}}},
+		},
+		repl: map[string]*s3.GetBucketReplicationOutput{
+			"data": {ReplicationConfiguration: &s3types.ReplicationConfiguration{
+				Role: sdkaws.String("arn:aws:iam::123456789012:role/replication"),
+				Rules: []s3types.ReplicationRule{{
+					ID:                      sdkaws.String("all"),
+					Status:                  s3types.ReplicationRuleStatusEnabled,
+					Priority:                sdkaws.Int32(1),
+					Filter:                  &s3types.ReplicationRuleFilter{Prefix: sdkaws.String("logs/")},
+					Destination:             &s3types.Destination{Bucket: sdkaws.String("arn:aws:s3:::replica")},
+					DeleteMarkerReplication: &s3types.DeleteMarkerReplication{Status: s3types.DeleteMarkerReplicationStatusEnabled},
+				}},
+			}},
+		},
+		objectLock: map[string]*s3.GetObjectLockConfigurationOutput{
+			"data": {ObjectLockConfiguration: &s3types.ObjectLockConfiguration{
+				ObjectLockEnabled: s3types.ObjectLockEnabledEnabled,
+				Rule: &s3types.ObjectLockRule{DefaultRetention: &s3types.DefaultRetention{
+					Mode: s3types.ObjectLockRetentionModeGovernance,
+					Days: sdkaws.Int32(30),
+				}},
+			}},
+		},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	require.Empty(t, state.Errors)
+	require.Len(t, state.Buckets, 1)
+	b := state.Buckets[0]
+
+	assert.Equal(t, "arn:aws:kms:us-east-1:123456789012:key/abcd", b.KMSKeyID)
+	assert.True(t, b.BucketKeyEnabled)
+	assert.Equal(t, `{"Version":"2012-10-17","Statement":[]}`, b.Policy)
+	require.Len(t, b.CORSRules, 1)
+	assert.Equal(t, 300, b.CORSRules[0].MaxAgeSeconds)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/replication", b.Replication.Role)
+	assert.Equal(t, []models.ReplicationRule{{
+		ID:                      "all",
+		Status:                  "Enabled",
+		Priority:                1,
+		Prefix:                  "logs/",
+		DestinationBucket:       "arn:aws:s3:::replica",
+		DeleteMarkerReplication: true,
+	}}, b.Replication.Rules)
+	assert.Equal(t, models.ObjectLockConfig{Enabled: true, Mode: "GOVERNANCE", Days: 30}, b.ObjectLock)
+}
+
+func TestFetchS3Buckets_PolicyAccessDeniedFailsBucket(t *testing.T) {
+	client := &fakeS3{
+		buckets: []string{"locked"},
+		errs:    map[string]error{"GetBucketPolicy/locked": apiError("AccessDenied")},
+	}
+
+	state, err := aws.FetchS3BucketsWithClient(context.Background(), client)
+	require.NoError(t, err)
+	assert.Empty(t, state.Buckets)
+	require.Len(t, state.Errors, 1)
+	assert.Equal(t, "GetBucketPolicy", state.Errors[0].Operation)
+	assert.Equal(t, "AccessDenied", state.Errors[0].ErrorCode)
+}
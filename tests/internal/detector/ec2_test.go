@@ -1,11 +1,14 @@
 package detector
 
 import (
+	"context"
 	"testing"
 
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/inayathulla/cloudrift/internal/detector"
 	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test missing instance
@@ -281,6 +284,142 @@ func TestDetectEC2Drift_KeyName_Negative(t *testing.T) {
 	assert.False(t, res.KeyNameDiff)
 }
 
+// Root volume drift
+func TestDetectEC2Drift_RootVolume_Positive(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{VolumeType: "gp3", VolumeSize: 20, Encrypted: sdkaws.Bool(true), IOPS: 3000},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{DeviceName: "/dev/xvda", VolumeType: "gp3", VolumeSize: 20, Encrypted: sdkaws.Bool(true), IOPS: 4000},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.RootVolumeDiff)
+}
+
+func TestDetectEC2Drift_RootVolume_Negative(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{VolumeType: "gp3", Encrypted: sdkaws.Bool(true)},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{DeviceName: "/dev/xvda", VolumeType: "gp3", VolumeSize: 8, Encrypted: sdkaws.Bool(true), IOPS: 3000},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.False(t, res.RootVolumeDiff)
+}
+
+func TestDetectEC2Drift_RootVolume_EncryptionOnlyComparedWhenPlanned(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{VolumeType: "gp3"},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:      "i-12345",
+		RootBlockDevice: models.BlockDevice{VolumeType: "gp3", Encrypted: sdkaws.Bool(true)},
+	}
+	// Encrypted by the account default
+	assert.False(t, detector.DetectEC2Drift(plan, actual).RootVolumeDiff)
+
+	plan.RootBlockDevice.Encrypted = sdkaws.Bool(false)
+	assert.True(t, detector.DetectEC2Drift(plan, actual).RootVolumeDiff)
+}
+
+// Additional EBS volume drift
+func TestDetectEC2Drift_EBSVolumes(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		EBSBlockDevices: []models.BlockDevice{{DeviceName: "/dev/sdf", VolumeType: "gp3", VolumeSize: 100}},
+	}
+	actual := &models.EC2Instance{
+		InstanceID: "i-12345",
+		EBSBlockDevices: []models.BlockDevice{
+			{DeviceName: "/dev/sdf", VolumeType: "gp3", VolumeSize: 100},
+			{DeviceName: "/dev/sdh", VolumeType: "gp2", VolumeSize: 10},
+		},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.False(t, res.EBSVolumesDiff, "unplanned live volumes are ignored")
+
+	actual.EBSBlockDevices[0].VolumeSize = 200
+	res = detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.EBSVolumesDiff)
+
+	actual.EBSBlockDevices = actual.EBSBlockDevices[1:]
+	res = detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.EBSVolumesDiff, "planned volume detached")
+}
+
+// Metadata options drift
+func TestDetectEC2Drift_MetadataOptions_Positive(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		MetadataOptions: models.MetadataOptions{HTTPTokens: "required"},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:      "i-12345",
+		MetadataOptions: models.MetadataOptions{HTTPTokens: "optional", HTTPEndpoint: "enabled"},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.MetadataOptionsDiff)
+}
+
+func TestDetectEC2Drift_MetadataOptions_Negative(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:      "i-12345",
+		MetadataOptions: models.MetadataOptions{HTTPTokens: "required"},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:      "i-12345",
+		MetadataOptions: models.MetadataOptions{HTTPTokens: "required", HTTPEndpoint: "enabled", HTTPPutResponseHopLimit: 2},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.False(t, res.MetadataOptionsDiff)
+}
+
+// Network interface drift
+func TestDetectEC2Drift_NetworkInterfaces(t *testing.T) {
+	plan := models.EC2Instance{
+		InstanceID:        "i-12345",
+		NetworkInterfaces: []models.NetworkInterface{{NetworkInterfaceID: "eni-1", DeviceIndex: 0}},
+	}
+	actual := &models.EC2Instance{
+		InstanceID:        "i-12345",
+		NetworkInterfaces: []models.NetworkInterface{{NetworkInterfaceID: "eni-1", DeviceIndex: 0, SubnetID: "subnet-1"}},
+	}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.False(t, res.NetworkInterfacesDiff)
+
+	actual.NetworkInterfaces[0].NetworkInterfaceID = "eni-2"
+	res = detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.NetworkInterfacesDiff)
+}
+
+// User data and API protection drift
+func TestDetectEC2Drift_UserData(t *testing.T) {
+	plan := models.EC2Instance{InstanceID: "i-12345", UserDataHash: models.HashUserData([]byte("v1"))}
+	actual := &models.EC2Instance{InstanceID: "i-12345", UserDataHash: models.HashUserData([]byte("v2"))}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.UserDataDiff)
+
+	actual.UserDataHash = plan.UserDataHash
+	res = detector.DetectEC2Drift(plan, actual)
+	assert.False(t, res.UserDataDiff)
+}
+
+func TestDetectEC2Drift_APIProtection(t *testing.T) {
+	plan := models.EC2Instance{InstanceID: "i-12345", DisableAPITermination: true}
+	actual := &models.EC2Instance{InstanceID: "i-12345", DisableAPIStop: true}
+	res := detector.DetectEC2Drift(plan, actual)
+	assert.True(t, res.TerminationProtectionDiff)
+	assert.True(t, res.StopProtectionDiff)
+
+	results := detector.DetectAllEC2Drift([]models.EC2Instance{plan}, []models.EC2Instance{*actual})
+	assert.Len(t, results, 1)
+}
+
 // DetectAllEC2Drift tests
 func TestDetectAllEC2Drift_MultipleInstances(t *testing.T) {
 	plans := []models.EC2Instance{
@@ -333,3 +472,69 @@ func TestEC2Instance_Name(t *testing.T) {
 	}
 	assert.Equal(t, "i-abcde", inst3.Name())
 }
+
+func TestEC2DriftDetector_FetchErrorKeyedByInstanceID(t *testing.T) {
+	web := map[string]string{"Name": "web"}
+	plans := []models.EC2Instance{
+		{InstanceID: "i-1", InstanceType: "t3.micro", Tags: web, TerraformAddress: "aws_instance.a"},
+		{InstanceID: "i-2", InstanceType: "t3.micro", Tags: web, TerraformAddress: "aws_instance.b"},
+	}
+	live := &models.EC2LiveState{
+		Instances: []models.EC2Instance{
+			{InstanceID: "i-1", InstanceType: "t3.micro", Tags: web},
+			{InstanceID: "i-2", InstanceType: "t3.micro", Tags: web},
+		},
+		Errors: []models.FetchError{
+			{Service: "ec2", ResourceType: "aws_instance", Resource: "i-2", Operation: "DescribeVolumes"},
+		},
+	}
+
+	det := detector.NewEC2DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, live)
+	require.NoError(t, err)
+
+	// The instance sharing the failed instance's Name tag is not affected
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Unknown)
+	assert.Equal(t, "i-2", results[0].ResourceID)
+	assert.Equal(t, "aws_instance.b", results[0].TerraformAddress)
+}
+
+func TestEC2DriftDetector_FetchErrorWithDriftedNameTag(t *testing.T) {
+	plans := []models.EC2Instance{
+		{InstanceID: "i-1", Tags: map[string]string{"Name": "web"}, DisableAPITermination: true},
+	}
+	live := &models.EC2LiveState{
+		// Half-populated: the attribute lookup failed
+		Instances: []models.EC2Instance{{InstanceID: "i-1", Tags: map[string]string{"Name": "web-renamed"}}},
+		Errors: []models.FetchError{
+			{Service: "ec2", ResourceType: "aws_instance", Resource: "i-1", Operation: "DescribeInstanceAttribute"},
+		},
+	}
+
+	det := detector.NewEC2DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, live)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Unknown)
+	assert.Empty(t, results[0].TagDiffs)
+	assert.False(t, results[0].AclDiff)
+}
+
+func TestEC2UnfetchedLiveState_ReportsAllPlannedAsUnknown(t *testing.T) {
+	plans := []models.EC2Instance{
+		{InstanceID: "i-1", Tags: map[string]string{"Name": "web"}},
+		{Tags: map[string]string{"Name": "new"}},
+	}
+	live, ok := detector.UnfetchedLiveState(plans, context.Canceled)
+	require.True(t, ok)
+
+	det := detector.NewEC2DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, live)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.True(t, r.Unknown)
+		assert.False(t, r.Missing)
+	}
+}
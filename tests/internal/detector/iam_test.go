@@ -3,9 +3,11 @@ package detector
 import (
 	"testing"
 
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/inayathulla/cloudrift/internal/detector"
 	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ──────────────────────────────────────────────────────────────────────────────
@@ -223,6 +225,11 @@ func TestDetectIAMPolicyDrift_DocumentDiff(t *testing.T) {
 	res := detector.DetectIAMPolicyDrift(plan, actual)
 	assert.True(t, res.PolicyDocumentDiff)
 	assert.True(t, res.HasAnyDrift())
+
+	require.Contains(t, res.PolicyDiffs, "policy_document")
+	removed, added := res.PolicyDiffs["policy_document"].Lines()
+	assert.Equal(t, []string{"Allow Action s3:GetObject"}, removed)
+	assert.Equal(t, []string{"Allow Action * (wildcard)"}, added)
 }
 
 func TestDetectIAMPolicyDrift_DescriptionDiff(t *testing.T) {
@@ -307,6 +314,113 @@ func TestDetectIAMGroupDrift_AttachedPoliciesDiff(t *testing.T) {
 // DetectAllIAMDrift
 // ──────────────────────────────────────────────────────────────────────────────
 
+// ──────────────────────────────────────────────────────────────────────────────
+// Inline Policies, Permissions Boundaries and Instance Profiles
+// ──────────────────────────────────────────────────────────────────────────────
+
+func TestDetectIAMRoleDrift_InlinePolicies(t *testing.T) {
+	planDoc := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"}]}`
+	plan := models.IAMRole{
+		RoleName:       "my-role",
+		InlinePolicies: map[string]string{"read": planDoc},
+	}
+
+	// Same document, different spelling
+	actual := &models.IAMRole{
+		RoleName:       "my-role",
+		InlinePolicies: map[string]string{"read": `{"Statement":{"Resource":"*","Action":"s3:GetObject","Effect":"Allow"},"Version":"2012-10-17"}`},
+	}
+	assert.False(t, detector.DetectIAMRoleDrift(plan, actual).InlinePoliciesDiff)
+
+	// Escalated document
+	actual.InlinePolicies["read"] = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`
+	assert.True(t, detector.DetectIAMRoleDrift(plan, actual).InlinePoliciesDiff)
+
+	// Extra inline policy added out of band
+	actual.InlinePolicies = map[string]string{"read": planDoc, "backdoor": planDoc}
+	res := detector.DetectIAMRoleDrift(plan, actual)
+	assert.True(t, res.InlinePoliciesDiff)
+	assert.True(t, res.HasAnyDrift())
+}
+
+func TestDetectIAMDrift_InlinePolicyNotInPlan(t *testing.T) {
+	doc := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`
+	live := map[string]string{"backdoor": doc}
+
+	role := detector.DetectIAMRoleDrift(models.IAMRole{RoleName: "r"}, &models.IAMRole{RoleName: "r", InlinePolicies: live})
+	assert.True(t, role.InlinePoliciesDiff)
+	assert.True(t, role.HasAnyDrift())
+
+	user := detector.DetectIAMUserDrift(models.IAMUser{UserName: "u"}, &models.IAMUser{UserName: "u", InlinePolicies: live})
+	assert.True(t, user.InlinePoliciesDiff)
+
+	group := detector.DetectIAMGroupDrift(models.IAMGroup{GroupName: "g"}, &models.IAMGroup{GroupName: "g", InlinePolicies: live})
+	assert.True(t, group.InlinePoliciesDiff)
+
+	// No inline policies on either side is not drift.
+	assert.False(t, detector.DetectIAMRoleDrift(models.IAMRole{RoleName: "r"}, &models.IAMRole{RoleName: "r"}).InlinePoliciesDiff)
+}
+
+func TestDetectIAMDrift_InlinePoliciesUnknownAtPlanTime(t *testing.T) {
+	doc := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	live := map[string]string{"app-20240101": doc}
+
+	role := detector.DetectIAMRoleDrift(models.IAMRole{RoleName: "r", InlinePoliciesUnknown: true}, &models.IAMRole{RoleName: "r", InlinePolicies: live})
+	assert.False(t, role.InlinePoliciesDiff)
+	assert.False(t, role.HasAnyDrift())
+
+	user := detector.DetectIAMUserDrift(models.IAMUser{UserName: "u", InlinePoliciesUnknown: true}, &models.IAMUser{UserName: "u", InlinePolicies: live})
+	assert.False(t, user.InlinePoliciesDiff)
+
+	group := detector.DetectIAMGroupDrift(models.IAMGroup{GroupName: "g", InlinePoliciesUnknown: true}, &models.IAMGroup{GroupName: "g", InlinePolicies: live})
+	assert.False(t, group.InlinePoliciesDiff)
+}
+
+func TestDetectIAMRoleDrift_PermissionsBoundary(t *testing.T) {
+	boundary := "arn:aws:iam::123456789012:policy/boundary"
+	plan := models.IAMRole{RoleName: "my-role", PermissionsBoundary: boundary}
+
+	assert.False(t, detector.DetectIAMRoleDrift(plan, &models.IAMRole{RoleName: "my-role", PermissionsBoundary: boundary}).PermissionsBoundaryDiff)
+	assert.True(t, detector.DetectIAMRoleDrift(plan, &models.IAMRole{RoleName: "my-role"}).PermissionsBoundaryDiff,
+		"boundary removed out of band")
+}
+
+func TestDetectIAMUserDrift_InlinePoliciesAndBoundary(t *testing.T) {
+	plan := models.IAMUser{
+		UserName:            "deploy",
+		PermissionsBoundary: "arn:aws:iam::123456789012:policy/boundary",
+		InlinePolicies:      map[string]string{"deploy": `{"Version":"2012-10-17","Statement":[]}`},
+	}
+	actual := &models.IAMUser{UserName: "deploy"}
+	res := detector.DetectIAMUserDrift(plan, actual)
+	assert.True(t, res.InlinePoliciesDiff)
+	assert.True(t, res.PermissionsBoundaryDiff)
+}
+
+func TestDetectIAMInstanceProfileDrift(t *testing.T) {
+	plan := models.IAMInstanceProfile{
+		InstanceProfileName: "web",
+		Path:                "/",
+		Roles:               []string{"web-role"},
+		Tags:                map[string]string{"env": "prod"},
+	}
+
+	assert.True(t, detector.DetectIAMInstanceProfileDrift(plan, nil).Missing)
+
+	actual := &models.IAMInstanceProfile{
+		InstanceProfileName: "web",
+		Path:                "/",
+		Roles:               []string{"web-role"},
+		Tags:                map[string]string{"env": "prod"},
+	}
+	assert.False(t, detector.DetectIAMInstanceProfileDrift(plan, actual).HasAnyDrift())
+
+	actual.Roles = []string{"admin-role"}
+	res := detector.DetectIAMInstanceProfileDrift(plan, actual)
+	assert.True(t, res.RolesDiff)
+	assert.Equal(t, "instance_profile", res.ResourceType)
+}
+
 func TestDetectAllIAMDrift_MixedResults(t *testing.T) {
 	plans := &models.IAMPlanResources{
 		Roles: []models.IAMRole{
@@ -359,3 +473,86 @@ func TestDetectAllIAMDrift_NoDrift(t *testing.T) {
 	results := detector.DetectAllIAMDrift(plans, lives)
 	assert.Empty(t, results)
 }
+
+func TestIAMDriftDetector_FetchErrorIsUnknown(t *testing.T) {
+	plans := &models.IAMPlanResources{
+		Roles: []models.IAMRole{
+			{RoleName: "role-a", Path: "/", AttachedPolicies: []string{"arn:aws:iam::aws:policy/ReadOnlyAccess"}},
+		},
+	}
+	// The attachment list could not be fetched, so the live role looks detached.
+	lives := &models.IAMLiveState{
+		Roles: []models.IAMRole{{RoleName: "role-a", Path: "/"}},
+		Errors: []models.FetchError{
+			{Service: "iam", ResourceType: "aws_iam_role", Resource: "role-a", Operation: "ListAttachedRolePolicies", ErrorCode: "Throttling"},
+		},
+	}
+
+	det := detector.NewIAMDriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, lives)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "role-a", results[0].BucketName)
+	assert.True(t, results[0].Unknown)
+	assert.False(t, results[0].AclDiff)
+}
+
+func TestIAMDriftDetector_FetchErrorKeyedByType(t *testing.T) {
+	plans := &models.IAMPlanResources{
+		Roles: []models.IAMRole{{RoleName: "app", Path: "/"}},
+		Users: []models.IAMUser{{UserName: "app", Path: "/"}},
+	}
+	// Only the role failed; the user of the same name was fetched and drifted.
+	lives := &models.IAMLiveState{
+		Roles: []models.IAMRole{{RoleName: "app", Path: "/"}},
+		Users: []models.IAMUser{{UserName: "app", Path: "/ops/"}},
+		Errors: []models.FetchError{
+			{Service: "iam", ResourceType: "aws_iam_role", Resource: "app", Operation: "GetRole", ErrorCode: "Throttling"},
+		},
+	}
+
+	det := detector.NewIAMDriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, lives)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	byType := make(map[string]detector.DriftResult)
+	for _, r := range results {
+		byType[r.ResourceType] = r
+	}
+	assert.True(t, byType["aws_iam_role"].Unknown)
+	assert.False(t, byType["aws_iam_user"].Unknown)
+	assert.True(t, byType["aws_iam_user"].AclDiff)
+}
+
+func TestIAMDriftDetector_PolicyStatementDiffs(t *testing.T) {
+	plans := &models.IAMPlanResources{
+		Roles: []models.IAMRole{{
+			RoleName:         "role-a",
+			AssumeRolePolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+			InlinePolicies:   map[string]string{"logs": `{"Statement":[{"Effect":"Allow","Action":"logs:PutLogEvents","Resource":"*"}]}`},
+		}},
+	}
+	lives := &models.IAMLiveState{
+		Roles: []models.IAMRole{{
+			RoleName:         "role-a",
+			AssumeRolePolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`,
+			InlinePolicies:   map[string]string{"logs": `{"Statement":[{"Effect":"Allow","Action":"logs:*","Resource":"*"}]}`},
+		}},
+	}
+
+	det := detector.NewIAMDriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, lives)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].AclDiff)
+
+	assert.Equal(t, [2]interface{}{
+		[]string{"Allow Principal.Service ec2.amazonaws.com"},
+		[]string{"Allow Principal.AWS * (wildcard)"},
+	}, results[0].Diffs["assume_role_policy"])
+	assert.Equal(t, [2]interface{}{
+		[]string{"Allow Action logs:PutLogEvents"},
+		[]string{"Allow Action logs:* (wildcard)"},
+	}, results[0].Diffs["inline_policies.logs"])
+}
@@ -1,11 +1,14 @@
 package detector
 
 import (
+	"context"
 	"testing"
 
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/inayathulla/cloudrift/internal/detector"
 	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ACL positive and negative
@@ -23,6 +26,39 @@ func TestDetectS3Drift_ACL_Negative(t *testing.T) {
 	assert.False(t, res.AclDiff)
 }
 
+func TestDetectS3Drift_ACL_UnsetPlanMeansPrivate(t *testing.T) {
+	owner := []models.AclGrant{{Grantee: "id:owner", Permission: "FULL_CONTROL"}}
+	plan := models.S3Bucket{Name: "b-acl"}
+	actual := &models.S3Bucket{Name: "b-acl", Acl: "private", AclGrants: owner, ObjectOwnership: "BucketOwnerEnforced"}
+	assert.False(t, detector.DetectS3Drift(plan, actual).AclDiff)
+
+	actual.Acl = "public-read"
+	assert.True(t, detector.DetectS3Drift(plan, actual).AclDiff)
+}
+
+func TestDetectS3Drift_ACL_NoCannedMatch(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-acl", Acl: "private"}
+	actual := &models.S3Bucket{Name: "b-acl", AclGrants: []models.AclGrant{
+		{Grantee: "id:other", Permission: "READ"},
+		{Grantee: "id:owner", Permission: "FULL_CONTROL"},
+	}}
+	assert.True(t, detector.DetectS3Drift(plan, actual).AclDiff)
+}
+
+func TestDetectS3Drift_ACL_Grants(t *testing.T) {
+	grants := []models.AclGrant{
+		{Grantee: "id:other", Permission: "READ"},
+		{Grantee: "id:owner", Permission: "FULL_CONTROL"},
+	}
+	plan := models.S3Bucket{Name: "b-acl", AclGrants: grants}
+	actual := &models.S3Bucket{Name: "b-acl", AclGrants: grants}
+	assert.False(t, detector.DetectS3Drift(plan, actual).AclDiff)
+
+	actual.AclGrants = grants[1:]
+	actual.Acl = "private"
+	assert.True(t, detector.DetectS3Drift(plan, actual).AclDiff)
+}
+
 // TagDiff positive and negative
 func TestDetectS3Drift_TagDiff_Positive(t *testing.T) {
 	plan := models.S3Bucket{Name: "b-tag", Tags: map[string]string{"k": "v1"}}
@@ -152,3 +188,212 @@ func TestDetectS3Drift_Lifecycle_Negative(t *testing.T) {
 	res := detector.DetectS3Drift(plan, actual)
 	assert.False(t, res.LifecycleDiff)
 }
+
+func TestDetectS3Drift_Lifecycle_OrderIndependent(t *testing.T) {
+	archive := models.LifecycleRuleSummary{
+		ID: "archive", Status: "Enabled", Prefix: "logs/",
+		Transitions: []models.LifecycleTransition{
+			{Days: 30, StorageClass: "STANDARD_IA"},
+			{Days: 90, StorageClass: "GLACIER"},
+		},
+	}
+	cleanup := models.LifecycleRuleSummary{ID: "cleanup", Status: "Enabled", AbortIncompleteMultipartDays: 7}
+
+	reordered := archive
+	reordered.Transitions = []models.LifecycleTransition{
+		{Days: 90, StorageClass: "GLACIER"},
+		{Days: 30, StorageClass: "STANDARD_IA"},
+	}
+	plan := models.S3Bucket{Name: "b-lc", LifecycleRules: []models.LifecycleRuleSummary{archive, cleanup}}
+	actual := &models.S3Bucket{Name: "b-lc", LifecycleRules: []models.LifecycleRuleSummary{cleanup, reordered}}
+	assert.False(t, detector.DetectS3Drift(plan, actual).LifecycleDiff)
+}
+
+func TestDetectS3Drift_Lifecycle_RuleDetails(t *testing.T) {
+	base := models.LifecycleRuleSummary{
+		ID: "r1", Status: "Enabled", Prefix: "data/",
+		FilterTags:               map[string]string{"tier": "cold"},
+		ExpirationDays:           365,
+		NoncurrentExpirationDays: 30,
+		Transitions:              []models.LifecycleTransition{{Days: 30, StorageClass: "STANDARD_IA"}},
+		NoncurrentTransitions:    []models.LifecycleTransition{{Days: 7, StorageClass: "GLACIER"}},
+	}
+	cases := map[string]func(r *models.LifecycleRuleSummary){
+		"filter tag":            func(r *models.LifecycleRuleSummary) { r.FilterTags = map[string]string{"tier": "hot"} },
+		"object size":           func(r *models.LifecycleRuleSummary) { r.ObjectSizeGreaterThan = 1024 },
+		"expiration date":       func(r *models.LifecycleRuleSummary) { r.ExpirationDate = "2030-01-01" },
+		"noncurrent expiration": func(r *models.LifecycleRuleSummary) { r.NoncurrentExpirationDays = 60 },
+		"transition class": func(r *models.LifecycleRuleSummary) {
+			r.Transitions = []models.LifecycleTransition{{Days: 30, StorageClass: "GLACIER"}}
+		},
+		"noncurrent transition":  func(r *models.LifecycleRuleSummary) { r.NoncurrentTransitions = nil },
+		"abort multipart upload": func(r *models.LifecycleRuleSummary) { r.AbortIncompleteMultipartDays = 7 },
+		"rule id":                func(r *models.LifecycleRuleSummary) { r.ID = "r2" },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			live := base
+			mutate(&live)
+			plan := models.S3Bucket{Name: "b-lc", LifecycleRules: []models.LifecycleRuleSummary{base}}
+			actual := &models.S3Bucket{Name: "b-lc", LifecycleRules: []models.LifecycleRuleSummary{live}}
+			assert.True(t, detector.DetectS3Drift(plan, actual).LifecycleDiff)
+		})
+	}
+}
+
+// Fetch failures are reported as unknown, never missing
+// Bucket policy positive and negative
+func TestDetectS3Drift_Policy_Positive(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-pol", Policy: `{"Statement":[{"Effect":"Deny"}]}`}
+	actual := &models.S3Bucket{Name: "b-pol", Policy: `{"Statement":[{"Effect":"Allow"}]}`}
+	assert.True(t, detector.DetectS3Drift(plan, actual).PolicyDiff)
+
+	actual.Policy = ""
+	assert.True(t, detector.DetectS3Drift(plan, actual).PolicyDiff, "policy removed out of band")
+}
+
+func TestDetectS3Drift_Policy_Negative(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-pol", Policy: `{"Version": "2012-10-17", "Statement": []}`}
+	actual := &models.S3Bucket{Name: "b-pol", Policy: `{"Statement":[],"Version":"2012-10-17"}`}
+	assert.False(t, detector.DetectS3Drift(plan, actual).PolicyDiff)
+}
+
+func TestDetectAllS3Drift_PolicyOnlyReported(t *testing.T) {
+	plans := []models.S3Bucket{{Name: "b-pol", Policy: `{"Version":"2012-10-17","Statement":[]}`}}
+	lives := []models.S3Bucket{{Name: "b-pol", Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow"}]}`}}
+
+	results := detector.DetectAllS3Drift(plans, lives)
+	require.Len(t, results, 1)
+	require.Contains(t, results[0].Diffs, "policy")
+	assert.Equal(t, [2]interface{}{plans[0].Policy, lives[0].Policy}, results[0].Diffs["policy"])
+
+	// The diff is what makes the converted result count as drifted.
+	info := detector.DriftInfo{ResourceName: results[0].BucketName, Diffs: results[0].Diffs}
+	assert.True(t, info.HasDrift())
+}
+
+func TestDetectS3Drift_DetailedDiffs(t *testing.T) {
+	plan := models.S3Bucket{
+		Name:            "b-all",
+		ObjectOwnership: "BucketOwnerEnforced",
+		CORSRules:       []models.CORSRule{{AllowedMethods: []string{"GET"}, AllowedOrigins: []string{"*"}}},
+		Replication:     models.ReplicationConfig{Role: "arn:aws:iam::123456789012:role/replication"},
+		ObjectLock:      models.ObjectLockConfig{Enabled: true},
+	}
+	actual := &models.S3Bucket{Name: "b-all", ObjectOwnership: "ObjectWriter"}
+
+	res := detector.DetectS3Drift(plan, actual)
+	assert.Equal(t, [2]interface{}{"BucketOwnerEnforced", "ObjectWriter"}, res.Diffs["object_ownership"])
+	assert.Contains(t, res.Diffs, "cors_rules")
+	assert.Contains(t, res.Diffs, "replication")
+	assert.Contains(t, res.Diffs, "object_lock")
+	assert.NotContains(t, res.Diffs, "policy")
+}
+
+// Encryption KMS key and bucket key
+func TestDetectS3Drift_EncryptionKMSKey(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-kms", EncryptionAlgorithm: "aws:kms", KMSKeyID: "abcd", BucketKeyEnabled: true}
+	actual := &models.S3Bucket{
+		Name:                "b-kms",
+		EncryptionAlgorithm: "aws:kms",
+		KMSKeyID:            "arn:aws:kms:us-east-1:123456789012:key/abcd",
+		BucketKeyEnabled:    true,
+	}
+	assert.False(t, detector.DetectS3Drift(plan, actual).EncryptionDiff)
+
+	actual.KMSKeyID = "arn:aws:kms:us-east-1:123456789012:key/other"
+	assert.True(t, detector.DetectS3Drift(plan, actual).EncryptionDiff)
+}
+
+// Ownership, CORS, replication and object lock
+func TestDetectS3Drift_Ownership(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-own", ObjectOwnership: "BucketOwnerEnforced"}
+	actual := &models.S3Bucket{Name: "b-own", ObjectOwnership: "ObjectWriter"}
+	assert.True(t, detector.DetectS3Drift(plan, actual).OwnershipDiff)
+}
+
+func TestDetectS3Drift_CORS_OrderIndependent(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-cors", CORSRules: []models.CORSRule{
+		{AllowedMethods: []string{"GET", "HEAD"}, AllowedOrigins: []string{"https://a.example"}},
+		{AllowedMethods: []string{"PUT"}, AllowedOrigins: []string{"https://b.example"}},
+	}}
+	actual := &models.S3Bucket{Name: "b-cors", CORSRules: []models.CORSRule{
+		{AllowedMethods: []string{"PUT"}, AllowedOrigins: []string{"https://b.example"}},
+		{AllowedMethods: []string{"HEAD", "GET"}, AllowedOrigins: []string{"https://a.example"}},
+	}}
+	assert.False(t, detector.DetectS3Drift(plan, actual).CORSDiff)
+
+	actual.CORSRules[0].AllowedOrigins = []string{"*"}
+	assert.True(t, detector.DetectS3Drift(plan, actual).CORSDiff)
+}
+
+func TestDetectS3Drift_Replication(t *testing.T) {
+	rule := models.ReplicationRule{ID: "r1", Status: "Enabled", DestinationBucket: "arn:aws:s3:::replica"}
+	plan := models.S3Bucket{Name: "b-rep", Replication: models.ReplicationConfig{Role: "arn:role", Rules: []models.ReplicationRule{rule}}}
+	actual := &models.S3Bucket{Name: "b-rep", Replication: models.ReplicationConfig{Role: "arn:role", Rules: []models.ReplicationRule{rule}}}
+	assert.False(t, detector.DetectS3Drift(plan, actual).ReplicationDiff)
+
+	actual.Replication.Rules[0].Status = "Disabled"
+	assert.True(t, detector.DetectS3Drift(plan, actual).ReplicationDiff)
+}
+
+func TestDetectS3Drift_ObjectLock(t *testing.T) {
+	plan := models.S3Bucket{Name: "b-lock", ObjectLock: models.ObjectLockConfig{Enabled: true, Mode: "COMPLIANCE", Years: 1}}
+	actual := &models.S3Bucket{Name: "b-lock", ObjectLock: models.ObjectLockConfig{Enabled: true, Mode: "GOVERNANCE", Years: 1}}
+	assert.True(t, detector.DetectS3Drift(plan, actual).ObjectLockDiff)
+
+	results := detector.DetectAllS3Drift([]models.S3Bucket{plan}, []models.S3Bucket{*actual})
+	assert.Len(t, results, 1)
+}
+
+func TestS3DriftDetector_FetchErrorIsUnknown(t *testing.T) {
+	plans := []models.S3Bucket{{Name: "ok"}, {Name: "denied", Id: "aws_s3_bucket.denied"}, {Name: "gone", Id: "aws_s3_bucket.gone"}}
+	live := &models.S3LiveState{
+		Buckets: []models.S3Bucket{{Name: "ok"}},
+		Errors: []models.FetchError{
+			{Service: "s3", ResourceType: "aws_s3_bucket", Resource: "denied", Operation: "GetBucketAcl", ErrorCode: "AccessDenied"},
+		},
+	}
+
+	det := detector.NewS3DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, live)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	byName := make(map[string]detector.DriftResult)
+	for _, r := range results {
+		byName[r.BucketName] = r
+	}
+	assert.True(t, byName["denied"].Unknown)
+	assert.False(t, byName["denied"].Missing)
+	assert.True(t, byName["gone"].Missing)
+	assert.False(t, byName["gone"].Unknown)
+
+	// Addresses survive the Unknown rewrite
+	assert.Equal(t, "aws_s3_bucket.denied", byName["denied"].TerraformAddress)
+	assert.Equal(t, "aws_s3_bucket.gone", byName["gone"].TerraformAddress)
+}
+
+func TestS3DriftDetector_AcceptsBucketSlice(t *testing.T) {
+	det := detector.NewS3DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift([]models.S3Bucket{{Name: "b"}}, []models.S3Bucket{{Name: "b"}})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestUnfetchedLiveState_ReportsAllPlannedAsUnknown(t *testing.T) {
+	plans := []models.S3Bucket{{Name: "a", Id: "aws_s3_bucket.a"}, {Name: "b", Id: "aws_s3_bucket.b"}}
+	live, ok := detector.UnfetchedLiveState(plans, context.Canceled)
+	require.True(t, ok)
+	assert.Len(t, detector.FetchErrors(live), 2)
+
+	det := detector.NewS3DriftDetector(sdkaws.Config{})
+	results, err := det.DetectDrift(plans, live)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.True(t, r.Unknown)
+		assert.False(t, r.Missing)
+		assert.Equal(t, "aws_s3_bucket."+r.BucketName, r.TerraformAddress)
+	}
+}
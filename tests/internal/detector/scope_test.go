@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceScope_S3MatchesByName(t *testing.T) {
+	plan := []models.S3Bucket{{Name: "logs"}, {Name: "data", VersioningEnabled: true}}
+	live := models.S3Bucket{Name: "data"}
+
+	scopedPlan, scopedLive, ok := detector.ResourceScope(plan, live)
+	require.True(t, ok)
+	assert.Equal(t, []models.S3Bucket{plan[1]}, scopedPlan)
+	assert.Equal(t, &models.S3LiveState{Buckets: []models.S3Bucket{live}}, scopedLive)
+
+	// The scoped comparison finds the same drift as the full one
+	results, err := detector.NewS3DriftDetector(sdkaws.Config{}).DetectDrift(scopedPlan, scopedLive)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "data", results[0].BucketName)
+
+	_, _, ok = detector.ResourceScope(plan, models.S3Bucket{Name: "other"})
+	assert.False(t, ok)
+}
+
+func TestResourceScope_EC2MatchesByInstanceIDOnly(t *testing.T) {
+	plan := []models.EC2Instance{
+		{InstanceID: "i-1", Tags: map[string]string{"Name": "web"}},
+		{Tags: map[string]string{"Name": "api"}},
+	}
+
+	scopedPlan, _, ok := detector.ResourceScope(plan, models.EC2Instance{InstanceID: "i-1", Tags: map[string]string{"Name": "web"}})
+	require.True(t, ok)
+	assert.Equal(t, []models.EC2Instance{plan[0]}, scopedPlan)
+
+	// Name tag matches depend on the other instances
+	_, _, ok = detector.ResourceScope(plan, models.EC2Instance{InstanceID: "i-2", Tags: map[string]string{"Name": "api"}})
+	assert.False(t, ok)
+}
+
+func TestResourceScope_IAMMatchesByTypeAndName(t *testing.T) {
+	plan := &models.IAMPlanResources{
+		Roles: []models.IAMRole{{RoleName: "app"}},
+		Users: []models.IAMUser{{UserName: "deploy"}},
+	}
+
+	scopedPlan, scopedLive, ok := detector.ResourceScope(plan, models.IAMUser{UserName: "deploy"})
+	require.True(t, ok)
+	assert.Equal(t, &models.IAMPlanResources{Users: plan.Users}, scopedPlan)
+	assert.Equal(t, &models.IAMLiveState{Users: []models.IAMUser{{UserName: "deploy"}}}, scopedLive)
+
+	// A user does not match a role of the same name
+	_, _, ok = detector.ResourceScope(plan, models.IAMUser{UserName: "app"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,131 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+func resourceIDs(resources []detector.UnmanagedResource) []string {
+	ids := make([]string, len(resources))
+	for i, r := range resources {
+		ids[i] = r.ResourceID
+	}
+	return ids
+}
+
+func TestFindUnmanaged_S3(t *testing.T) {
+	plan := []models.S3Bucket{{Name: "managed"}}
+	live := &models.S3LiveState{Buckets: []models.S3Bucket{
+		{Name: "managed"},
+		{Name: "shadow-b"},
+		{Name: "shadow-a", Tags: map[string]string{"Owner": "alice"}},
+	}}
+
+	found, err := detector.FindUnmanaged(plan, live, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shadow-a", "shadow-b"}, resourceIDs(found))
+	assert.Equal(t, "aws_s3_bucket", found[0].ResourceType)
+	assert.Equal(t, "alice", found[0].Tags["Owner"])
+}
+
+func TestFindUnmanaged_EC2MatchesByIDOrName(t *testing.T) {
+	plan := []models.EC2Instance{
+		{InstanceID: "i-1"},
+		{Tags: map[string]string{"Name": "web"}},
+	}
+	live := &models.EC2LiveState{Instances: []models.EC2Instance{
+		{InstanceID: "i-1", State: "running"},
+		{InstanceID: "i-2", State: "running", Tags: map[string]string{"Name": "web"}},
+		{InstanceID: "i-3", State: "stopped", Tags: map[string]string{"Name": "bastion"}},
+		{InstanceID: "i-4", State: "terminated"},
+	}}
+
+	found, err := detector.FindUnmanaged(plan, live, nil)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "i-3", found[0].ResourceID)
+	assert.Equal(t, "bastion", found[0].ResourceName)
+}
+
+func TestFindUnmanaged_IAMSkipsServiceLinkedRoles(t *testing.T) {
+	plan := &models.IAMPlanResources{
+		Roles:    []models.IAMRole{{RoleName: "app"}},
+		Policies: []models.IAMPolicy{{PolicyName: "app-policy"}},
+	}
+	live := &models.IAMLiveState{
+		Roles: []models.IAMRole{
+			{RoleName: "app", Path: "/"},
+			{RoleName: "manual-admin", Path: "/"},
+			{RoleName: "AWSServiceRoleForECS", Path: "/aws-service-role/ecs.amazonaws.com/"},
+		},
+		Users:            []models.IAMUser{{UserName: "bob"}},
+		Policies:         []models.IAMPolicy{{PolicyName: "app-policy"}},
+		Groups:           []models.IAMGroup{{GroupName: "ops"}},
+		InstanceProfiles: []models.IAMInstanceProfile{{InstanceProfileName: "legacy"}},
+	}
+
+	found, err := detector.FindUnmanaged(plan, live, nil)
+	require.NoError(t, err)
+
+	types := make(map[string]string, len(found))
+	for _, r := range found {
+		types[r.ResourceID] = r.ResourceType
+	}
+	assert.Equal(t, map[string]string{
+		"ops":          "aws_iam_group",
+		"legacy":       "aws_iam_instance_profile",
+		"manual-admin": "aws_iam_role",
+		"bob":          "aws_iam_user",
+	}, types)
+}
+
+func TestFindUnmanaged_Exclusions(t *testing.T) {
+	live := &models.S3LiveState{Buckets: []models.S3Bucket{
+		{Name: "cdk-assets-123"},
+		{Name: "logs", Tags: map[string]string{"ManagedBy": "cloudformation"}},
+		{Name: "data", Tags: map[string]string{"ManagedBy": "manual"}},
+		{Name: "scratch"},
+	}}
+	exclusions := []detector.Exclusion{
+		{Name: "cdk-*"},
+		{Tag: "ManagedBy=cloud*"},
+		{ResourceType: "aws_instance", Name: "scratch"},
+	}
+
+	found, err := detector.FindUnmanaged([]models.S3Bucket{}, live, exclusions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data", "scratch"}, resourceIDs(found))
+}
+
+func TestFindUnmanaged_TypeMismatch(t *testing.T) {
+	_, err := detector.FindUnmanaged([]models.S3Bucket{}, &models.EC2LiveState{}, nil)
+	assert.Error(t, err)
+}
+
+func TestParseExclusion(t *testing.T) {
+	e, err := detector.ParseExclusion("name:tmp-*")
+	require.NoError(t, err)
+	assert.Equal(t, detector.Exclusion{Name: "tmp-*"}, e)
+
+	e, err = detector.ParseExclusion("tag:Owner=platform")
+	require.NoError(t, err)
+	assert.Equal(t, detector.Exclusion{Tag: "Owner=platform"}, e)
+	assert.True(t, e.Matches(detector.UnmanagedResource{ResourceID: "x", Tags: map[string]string{"Owner": "platform"}}))
+	assert.False(t, e.Matches(detector.UnmanagedResource{ResourceID: "x", Tags: map[string]string{"Owner": "data"}}))
+
+	for _, bad := range []string{"tmp-*", "name:", "owner:x", "name:[", "tag:k=["} {
+		_, err := detector.ParseExclusion(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestExclusion_EmptyMatchesNothing(t *testing.T) {
+	e := detector.Exclusion{ResourceType: "aws_s3_bucket"}
+	assert.False(t, e.Matches(detector.UnmanagedResource{ResourceType: "aws_s3_bucket", ResourceID: "b"}))
+	assert.Error(t, e.Validate())
+}
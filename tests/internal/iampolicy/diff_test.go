@@ -0,0 +1,106 @@
+package iampolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/iampolicy"
+)
+
+func TestDiffDocuments_Equivalent(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Version":"2012-10-17","Statement":[{"Sid":"A","Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"}]}`,
+		`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"S3:GetObject","Resource":["*"]}}`,
+	)
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+}
+
+func TestDiffDocuments_ModifiedStatementEntries(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],"Resource":"arn:aws:s3:::b/*"}]}`,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*","s3:ListBucket"],"Resource":"arn:aws:s3:::b/*"}]}`,
+	)
+	require.NoError(t, err)
+	require.Len(t, diff.Statements, 1)
+
+	c := diff.Statements[0]
+	assert.Equal(t, iampolicy.StatementModified, c.Kind)
+	assert.Equal(t, []iampolicy.Entry{{Element: "Action", Value: "s3:GetObject"}}, c.Removed)
+	assert.Equal(t, []iampolicy.Entry{{Element: "Action", Value: "s3:*", Wildcard: true}}, c.Added)
+}
+
+func TestDiffDocuments_PairsBySid(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Statement":[
+			{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::a/*"},
+			{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::a/*"}
+		]}`,
+		`{"Statement":[
+			{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/*"},
+			{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::a/*"}
+		]}`,
+	)
+	require.NoError(t, err)
+	require.Len(t, diff.Statements, 1)
+	assert.Equal(t, "Read", diff.Statements[0].Sid)
+	assert.Equal(t, []iampolicy.Entry{{Element: "Resource", Value: "arn:aws:s3:::a/*", Wildcard: true}}, diff.Statements[0].Removed)
+	assert.Equal(t, []iampolicy.Entry{{Element: "Resource", Value: "arn:aws:s3:::b/*", Wildcard: true}}, diff.Statements[0].Added)
+}
+
+func TestDiffDocuments_AddedAndRemovedStatements(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Statement":[{"Effect":"Deny","Action":"s3:DeleteBucket","Resource":"*"}]}`,
+		`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sts:AssumeRole","Condition":{"StringEquals":{"sts:ExternalId":"x"}}}]}`,
+	)
+	require.NoError(t, err)
+	require.Len(t, diff.Statements, 2)
+
+	removed, added := diff.Statements[0], diff.Statements[1]
+	assert.Equal(t, iampolicy.StatementRemoved, removed.Kind)
+	assert.Equal(t, "Deny", removed.ExpectedEffect)
+	assert.Len(t, removed.Removed, 2)
+
+	assert.Equal(t, iampolicy.StatementAdded, added.Kind)
+	assert.Equal(t, "Allow", added.ActualEffect)
+	assert.Equal(t, []iampolicy.Entry{
+		{Element: "Principal.AWS", Value: "*", Wildcard: true},
+		{Element: "Action", Value: "sts:AssumeRole"},
+		{Element: "Condition.StringEquals.sts:ExternalId", Value: "x"},
+	}, added.Added)
+}
+
+func TestDiffDocuments_EffectChange(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Statement":[{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+		`{"Statement":[{"Effect":"Allow","Action":"iam:*","Resource":"*"}]}`,
+	)
+	require.NoError(t, err)
+	require.Len(t, diff.Statements, 1)
+	assert.Equal(t, iampolicy.StatementModified, diff.Statements[0].Kind)
+	assert.Equal(t, "Deny", diff.Statements[0].ExpectedEffect)
+	assert.Equal(t, "Allow", diff.Statements[0].ActualEffect)
+
+	removed, added := diff.Lines()
+	assert.Equal(t, []string{"Effect Deny"}, removed)
+	assert.Equal(t, []string{"Effect Allow"}, added)
+}
+
+func TestDiff_Lines(t *testing.T) {
+	diff, err := iampolicy.DiffDocuments(
+		`{"Version":"2008-10-17","Statement":[{"Sid":"Admin","Effect":"Allow","Action":"ec2:Describe*","Resource":"*"}]}`,
+		`{"Version":"2012-10-17","Statement":[{"Sid":"Admin","Effect":"Allow","Action":"*","Resource":"*"}]}`,
+	)
+	require.NoError(t, err)
+
+	removed, added := diff.Lines()
+	assert.Equal(t, []string{"Version 2008-10-17", "Admin: Allow Action ec2:Describe* (wildcard)"}, removed)
+	assert.Equal(t, []string{"Version 2012-10-17", "Admin: Allow Action * (wildcard)"}, added)
+}
+
+func TestDiffDocuments_InvalidJSON(t *testing.T) {
+	_, err := iampolicy.DiffDocuments(`{"Statement":[]}`, `not json`)
+	assert.Error(t, err)
+}
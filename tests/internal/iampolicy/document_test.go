@@ -0,0 +1,109 @@
+package iampolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/iampolicy"
+)
+
+func TestParse_NormalizesStringsAndArrays(t *testing.T) {
+	doc, err := iampolicy.Parse(`{
+		"Version": "2012-10-17",
+		"Statement": {
+			"Effect": "Allow",
+			"Principal": "*",
+			"Action": "s3:GetObject",
+			"Resource": ["arn:aws:s3:::b/*", "arn:aws:s3:::b/*"],
+			"Condition": {"Bool": {"aws:SecureTransport": true}}
+		}
+	}`)
+	require.NoError(t, err)
+	require.Len(t, doc.Statements, 1)
+
+	s := doc.Statements[0]
+	assert.Equal(t, map[string][]string{"AWS": {"*"}}, s.Principal)
+	assert.Equal(t, []string{"s3:GetObject"}, s.Action)
+	assert.Equal(t, []string{"arn:aws:s3:::b/*"}, s.Resource)
+	assert.Equal(t, map[string]map[string][]string{"Bool": {"aws:SecureTransport": {"true"}}}, s.Condition)
+}
+
+func TestParse_InvalidJSON(t *testing.T) {
+	_, err := iampolicy.Parse(`{"Statement":`)
+	assert.Error(t, err)
+}
+
+func TestEqual_Semantic(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{
+			name: "formatting",
+			a:    `{"Version":"2012-10-17","Statement":[]}`,
+			b:    `{ "Version" : "2012-10-17", "Statement" : [] }`,
+			want: true,
+		},
+		{
+			name: "string versus array",
+			a:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}}`,
+			want: true,
+		},
+		{
+			name: "statement and value order",
+			a: `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"},
+				{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+			b: `{"Version":"2012-10-17","Statement":[
+				{"Effect":"Deny","Action":"iam:*","Resource":"*"},
+				{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject"],"Resource":"*"}]}`,
+			want: true,
+		},
+		{
+			name: "sid and action case ignored",
+			a:    `{"Version":"2012-10-17","Statement":[{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"S3:getobject","Resource":"*"}]}`,
+			want: true,
+		},
+		{
+			name: "wildcard principal forms",
+			a:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"sts:AssumeRole"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":"sts:AssumeRole"}]}`,
+			want: true,
+		},
+		{
+			name: "added action",
+			a:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:*"],"Resource":"*"}]}`,
+			want: false,
+		},
+		{
+			name: "different principal",
+			a:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+			want: false,
+		},
+		{
+			name: "duplicated statement",
+			a:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			want: false,
+		},
+		{
+			name: "unparseable",
+			a:    `not json`,
+			b:    `{"Version":"2012-10-17","Statement":[]}`,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, iampolicy.Equal(tt.a, tt.b))
+			assert.Equal(t, tt.want, iampolicy.Equal(tt.b, tt.a))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package importgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/importgen"
+	"github.com/inayathulla/cloudrift/internal/models"
+)
+
+func TestResourceName(t *testing.T) {
+	tests := map[string]string{
+		"my-bucket":        "my_bucket",
+		"My-Bucket.logs":   "my_bucket_logs",
+		"123-data":         "r_123_data",
+		"--weird__name--":  "weird_name",
+		"i-0abc123":        "i_0abc123",
+		"!!!":              "resource",
+		"AdminRole+Access": "adminrole_access",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, importgen.ResourceName(in), in)
+	}
+}
+
+func TestGenerator_ImportBlocks(t *testing.T) {
+	live := &models.S3LiveState{Buckets: []models.S3Bucket{{Name: "shadow-bucket"}, {Name: "shadow.bucket"}}}
+	unmanaged, err := detector.FindUnmanaged([]models.S3Bucket{}, live, nil)
+	require.NoError(t, err)
+
+	gen := importgen.New(false)
+	require.NoError(t, gen.Add(unmanaged, live))
+	assert.Equal(t, 2, gen.Count())
+
+	// Both names normalize to shadow_bucket, so the second gets a suffix
+	assert.Equal(t, `import {
+  to = aws_s3_bucket.shadow_bucket
+  id = "shadow-bucket"
+}
+
+import {
+  to = aws_s3_bucket.shadow_bucket_2
+  id = "shadow.bucket"
+}
+`, string(gen.Bytes()))
+}
+
+func TestGenerator_Skeletons(t *testing.T) {
+	live := &models.EC2LiveState{Instances: []models.EC2Instance{{
+		InstanceID:         "i-0abc",
+		State:              "running",
+		AMI:                "ami-123",
+		InstanceType:       "t3.micro",
+		SecurityGroupIDs:   []string{"sg-1"},
+		IAMInstanceProfile: "arn:aws:iam::123456789012:instance-profile/app/bastion-profile",
+		Monitoring:         true,
+		Tags:               map[string]string{"Name": "bastion", "aws:cloudformation:stack-name": "x"},
+	}}}
+	unmanaged, err := detector.FindUnmanaged([]models.EC2Instance{}, live, nil)
+	require.NoError(t, err)
+
+	gen := importgen.New(true)
+	require.NoError(t, gen.Add(unmanaged, live))
+	assert.Equal(t, `import {
+  to = aws_instance.bastion
+  id = "i-0abc"
+}
+
+resource "aws_instance" "bastion" {
+  ami                    = "ami-123"
+  instance_type          = "t3.micro"
+  vpc_security_group_ids = ["sg-1"]
+  iam_instance_profile   = "bastion-profile"
+  monitoring             = true
+  tags = {
+    Name = "bastion"
+  }
+}
+`, string(gen.Bytes()))
+}
+
+func TestGenerator_IAM(t *testing.T) {
+	live := &models.IAMLiveState{
+		Roles: []models.IAMRole{{
+			RoleName:           "ci-deploy",
+			Path:               "/",
+			AssumeRolePolicy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"${account}"},"Action":"sts:AssumeRole"}]}`,
+			MaxSessionDuration: 3600,
+		}},
+		Policies: []models.IAMPolicy{{PolicyName: "deploy", Arn: "arn:aws:iam::123456789012:policy/deploy"}},
+	}
+	unmanaged, err := detector.FindUnmanaged(&models.IAMPlanResources{}, live, nil)
+	require.NoError(t, err)
+
+	gen := importgen.New(true)
+	require.NoError(t, gen.Add(unmanaged, live))
+	out := string(gen.Bytes())
+
+	// Policies are imported by ARN, roles by name
+	assert.Contains(t, out, `id = "arn:aws:iam::123456789012:policy/deploy"`)
+	assert.Contains(t, out, `id = "ci-deploy"`)
+	assert.Contains(t, out, `resource "aws_iam_role" "ci_deploy"`)
+	assert.Contains(t, out, `max_session_duration = 3600`)
+	// Interpolation sequences in policy documents are escaped
+	assert.Contains(t, out, `$${account}`)
+}
+
+func TestGenerator_UnknownResource(t *testing.T) {
+	gen := importgen.New(false)
+	err := gen.Add([]detector.UnmanagedResource{{ResourceType: "aws_s3_bucket", ResourceID: "gone"}}, &models.S3LiveState{})
+	assert.Error(t, err)
+}
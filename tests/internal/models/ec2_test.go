@@ -0,0 +1,14 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceProfileName(t *testing.T) {
+	assert.Equal(t, "app", models.InstanceProfileName("arn:aws:iam::123:instance-profile/app"))
+	assert.Equal(t, "app", models.InstanceProfileName("arn:aws:iam::123:instance-profile/team/app"))
+	assert.Equal(t, "app", models.InstanceProfileName("app"))
+}
@@ -77,6 +77,15 @@ func TestIAMPlanResources_TotalCount(t *testing.T) {
 	assert.Equal(t, 7, pr.TotalCount())
 }
 
+func TestIAMPlanResources_InstanceProfiles(t *testing.T) {
+	pr := models.IAMPlanResources{
+		Roles:            []models.IAMRole{{RoleName: "r1"}},
+		InstanceProfiles: []models.IAMInstanceProfile{{InstanceProfileName: "ip1"}},
+	}
+	assert.Equal(t, 2, pr.TotalCount())
+	assert.Equal(t, []string{"r1", "ip1"}, pr.Names())
+}
+
 func TestIAMPlanResources_TotalCount_Empty(t *testing.T) {
 	pr := models.IAMPlanResources{}
 	assert.Equal(t, 0, pr.TotalCount())
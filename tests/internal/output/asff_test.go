@@ -0,0 +1,187 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type asffDoc struct {
+	Findings []struct {
+		SchemaVersion string
+		Id            string
+		ProductArn    string
+		GeneratorId   string
+		AwsAccountId  string
+		Region        string
+		Types         []string
+		CreatedAt     string
+		Severity      struct{ Label, Original string }
+		Title         string
+		Description   string
+		Remediation   *struct{ Recommendation struct{ Text string } }
+		ProductFields map[string]string
+		Resources     []struct{ Type, Id, Partition, Region string }
+		Compliance    *struct {
+			Status              string
+			RelatedRequirements []string
+		}
+		RecordState string
+	}
+}
+
+// createTestScanResultForFindings links the policy findings to drift
+// entries so the AWS identifiers of their resources are known.
+func createTestScanResultForFindings() output.ScanResult {
+	result := createTestScanResultForReport()
+	result.Drifts = append(result.Drifts,
+		detector.DriftInfo{ResourceID: "data-bucket", ResourceType: "aws_s3_bucket", ResourceName: "data-bucket", ResourceAddress: "aws_s3_bucket.data"},
+		detector.DriftInfo{ResourceID: "logs-bucket", ResourceType: "aws_s3_bucket", ResourceName: "logs-bucket", ResourceAddress: "aws_s3_bucket.logs"},
+	)
+	return result
+}
+
+func formatASFF(t *testing.T, result output.ScanResult) (string, asffDoc) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, output.NewASFFFormatter().Format(&buf, result))
+
+	var doc asffDoc
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	return buf.String(), doc
+}
+
+func TestASFFFormatter_Golden(t *testing.T) {
+	out, _ := formatASFF(t, createTestScanResultForFindings())
+	assertGolden(t, "report.golden.asff.json", []byte(out))
+}
+
+func TestASFFFormatter_Findings(t *testing.T) {
+	_, doc := formatASFF(t, createTestScanResultForFindings())
+
+	// 2 drift (drifted, missing; unknown is skipped), 2 violations, 1 warning
+	require.Len(t, doc.Findings, 5)
+
+	for _, f := range doc.Findings {
+		assert.Equal(t, "2018-10-08", f.SchemaVersion)
+		assert.Equal(t, "arn:aws:securityhub:us-east-1:123456789012:product/123456789012/default", f.ProductArn)
+		assert.Equal(t, "123456789012", f.AwsAccountId)
+		assert.Equal(t, "us-east-1", f.Region)
+		assert.Equal(t, "2024-01-15T10:30:00Z", f.CreatedAt)
+		assert.Equal(t, "ACTIVE", f.RecordState)
+		assert.True(t, strings.HasPrefix(f.Id, "cloudrift/123456789012/us-east-1/"), f.Id)
+	}
+
+	drifted := doc.Findings[0]
+	assert.Equal(t, "cloudrift/DRIFT002", drifted.GeneratorId)
+	assert.Equal(t, "MEDIUM", drifted.Severity.Label)
+	assert.Contains(t, drifted.Description, "versioning_enabled: expected true, actual false")
+	assert.Equal(t, "AwsS3Bucket", drifted.Resources[0].Type)
+	assert.Equal(t, "arn:aws:s3:::my-bucket", drifted.Resources[0].Id)
+	assert.Nil(t, drifted.Compliance)
+	assert.Equal(t, "infra/s3.tf:12", drifted.ProductFields["cloudrift/Location"])
+
+	missing := doc.Findings[1]
+	assert.Equal(t, "cloudrift/DRIFT001", missing.GeneratorId)
+	assert.Equal(t, "CRITICAL", missing.Severity.Label)
+
+	critical := doc.Findings[2]
+	assert.Equal(t, "cloudrift/S3-009", critical.GeneratorId)
+	assert.Equal(t, "CRITICAL", critical.Severity.Label)
+	assert.Equal(t, "arn:aws:s3:::logs-bucket", critical.Resources[0].Id)
+	require.NotNil(t, critical.Compliance)
+	assert.Equal(t, "FAILED", critical.Compliance.Status)
+
+	high := doc.Findings[3]
+	assert.Equal(t, "HIGH", high.Severity.Label)
+	assert.Equal(t, []string{"GDPR", "HIPAA", "ISO 27001", "PCI DSS", "SOC 2"}, high.Compliance.RelatedRequirements)
+	assert.Equal(t, []string{"Software and Configuration Checks/Industry and Regulatory Standards"}, high.Types)
+	require.NotNil(t, high.Remediation)
+	assert.Equal(t, "Add server_side_encryption_configuration", high.Remediation.Recommendation.Text)
+
+	warning := doc.Findings[4]
+	assert.Equal(t, "LOW", warning.Severity.Label)
+	assert.Equal(t, "WARNING", warning.Compliance.Status)
+}
+
+func TestASFFFormatter_DeterministicIDs(t *testing.T) {
+	_, first := formatASFF(t, createTestScanResultForFindings())
+
+	// A later scan with a different timestamp and message text keeps the IDs.
+	rescan := createTestScanResultForFindings()
+	rescan.Timestamp = "2024-02-01T08:00:00Z"
+	rescan.PolicyResult.Violations[0].Message = "reworded"
+	_, second := formatASFF(t, rescan)
+
+	require.Len(t, second.Findings, len(first.Findings))
+	ids := make(map[string]bool)
+	for i := range first.Findings {
+		assert.Equal(t, first.Findings[i].Id, second.Findings[i].Id)
+		ids[first.Findings[i].Id] = true
+	}
+	assert.Len(t, ids, len(first.Findings), "IDs must be unique")
+
+	// Another account gets different IDs.
+	other := createTestScanResultForFindings()
+	other.AccountID = "210987654321"
+	_, third := formatASFF(t, other)
+	assert.NotEqual(t, first.Findings[0].Id, third.Findings[0].Id)
+}
+
+func TestASFFFormatter_DuplicateFindingsGetDistinctIDs(t *testing.T) {
+	result := createTestScanResultForFindings()
+	v := result.PolicyResult.Violations[0]
+	v.Message = "second message for the same policy and resource"
+	result.PolicyResult.Violations = append(result.PolicyResult.Violations, v)
+
+	_, doc := formatASFF(t, result)
+	ids := make(map[string]bool)
+	for _, f := range doc.Findings {
+		assert.False(t, ids[f.Id], "duplicate ID %s", f.Id)
+		ids[f.Id] = true
+	}
+}
+
+func TestASFFFormatter_ResourceARNs(t *testing.T) {
+	result := output.ScanResult{
+		Service:   "EC2",
+		AccountID: "123456789012",
+		Region:    "us-gov-west-1",
+		Timestamp: "2024-01-15T10:30:00Z",
+		Drifts: []detector.DriftInfo{
+			{ResourceID: "i-0abc", ResourceType: "aws_instance", ResourceAddress: "aws_instance.web", Missing: true, Severity: "critical"},
+			{ResourceID: "web-2", ResourceType: "aws_instance", ResourceAddress: "aws_instance.web2", Missing: true, Severity: "critical"},
+			{ResourceID: "deploy", ResourceType: "aws_iam_role", ResourceAddress: "aws_iam_role.deploy", Missing: true, Severity: "critical"},
+		},
+	}
+	_, doc := formatASFF(t, result)
+	require.Len(t, doc.Findings, 3)
+
+	assert.Equal(t, "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-0abc", doc.Findings[0].Resources[0].Id)
+	assert.Equal(t, "aws-us-gov", doc.Findings[0].Resources[0].Partition)
+	assert.Equal(t, "web-2", doc.Findings[1].Resources[0].Id, "name tags are not instance IDs")
+	assert.Equal(t, "AwsIamRole", doc.Findings[2].Resources[0].Type)
+	assert.Equal(t, "deploy", doc.Findings[2].Resources[0].Id, "IAM paths are unknown")
+}
+
+func TestASFFFormatter_NoFindings(t *testing.T) {
+	out, doc := formatASFF(t, output.ScanResult{Service: "S3"})
+	assert.Empty(t, doc.Findings)
+	assert.Contains(t, out, `"Findings": []`)
+}
+
+func TestASFFFormatter_Name(t *testing.T) {
+	formatter := output.NewASFFFormatter()
+	assert.Equal(t, "asff", formatter.Name())
+	assert.Equal(t, ".json", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatASFF)
+	require.True(t, ok)
+	assert.Equal(t, "asff", registered.Name())
+}
@@ -0,0 +1,177 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/output"
+)
+
+func formatConsole(t *testing.T, formatter *output.ConsoleFormatter, result output.ScanResult) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, result))
+	return buf.String()
+}
+
+// withDetails attaches the detector results for plan and live to result.
+func withDetails(t *testing.T, result output.ScanResult, det interface {
+	DetectDrift(plan, live interface{}) ([]detector.DriftResult, error)
+}, plan, live interface{}) output.ScanResult {
+	t.Helper()
+	results, err := det.DetectDrift(plan, live)
+	require.NoError(t, err)
+	result.Details = &output.ServiceDetails{Results: results, Plan: plan, Live: live}
+	return result
+}
+
+func createS3ConsoleResult(t *testing.T) output.ScanResult {
+	plan := []models.S3Bucket{
+		{
+			Id:                "aws_s3_bucket.logs",
+			Name:              "logs-bucket",
+			Acl:               "private",
+			Tags:              map[string]string{"Env": "prod", "Owner": "platform", "Team": "infra"},
+			VersioningEnabled: true,
+			LifecycleRules:    []models.LifecycleRuleSummary{{ID: "expire", Status: "Enabled", ExpirationDays: 90}},
+		},
+		{Id: "aws_s3_bucket.data", Name: "data-bucket", Acl: "private"},
+		{Id: "aws_s3_bucket.gone", Name: "gone-bucket", Acl: "private"},
+	}
+	live := &models.S3LiveState{
+		Buckets: []models.S3Bucket{
+			{
+				Name:           "logs-bucket",
+				Acl:            "public-read",
+				Tags:           map[string]string{"Env": "dev", "Team": "infra", "CostCenter": "42"},
+				LifecycleRules: []models.LifecycleRuleSummary{{ID: "expire", Status: "Enabled", ExpirationDays: 30}},
+			},
+			{Name: "data-bucket", Acl: "private"},
+		},
+		Errors: []models.FetchError{{Resource: "gone-bucket", ResourceType: "aws_s3_bucket", Operation: "GetBucketAcl", ErrorCode: "AccessDenied", Message: "access denied"}},
+	}
+	result := output.ScanResult{Service: "S3", TotalResources: 3, Errors: live.Errors}
+	return withDetails(t, result, detector.NewS3DriftDetector(aws.Config{}), plan, live)
+}
+
+func TestConsoleFormatter_S3Details_Golden(t *testing.T) {
+	result := createS3ConsoleResult(t)
+	result.PolicyResult = createTestScanResultForReport().PolicyResult
+
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assertGolden(t, "console.golden.s3.txt", []byte(out))
+}
+
+func TestConsoleFormatter_EC2Details_Golden(t *testing.T) {
+	plan := []models.EC2Instance{
+		{
+			InstanceID:       "i-0abc",
+			TerraformAddress: "aws_instance.web",
+			InstanceType:     "t3.micro",
+			AMI:              "ami-123",
+			SecurityGroupIDs: []string{"sg-1"},
+			Tags:             map[string]string{"Name": "web", "Env": "prod"},
+		},
+		{InstanceID: "i-0def", TerraformAddress: "aws_instance.api", InstanceType: "t3.small", AMI: "ami-456", Tags: map[string]string{"Name": "api"}},
+	}
+	live := &models.EC2LiveState{Instances: []models.EC2Instance{{
+		InstanceID:       "i-0abc",
+		InstanceType:     "t3.large",
+		AMI:              "ami-123",
+		SecurityGroupIDs: []string{"sg-1", "sg-2"},
+		Monitoring:       true,
+		Tags:             map[string]string{"Name": "web", "Env": "dev", "Patch": "weekly"},
+	}}}
+	result := withDetails(t, output.ScanResult{Service: "EC2", TotalResources: 2}, detector.NewEC2DriftDetector(aws.Config{}), plan, live)
+
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assertGolden(t, "console.golden.ec2.txt", []byte(out))
+}
+
+func TestConsoleFormatter_IAMDetails_Golden(t *testing.T) {
+	plan := &models.IAMPlanResources{Roles: []models.IAMRole{{
+		TerraformAddress:   "aws_iam_role.deploy",
+		RoleName:           "deploy",
+		Path:               "/",
+		AssumeRolePolicy:   `{"Version":"2012-10-17","Statement":[{"Sid":"EC2","Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`,
+		MaxSessionDuration: 3600,
+		InlinePolicies:     map[string]string{"s3": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::data/*"}]}`},
+	}}}
+	live := &models.IAMLiveState{Roles: []models.IAMRole{{
+		RoleName:           "deploy",
+		Path:               "/",
+		AssumeRolePolicy:   `{"Version":"2012-10-17","Statement":[{"Sid":"EC2","Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"},{"Effect":"Allow","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`,
+		MaxSessionDuration: 43200,
+		InlinePolicies: map[string]string{
+			"s3":    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::data/*"}]}`,
+			"debug": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+		},
+	}}}
+	result := withDetails(t, output.ScanResult{Service: "IAM", TotalResources: 1}, detector.NewIAMDriftDetector(aws.Config{}), plan, live)
+
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assertGolden(t, "console.golden.iam.txt", []byte(out))
+}
+
+func TestConsoleFormatter_GenericView(t *testing.T) {
+	// Without service details, drift is shown from the attribute diffs.
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, createTestScanResultForReport())
+
+	assert.Contains(t, out, "Drift detected!")
+	assert.Contains(t, out, "my-bucket (aws_s3_bucket)")
+	assert.Contains(t, out, "MISSING - Resource not found in AWS")
+	assert.Contains(t, out, `+ actual:   "false"`)
+	assert.Contains(t, out, "Live state unknown for 1 resources")
+	assert.Contains(t, out, "stray-bucket")
+	assert.Contains(t, out, "COMPLIANCE SUMMARY")
+}
+
+func TestConsoleFormatter_PolicySection(t *testing.T) {
+	result := createTestScanResultForReport()
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assert.Contains(t, out, "VIOLATIONS (2)")
+	assert.Contains(t, out, "[critical] S3-009")
+	assert.Contains(t, out, "WARNINGS (1)")
+
+	// Active frameworks are named in the compliance summary.
+	result.PolicyResult.ComplianceResult.ActiveFrameworks = []string{"hipaa", "soc2"}
+	out = formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assert.Contains(t, out, "COMPLIANCE SUMMARY (HIPAA, SOC2)")
+
+	// Without findings there is no policy section.
+	result.PolicyResult.Violations = nil
+	result.PolicyResult.Warnings = nil
+	out = formatConsole(t, &output.ConsoleFormatter{NoColor: true}, result)
+	assert.NotContains(t, out, "POLICY EVALUATION")
+}
+
+func TestConsoleFormatter_NoEmoji(t *testing.T) {
+	out := formatConsole(t, &output.ConsoleFormatter{NoColor: true, NoEmoji: true}, createS3ConsoleResult(t))
+
+	assert.Contains(t, out, "[!] Drift detected!")
+	assert.Contains(t, out, "* logs-bucket")
+	assert.Contains(t, out, "  ACL mismatch:")
+	for _, emoji := range []string{"⚠️", "🪣", "🔑", "🏷️", "❔", "✅"} {
+		assert.NotContains(t, out, emoji)
+	}
+}
+
+func TestConsoleFormatter_NoColor(t *testing.T) {
+	// Force terminal colors on so the test does not depend on stdout.
+	saved := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = saved }()
+
+	colored := formatConsole(t, &output.ConsoleFormatter{}, createS3ConsoleResult(t))
+	assert.Contains(t, colored, "\x1b[")
+
+	plain := formatConsole(t, &output.ConsoleFormatter{NoColor: true}, createS3ConsoleResult(t))
+	assert.NotContains(t, plain, "\x1b[")
+}
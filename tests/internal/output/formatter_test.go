@@ -3,11 +3,15 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -422,6 +426,129 @@ func TestSARIFFormatter_EmptyResult(t *testing.T) {
 	assert.Equal(t, "2.1.0", parsed["version"])
 }
 
+// Fetch error tests
+func createTestScanResultWithErrors() output.ScanResult {
+	result := createTestScanResult()
+	result.Drifts = append(result.Drifts, detector.DriftInfo{
+		ResourceID:   "denied-bucket",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "denied-bucket",
+		Unknown:      true,
+		Severity:     "info",
+	})
+	result.Errors = []models.FetchError{
+		{
+			Service:      "s3",
+			ResourceType: "aws_s3_bucket",
+			Resource:     "denied-bucket",
+			Operation:    "GetBucketAcl",
+			ErrorCode:    "AccessDenied",
+			Message:      "api error AccessDenied: Access Denied",
+		},
+	}
+	return result
+}
+
+func TestJSONFormatter_WithFetchErrors(t *testing.T) {
+	formatter := output.NewJSONFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithErrors()))
+
+	var parsed struct {
+		Drifts []map[string]interface{} `json:"drifts"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+
+	require.Len(t, parsed.Errors, 1)
+	assert.Equal(t, "denied-bucket", parsed.Errors[0]["resource"])
+	assert.Equal(t, "GetBucketAcl", parsed.Errors[0]["operation"])
+	assert.Equal(t, "AccessDenied", parsed.Errors[0]["error_code"])
+
+	require.Len(t, parsed.Drifts, 3)
+	assert.Equal(t, true, parsed.Drifts[2]["unknown"])
+	assert.Equal(t, false, parsed.Drifts[2]["missing"])
+}
+
+func TestJSONFormatter_WithoutFetchErrors_OmitsErrors(t *testing.T) {
+	formatter := output.NewJSONFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResult()))
+
+	assert.NotContains(t, buf.String(), `"errors"`)
+	assert.NotContains(t, buf.String(), `"unknown"`)
+}
+
+func TestSARIFFormatter_WithFetchErrors(t *testing.T) {
+	formatter := output.NewSARIFFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithErrors()))
+
+	assert.Contains(t, buf.String(), `"FETCH001"`)
+	assert.Contains(t, buf.String(), `"resource-state-unknown"`)
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				RuleID     string                 `json:"ruleId"`
+				Level      string                 `json:"level"`
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	var fetchResults int
+	for _, r := range doc.Runs[0].Results {
+		if r.RuleID == "DRIFT001" {
+			assert.NotEqual(t, "denied-bucket", r.Properties["resourceName"], "unknown resource must not be reported as missing")
+		}
+		if r.RuleID == "FETCH001" {
+			fetchResults++
+			assert.Equal(t, "warning", r.Level)
+			assert.Equal(t, "AccessDenied", r.Properties["errorCode"])
+		}
+	}
+	assert.Equal(t, 1, fetchResults)
+}
+
+func TestConsoleFormatter_WithFetchErrors(t *testing.T) {
+	formatter := output.NewConsoleFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithErrors()))
+
+	assert.Contains(t, buf.String(), "denied-bucket")
+	assert.Contains(t, buf.String(), "GetBucketAcl")
+}
+
+func TestSARIFFormatter_IncompleteScan(t *testing.T) {
+	formatter := output.NewSARIFFormatter()
+	result := createTestScanResultWithErrors()
+	result.Incomplete = true
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, result))
+	assert.Contains(t, buf.String(), `"executionSuccessful": false`)
+
+	var complete bytes.Buffer
+	require.NoError(t, formatter.Format(&complete, createTestScanResult()))
+	assert.NotContains(t, complete.String(), `"invocations"`)
+}
+
+func TestJSONFormatter_IncompleteScan(t *testing.T) {
+	formatter := output.NewJSONFormatter()
+	result := createTestScanResultWithErrors()
+	result.Incomplete = true
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, result))
+	assert.Contains(t, buf.String(), `"incomplete": true`)
+}
+
 // Compliance JSON Tests
 func TestJSONFormatter_WithCompliance(t *testing.T) {
 	formatter := output.NewJSONFormatter()
@@ -592,3 +719,291 @@ func TestSARIFFormatter_SeverityMapping(t *testing.T) {
 		})
 	}
 }
+
+func createTestScanResultWithUnmanaged() output.ScanResult {
+	result := createTestScanResult()
+	result.Unmanaged = []detector.UnmanagedResource{
+		{ResourceType: "aws_s3_bucket", ResourceID: "shadow-bucket", ResourceName: "shadow-bucket", Tags: map[string]string{"Owner": "alice"}},
+	}
+	return result
+}
+
+func TestJSONFormatter_WithUnmanaged(t *testing.T) {
+	formatter := output.NewJSONFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithUnmanaged()))
+
+	var parsed output.ScanResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	require.Len(t, parsed.Unmanaged, 1)
+	assert.Equal(t, "shadow-bucket", parsed.Unmanaged[0].ResourceID)
+	assert.Equal(t, "alice", parsed.Unmanaged[0].Tags["Owner"])
+
+	buf.Reset()
+	require.NoError(t, formatter.Format(&buf, createTestScanResult()))
+	assert.NotContains(t, buf.String(), `"unmanaged"`)
+}
+
+func TestSARIFFormatter_WithUnmanaged(t *testing.T) {
+	formatter := output.NewSARIFFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithUnmanaged()))
+
+	var doc struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID     string                 `json:"ruleId"`
+				RuleIndex  int                    `json:"ruleIndex"`
+				Level      string                 `json:"level"`
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	rules := doc.Runs[0].Tool.Driver.Rules
+	var unmanaged int
+	for _, r := range doc.Runs[0].Results {
+		if r.RuleID == "UNMANAGED001" {
+			unmanaged++
+			assert.Equal(t, "UNMANAGED001", rules[r.RuleIndex].ID)
+			assert.Equal(t, "warning", r.Level)
+			assert.Equal(t, "shadow-bucket", r.Properties["resourceId"])
+		}
+	}
+	assert.Equal(t, 1, unmanaged)
+}
+
+func TestConsoleFormatter_WithUnmanaged(t *testing.T) {
+	formatter := output.NewConsoleFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithUnmanaged()))
+
+	assert.Contains(t, buf.String(), "not managed by Terraform")
+	assert.Contains(t, buf.String(), "shadow-bucket")
+}
+
+func createTestScanResultWithRemediation() output.ScanResult {
+	result := createTestScanResult()
+	result.Remediation = []remediation.Patch{{
+		Address:      "aws_s3_bucket.logs",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "logs",
+		Changes:      []remediation.Change{{Attribute: "tags", Value: map[string]string{"Env": "dev"}}},
+	}}
+	return result
+}
+
+func TestRemediationFormatter(t *testing.T) {
+	formatter, ok := output.Get(output.FormatRemediation)
+	require.True(t, ok)
+	assert.Equal(t, "remediation", formatter.Name())
+	assert.Equal(t, ".tf", formatter.FileExtension())
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultWithRemediation()))
+	assert.Equal(t, `# aws_s3_bucket.logs
+resource "aws_s3_bucket" "logs" {
+  tags = {
+    Env = "dev"
+  }
+}
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, formatter.Format(&buf, createTestScanResult()))
+	assert.Empty(t, buf.String())
+}
+
+func TestJSONFormatter_WithRemediation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.NewJSONFormatter().Format(&buf, createTestScanResultWithRemediation()))
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	patches, ok := parsed["remediation"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, patches, 1)
+	patch := patches[0].(map[string]interface{})
+	assert.Equal(t, "aws_s3_bucket.logs", patch["address"])
+	changes := patch["changes"].([]interface{})
+	assert.Equal(t, "tags", changes[0].(map[string]interface{})["attribute"])
+}
+
+func TestSARIFFormatter_SourceLocations(t *testing.T) {
+	result := createTestScanResult()
+	result.Drifts[0].ResourceAddress = "aws_s3_bucket.my_bucket"
+	result.Drifts[0].Location = &tfconfig.Location{File: "infra/s3.tf", StartLine: 12, StartColumn: 1, EndLine: 20}
+	result.Drifts = append(result.Drifts, detector.DriftInfo{
+		ResourceID:   "denied",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "denied",
+		Unknown:      true,
+		Location:     &tfconfig.Location{File: "infra/s3.tf", StartLine: 30, StartColumn: 1, EndLine: 32},
+	})
+	result.Errors = []models.FetchError{{Service: "s3", ResourceType: "aws_s3_bucket", Resource: "denied", Operation: "GetBucketAcl"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.NewSARIFFormatter().Format(&buf, result))
+
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+							EndLine   int `json:"endLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	byRule := make(map[string][]string)
+	for _, r := range doc.Runs[0].Results {
+		loc := r.Locations[0].PhysicalLocation
+		if loc.Region != nil {
+			byRule[r.RuleID] = append(byRule[r.RuleID], fmt.Sprintf("%s:%d-%d", loc.ArtifactLocation.URI, loc.Region.StartLine, loc.Region.EndLine))
+		} else {
+			byRule[r.RuleID] = append(byRule[r.RuleID], loc.ArtifactLocation.URI)
+		}
+	}
+	assert.Equal(t, []string{"infra/s3.tf:12-20"}, byRule["DRIFT002"])
+	// Resources without a known location fall back to the state file
+	assert.Equal(t, []string{"terraform.tfstate"}, byRule["DRIFT001"])
+	// Fetch errors use the location of the resource's Unknown entry
+	assert.Equal(t, []string{"infra/s3.tf:30-32"}, byRule["FETCH001"])
+}
+
+func TestSARIFFormatter_PolicyFindings(t *testing.T) {
+	result := createTestScanResultWithCompliance()
+	result.PolicyResult.Violations[0].Location = &tfconfig.Location{File: "infra/s3.tf", StartLine: 3, StartColumn: 1, EndLine: 9}
+	result.PolicyResult.Warnings = []output.PolicyViolationOutput{{
+		PolicyID:        "TAG-001",
+		PolicyName:      "Required Tags",
+		Message:         "aws_s3_bucket.data is missing the Owner tag",
+		Severity:        "critical",
+		ResourceType:    "aws_s3_bucket",
+		ResourceAddress: "aws_s3_bucket.data",
+		Category:        "tagging",
+	}}
+	result.PolicyResult.Rules = []output.PolicyRuleOutput{
+		{ID: "S3-001", Name: "S3 Encryption Required", Severity: "high", Remediation: "Enable SSE", Category: "security", Frameworks: []string{"hipaa", "soc2"}},
+		{ID: "EC2-001", Name: "IMDSv2 Required", Severity: "medium", Category: "security"},
+	}
+
+	format := func() []byte {
+		var buf bytes.Buffer
+		require.NoError(t, output.NewSARIFFormatter().Format(&buf, result))
+		return buf.Bytes()
+	}
+	out := format()
+
+	var doc struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID            string                 `json:"id"`
+						Name          string                 `json:"name"`
+						Help          struct{ Text string }  `json:"help"`
+						DefaultConfig struct{ Level string } `json:"defaultConfiguration"`
+						Properties    map[string]interface{} `json:"properties"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID              string            `json:"ruleId"`
+				RuleIndex           int               `json:"ruleIndex"`
+				Level               string            `json:"level"`
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+				Locations           []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	run := doc.Runs[0]
+
+	// Every evaluated policy is a rule, plus rules for findings without one
+	var ids []string
+	for _, r := range run.Tool.Driver.Rules[5:] {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"EC2-001", "S3-001", "TAG-001"}, ids)
+
+	s3 := run.Tool.Driver.Rules[6]
+	assert.Equal(t, "S3 Encryption Required", s3.Name)
+	assert.Equal(t, "Enable SSE", s3.Help.Text)
+	assert.Equal(t, "error", s3.DefaultConfig.Level)
+	assert.Equal(t, "security", s3.Properties["category"])
+	assert.Equal(t, []interface{}{"hipaa", "soc2"}, s3.Properties["frameworks"])
+	assert.Equal(t, "8.0", s3.Properties["security-severity"])
+
+	var policyResults int
+	for _, r := range run.Results {
+		switch r.RuleID {
+		case "S3-001":
+			policyResults++
+			assert.Equal(t, "S3-001", run.Tool.Driver.Rules[r.RuleIndex].ID)
+			assert.Equal(t, "error", r.Level)
+			assert.Equal(t, "violation", r.Properties["kind"])
+			assert.Equal(t, "infra/s3.tf", r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+			assert.NotEmpty(t, r.PartialFingerprints["cloudriftPolicyFinding/v1"])
+		case "TAG-001":
+			policyResults++
+			assert.Equal(t, "TAG-001", run.Tool.Driver.Rules[r.RuleIndex].ID)
+			// Warnings are capped at warning level
+			assert.Equal(t, "warning", r.Level)
+			assert.Equal(t, "warning", r.Properties["kind"])
+		}
+	}
+	assert.Equal(t, 2, policyResults)
+
+	// Fingerprints are stable across runs
+	result.Timestamp = "2030-01-01T00:00:00Z"
+	result.ScanDuration = 1
+	assert.Equal(t, fingerprints(t, out), fingerprints(t, format()))
+}
+
+func fingerprints(t *testing.T, sarif []byte) []string {
+	t.Helper()
+	var doc struct {
+		Runs []struct {
+			Results []struct {
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(sarif, &doc))
+	var fps []string
+	for _, r := range doc.Runs[0].Results {
+		for _, fp := range r.PartialFingerprints {
+			fps = append(fps, fp)
+		}
+	}
+	return fps
+}
@@ -0,0 +1,184 @@
+package output
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites golden files instead of comparing against them:
+//
+//	go test ./tests/internal/output/ -update
+var update = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares got with testdata/<name>, or rewrites the file when
+// -update is set.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing; run with -update")
+	assert.Equal(t, string(want), string(got))
+}
+
+// createTestScanResultForReport covers every section of the document
+// formats: compliance, violations and warnings, drift of each kind, fetch
+// errors, unmanaged resources and remediation.
+func createTestScanResultForReport() output.ScanResult {
+	result := createTestScanResultWithCompliance()
+	result.Incomplete = true
+	result.Drifts[0].ResourceAddress = "aws_s3_bucket.my_bucket"
+	result.Drifts[0].Location = &tfconfig.Location{File: "infra/s3.tf", StartLine: 12, StartColumn: 1, EndLine: 20}
+	result.Drifts[0].Diffs["tags"] = [2]interface{}{
+		map[string]string{"Env": "prod", "Owner": "<platform>"},
+		map[string]string{"Env": "dev"},
+	}
+	result.Drifts[0].ExtraAttributes = map[string]interface{}{"acl": "public-read"}
+	result.Drifts = append(result.Drifts,
+		detector.DriftInfo{
+			ResourceID:      "role-x",
+			ResourceType:    "aws_iam_role",
+			ResourceName:    "role-x",
+			ResourceAddress: "aws_iam_role.x",
+			Unknown:         true,
+		},
+		detector.DriftInfo{
+			ResourceID:   "in-sync-bucket",
+			ResourceType: "aws_s3_bucket",
+			ResourceName: "in-sync-bucket",
+		},
+	)
+	result.Errors = []models.FetchError{{
+		Resource:     "role-x",
+		ResourceType: "aws_iam_role",
+		Operation:    "GetRole",
+		ErrorCode:    "AccessDenied",
+		Message:      "not authorized",
+	}}
+	result.Unmanaged = []detector.UnmanagedResource{{
+		ResourceID:   "stray-bucket",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "stray-bucket",
+	}}
+	result.PolicyResult.Violations = append(result.PolicyResult.Violations, output.PolicyViolationOutput{
+		PolicyID:        "S3-009",
+		PolicyName:      "S3 Public Access Block",
+		Message:         "Bucket <logs> allows public ACLs",
+		Severity:        "critical",
+		ResourceType:    "aws_s3_bucket",
+		ResourceAddress: "aws_s3_bucket.logs",
+		Category:        "security",
+		Frameworks:      []string{"cis"},
+		Location:        &tfconfig.Location{File: "infra/s3.tf", StartLine: 30, StartColumn: 1, EndLine: 36},
+	})
+	result.PolicyResult.Warnings = []output.PolicyViolationOutput{{
+		PolicyID:        "TAG-001",
+		PolicyName:      "Environment Tag",
+		Message:         "Resource should have an Environment tag",
+		Severity:        "low",
+		ResourceType:    "aws_s3_bucket",
+		ResourceAddress: "aws_s3_bucket.logs",
+		Category:        "tagging",
+	}}
+	result.Remediation = []remediation.Patch{{
+		Address:      "aws_s3_bucket.my_bucket",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "my_bucket",
+		Changes:      []remediation.Change{{Attribute: "tags", Value: map[string]string{"Env": "dev"}}},
+	}}
+	return result
+}
+
+func TestHTMLFormatter_Golden(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultForReport()))
+	assertGolden(t, "report.golden.html", buf.Bytes())
+}
+
+func TestHTMLFormatter_Golden_NoDrift(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+	result := output.ScanResult{
+		Service:        "EC2",
+		TotalResources: 3,
+		ScanDuration:   200,
+		Timestamp:      "2024-01-15T10:30:00Z",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, result))
+	assertGolden(t, "report-no-drift.golden.html", buf.Bytes())
+}
+
+func TestHTMLFormatter_SelfContained(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultForReport()))
+	html := buf.String()
+
+	assert.NotContains(t, html, "<link")
+	assert.NotContains(t, html, "src=")
+	assert.NotContains(t, html, "http://")
+	assert.NotContains(t, html, "https://")
+	assert.Contains(t, html, "<style>")
+	assert.Contains(t, html, "finding-search")
+}
+
+func TestHTMLFormatter_Content(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultForReport()))
+	html := buf.String()
+
+	// Compliance score and bars
+	assert.Contains(t, html, "98.0%")
+	assert.Contains(t, html, "width: 96.2%")
+	assert.Contains(t, html, "pci_dss")
+
+	// Findings sorted by severity, user input escaped
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("S3-009")), bytes.Index(buf.Bytes(), []byte("S3-001")))
+	assert.Contains(t, html, "Bucket &lt;logs&gt; allows public ACLs")
+	assert.NotContains(t, html, "<logs>")
+	assert.Contains(t, html, `data-kind="warning"`)
+	assert.Contains(t, html, "infra/s3.tf:30")
+
+	// Drift: in-sync resources are omitted
+	assert.Contains(t, html, "aws_s3_bucket.my_bucket")
+	assert.Contains(t, html, "missing-bucket (aws_s3_bucket)")
+	assert.Contains(t, html, "aws_iam_role.x")
+	assert.NotContains(t, html, "in-sync-bucket")
+	assert.Contains(t, html, "{Env=prod, Owner=&lt;platform&gt;}")
+}
+
+func TestHTMLFormatter_Name(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+	assert.Equal(t, "html", formatter.Name())
+}
+
+func TestHTMLFormatter_FileExtension(t *testing.T) {
+	formatter := output.NewHTMLFormatter()
+	assert.Equal(t, ".html", formatter.FileExtension())
+}
+
+func TestFormatRegistry_HTML(t *testing.T) {
+	formatter, ok := output.Get(output.FormatHTML)
+	require.True(t, ok)
+	assert.Equal(t, "html", formatter.Name())
+}
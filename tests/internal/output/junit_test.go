@@ -0,0 +1,148 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type junitDoc struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Errors   int `xml:"errors,attr"`
+	Suites   []struct {
+		Name     string `xml:"name,attr"`
+		Tests    int    `xml:"tests,attr"`
+		Failures int    `xml:"failures,attr"`
+		Cases    []struct {
+			Name      string `xml:"name,attr"`
+			ClassName string `xml:"classname,attr"`
+			File      string `xml:"file,attr"`
+			Line      int    `xml:"line,attr"`
+			Failure   *struct {
+				Message string `xml:"message,attr"`
+				Type    string `xml:"type,attr"`
+				Body    string `xml:",chardata"`
+			} `xml:"failure"`
+			Error     *struct{} `xml:"error"`
+			Skipped   *struct{} `xml:"skipped"`
+			SystemOut string    `xml:"system-out"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func createTestScanResultForJUnit() output.ScanResult {
+	result := createTestScanResultForReport()
+	result.PolicyResult.Rules = []output.PolicyRuleOutput{
+		{ID: "S3-001", Name: "S3 Encryption Required", Severity: "high"},
+		{ID: "S3-002", Name: "S3 Versioning", Severity: "medium"},
+		{ID: "S3-009", Name: "S3 Public Access Block", Severity: "critical"},
+		{ID: "TAG-001", Name: "Environment Tag", Severity: "low"},
+	}
+	return result
+}
+
+func formatJUnit(t *testing.T, result output.ScanResult) (string, junitDoc) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, output.NewJUnitFormatter().Format(&buf, result))
+
+	var doc junitDoc
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	return buf.String(), doc
+}
+
+func TestJUnitFormatter_Golden(t *testing.T) {
+	out, _ := formatJUnit(t, createTestScanResultForJUnit())
+	assertGolden(t, "report.golden.junit.xml", []byte(out))
+}
+
+func TestJUnitFormatter_DriftSuite(t *testing.T) {
+	_, doc := formatJUnit(t, createTestScanResultForJUnit())
+	require.Len(t, doc.Suites, 2)
+
+	drift := doc.Suites[0]
+	assert.Equal(t, "cloudrift.drift.s3", drift.Name)
+	require.Len(t, drift.Cases, 4)
+	assert.Equal(t, 2, drift.Failures)
+
+	drifted := drift.Cases[0]
+	assert.Equal(t, "aws_s3_bucket.my_bucket", drifted.Name)
+	assert.Equal(t, "drift.aws_s3_bucket", drifted.ClassName)
+	assert.Equal(t, "infra/s3.tf", drifted.File)
+	assert.Equal(t, 12, drifted.Line)
+	require.NotNil(t, drifted.Failure)
+	assert.Equal(t, "drift", drifted.Failure.Type)
+	assert.Contains(t, drifted.Failure.Body, "versioning_enabled\n  expected: true\n  actual:   false")
+	assert.Contains(t, drifted.Failure.Body, "acl (not in plan)\n  actual:   public-read")
+
+	require.NotNil(t, drift.Cases[1].Failure)
+	assert.Equal(t, "missing", drift.Cases[1].Failure.Type)
+	assert.NotNil(t, drift.Cases[2].Error, "unknown state is an error")
+
+	inSync := drift.Cases[3]
+	assert.Nil(t, inSync.Failure)
+	assert.Nil(t, inSync.Error)
+	assert.Nil(t, inSync.Skipped)
+}
+
+func TestJUnitFormatter_PolicySuite(t *testing.T) {
+	_, doc := formatJUnit(t, createTestScanResultForJUnit())
+	policy := doc.Suites[1]
+	assert.Equal(t, "cloudrift.policy.s3", policy.Name)
+
+	// 2 violations, 1 warning, 1 passing policy (S3-002)
+	require.Len(t, policy.Cases, 4)
+	assert.Equal(t, 2, policy.Failures)
+
+	assert.Equal(t, "policy.S3-009", policy.Cases[0].ClassName)
+	assert.Equal(t, "aws_s3_bucket.logs", policy.Cases[0].Name)
+	require.NotNil(t, policy.Cases[0].Failure)
+	assert.Equal(t, "critical", policy.Cases[0].Failure.Type)
+
+	assert.Equal(t, "policy.S3-001", policy.Cases[1].ClassName)
+	assert.Contains(t, policy.Cases[1].Failure.Body, "Remediation: Add server_side_encryption_configuration")
+
+	warning := policy.Cases[2]
+	assert.Equal(t, "policy.TAG-001", warning.ClassName)
+	// Warnings pass, with the finding in system-out
+	assert.Nil(t, warning.Skipped)
+	assert.Nil(t, warning.Failure)
+	assert.Contains(t, warning.SystemOut, "warning: ")
+	assert.Contains(t, warning.SystemOut, "Policy: TAG-001")
+
+	passing := policy.Cases[3]
+	assert.Equal(t, "policy.S3-002", passing.ClassName)
+	assert.Nil(t, passing.Failure)
+
+	assert.Equal(t, 8, doc.Tests)
+	assert.Equal(t, 4, doc.Failures)
+	assert.Equal(t, 1, doc.Errors)
+}
+
+func TestJUnitFormatter_NoPolicies(t *testing.T) {
+	_, doc := formatJUnit(t, createTestScanResult())
+	require.Len(t, doc.Suites, 1)
+	assert.Equal(t, 2, doc.Tests)
+	assert.Equal(t, 2, doc.Failures)
+}
+
+func TestJUnitFormatter_EscapesXML(t *testing.T) {
+	out, doc := formatJUnit(t, createTestScanResultForJUnit())
+	assert.Contains(t, out, "Bucket &lt;logs&gt; allows public ACLs")
+	assert.Equal(t, "Bucket <logs> allows public ACLs", doc.Suites[1].Cases[0].Failure.Message)
+}
+
+func TestJUnitFormatter_Name(t *testing.T) {
+	formatter := output.NewJUnitFormatter()
+	assert.Equal(t, "junit", formatter.Name())
+	assert.Equal(t, ".xml", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatJUnit)
+	require.True(t, ok)
+	assert.Equal(t, "junit", registered.Name())
+}
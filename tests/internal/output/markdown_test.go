@@ -0,0 +1,125 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownFormatter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.NewMarkdownFormatter().Format(&buf, createTestScanResultForReport()))
+	assertGolden(t, "report.golden.md", buf.Bytes())
+}
+
+func TestMarkdownFormatter_NoDrift(t *testing.T) {
+	result := output.ScanResult{Service: "EC2", TotalResources: 3}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.NewMarkdownFormatter().Format(&buf, result))
+	md := buf.String()
+
+	assert.Contains(t, md, "## Cloudrift EC2 scan")
+	assert.Contains(t, md, "**3** resources scanned")
+	assert.Contains(t, md, "No drift detected.")
+	assert.NotContains(t, md, "<details>")
+	assert.NotContains(t, md, "Compliance")
+}
+
+func TestMarkdownFormatter_Content(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.NewMarkdownFormatter().Format(&buf, createTestScanResultForReport()))
+	md := buf.String()
+
+	assert.Contains(t, md, "**Compliance: 98.0%** (48/49 policies passing)")
+	assert.Contains(t, md, "| critical | 1 | 0 |")
+	assert.Contains(t, md, "| high | 1 | 0 |")
+	assert.Contains(t, md, "| low | 0 | 1 |")
+	assert.Contains(t, md, "| `aws_s3_bucket.my_bucket` | drifted | tags, versioning_enabled, acl | `infra/s3.tf:12` |")
+	assert.Contains(t, md, "<summary>Drift details (1)</summary>")
+	assert.Contains(t, md, "| acl | _not in plan_ | `public-read` |")
+	assert.Contains(t, md, "```hcl\n# aws_s3_bucket.my_bucket")
+	assert.NotContains(t, md, "in-sync-bucket")
+	assert.NotContains(t, md, "truncated")
+}
+
+func TestMarkdownFormatter_EscapesHTMLAndPipes(t *testing.T) {
+	result := createTestScanResultForReport()
+	result.PolicyResult.Violations[0].Message = "a|b <script>"
+
+	var buf bytes.Buffer
+	require.NoError(t, output.NewMarkdownFormatter().Format(&buf, result))
+	md := buf.String()
+
+	assert.Contains(t, md, "a\\|b &lt;script&gt;")
+	assert.NotContains(t, md, "<script>")
+	assert.Contains(t, md, "Bucket &lt;logs&gt; allows public ACLs")
+}
+
+// createLargeScanResult returns a scan with n drifted resources, each with
+// several long attribute values.
+func createLargeScanResult(n int) output.ScanResult {
+	result := output.ScanResult{Service: "S3", TotalResources: n, DriftCount: n}
+	long := strings.Repeat("x", 200)
+	for i := 0; i < n; i++ {
+		result.Drifts = append(result.Drifts, detector.DriftInfo{
+			ResourceType:    "aws_s3_bucket",
+			ResourceName:    fmt.Sprintf("bucket-%04d", i),
+			ResourceAddress: fmt.Sprintf("aws_s3_bucket.b%04d", i),
+			Diffs: map[string][2]interface{}{
+				"tags.Owner":         {long, "someone"},
+				"versioning_enabled": {true, false},
+			},
+		})
+	}
+	return result
+}
+
+func TestMarkdownFormatter_Truncates(t *testing.T) {
+	formatter := &output.MarkdownFormatter{MaxLength: 4000}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createLargeScanResult(200)))
+	md := buf.String()
+
+	assert.LessOrEqual(t, len(md), 4000)
+	assert.Contains(t, md, "## Cloudrift S3 scan")
+	assert.Contains(t, md, "**200** with drift")
+	assert.Regexp(t, `_\d+ more resources omitted to fit the comment size limit._`, md)
+	assert.Contains(t, md, "Output truncated.")
+	assert.Equal(t, strings.Count(md, "<details>"), strings.Count(md, "</details>"))
+}
+
+func TestMarkdownFormatter_DefaultLimit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.NewMarkdownFormatter().Format(&buf, createLargeScanResult(2000)))
+
+	assert.LessOrEqual(t, buf.Len(), output.DefaultMarkdownLength)
+	assert.Contains(t, buf.String(), "Output truncated.")
+}
+
+func TestMarkdownFormatter_NoLimit(t *testing.T) {
+	formatter := &output.MarkdownFormatter{}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createLargeScanResult(500)))
+
+	assert.Greater(t, buf.Len(), output.DefaultMarkdownLength)
+	assert.NotContains(t, buf.String(), "truncated")
+}
+
+func TestMarkdownFormatter_Name(t *testing.T) {
+	formatter := output.NewMarkdownFormatter()
+	assert.Equal(t, "markdown", formatter.Name())
+	assert.Equal(t, ".md", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatMarkdown)
+	require.True(t, ok)
+	assert.Equal(t, "markdown", registered.Name())
+}
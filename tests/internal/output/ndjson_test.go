@@ -0,0 +1,142 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/detector"
+	"github.com/inayathulla/cloudrift/internal/output"
+)
+
+type ndjsonLine struct {
+	Type      string `json:"type"`
+	Service   string `json:"service"`
+	AccountID string `json:"account_id"`
+
+	// Payload is the object under the key named after the record type.
+	Payload map[string]interface{} `json:"-"`
+
+	raw map[string]interface{}
+}
+
+// parseNDJSON decodes every line of NDJSON output on its own.
+func parseNDJSON(t *testing.T, out string) []ndjsonLine {
+	t.Helper()
+	require.True(t, strings.HasSuffix(out, "\n"))
+
+	var lines []ndjsonLine
+	for _, text := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var line ndjsonLine
+		require.NoError(t, json.Unmarshal([]byte(text), &line), text)
+		require.NoError(t, json.Unmarshal([]byte(text), &line.raw))
+		line.Payload, _ = line.raw[line.Type].(map[string]interface{})
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestNDJSONFormatter_Records(t *testing.T) {
+	result := createTestScanResultForReport()
+
+	var buf bytes.Buffer
+	require.NoError(t, output.NewNDJSONFormatter().Format(&buf, result))
+	lines := parseNDJSON(t, buf.String())
+
+	var types []string
+	for _, line := range lines {
+		types = append(types, line.Type)
+		assert.Equal(t, result.Service, line.Service)
+		assert.Equal(t, result.AccountID, line.AccountID)
+		require.NotNil(t, line.Payload, "record %s has no %q payload", line.Type, line.Type)
+	}
+	assert.Equal(t, []string{
+		"error", "drift", "drift", "drift", "drift", "unmanaged",
+		"remediation", "violation", "violation", "warning", "summary",
+	}, types)
+
+	assert.Equal(t, result.Drifts[0].ResourceID, lines[1].Payload["resource_id"])
+	assert.Equal(t, result.PolicyResult.Violations[0].PolicyID, lines[7].Payload["policy_id"])
+	assert.Equal(t, result.Remediation[0].Address, lines[6].Payload["address"])
+
+	summary := lines[len(lines)-1].Payload
+	assert.Equal(t, output.SchemaVersion, summary["schema_version"])
+	assert.EqualValues(t, result.DriftCount, summary["drift_count"])
+	assert.EqualValues(t, 1, summary["errors"])
+	assert.EqualValues(t, 1, summary["unmanaged"])
+	assert.EqualValues(t, 2, summary["violations"])
+	assert.EqualValues(t, 1, summary["warnings"])
+	assert.Contains(t, summary, "compliance")
+}
+
+func TestNDJSONFormatter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, output.NewNDJSONFormatter().Format(&buf, output.ScanResult{Service: "EC2"}))
+
+	lines := parseNDJSON(t, buf.String())
+	require.Len(t, lines, 1)
+	assert.Equal(t, output.RecordSummary, lines[0].Type)
+	assert.NotContains(t, lines[0].raw, "account_id")
+	assert.NotContains(t, lines[0].Payload, "compliance")
+}
+
+func TestNDJSONWriter_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	w := output.NewNDJSONWriter(&buf, "IAM", "123456789012")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("role-%d", i)
+			assert.NoError(t, w.WriteDrift(detector.DriftInfo{ResourceID: id, Missing: true, Severity: "critical"}))
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(t, w.WriteSummary(output.ScanResult{Service: "IAM", DriftCount: 50}))
+
+	lines := parseNDJSON(t, buf.String())
+	require.Len(t, lines, 51)
+	seen := make(map[interface{}]bool)
+	for _, line := range lines[:50] {
+		assert.Equal(t, output.RecordDrift, line.Type)
+		seen[line.Payload["resource_id"]] = true
+	}
+	assert.Len(t, seen, 50)
+	assert.Equal(t, output.RecordSummary, lines[50].Type)
+}
+
+type failingWriter struct{ writes int }
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	f.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestNDJSONWriter_KeepsFirstError(t *testing.T) {
+	fw := &failingWriter{}
+	w := output.NewNDJSONWriter(fw, "S3", "")
+
+	assert.EqualError(t, w.WriteDrift(detector.DriftInfo{ResourceID: "a"}), "disk full")
+	assert.EqualError(t, w.WriteDrift(detector.DriftInfo{ResourceID: "b"}), "disk full")
+	assert.EqualError(t, w.WriteSummary(output.ScanResult{}), "disk full")
+	assert.Equal(t, 1, fw.writes)
+}
+
+func TestNDJSONFormatter_Name(t *testing.T) {
+	formatter := output.NewNDJSONFormatter()
+	assert.Equal(t, "ndjson", formatter.Name())
+	assert.Equal(t, ".ndjson", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatNDJSON)
+	require.True(t, ok)
+	assert.Equal(t, "ndjson", registered.Name())
+}
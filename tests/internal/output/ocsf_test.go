@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ocsfTestEvent struct {
+	ClassUID    int    `json:"class_uid"`
+	TypeUID     int    `json:"type_uid"`
+	Time        int64  `json:"time"`
+	SeverityID  int    `json:"severity_id"`
+	Severity    string `json:"severity"`
+	FindingInfo struct {
+		UID   string   `json:"uid"`
+		Title string   `json:"title"`
+		Types []string `json:"types"`
+	} `json:"finding_info"`
+	Compliance struct {
+		Control      string   `json:"control"`
+		Requirements []string `json:"requirements"`
+		StatusID     int      `json:"status_id"`
+		Status       string   `json:"status"`
+	} `json:"compliance"`
+	Cloud struct {
+		Provider string `json:"provider"`
+		Region   string `json:"region"`
+		Account  struct {
+			UID string `json:"uid"`
+		} `json:"account"`
+	} `json:"cloud"`
+	Resources []struct {
+		UID  string            `json:"uid"`
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	} `json:"resources"`
+	Remediation *struct {
+		Desc string `json:"desc"`
+	} `json:"remediation"`
+}
+
+func formatOCSF(t *testing.T, result output.ScanResult) (string, []ocsfTestEvent) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, output.NewOCSFFormatter().Format(&buf, result))
+
+	var events []ocsfTestEvent
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &events))
+	return buf.String(), events
+}
+
+func TestOCSFFormatter_Golden(t *testing.T) {
+	out, _ := formatOCSF(t, createTestScanResultForFindings())
+	assertGolden(t, "report.golden.ocsf.json", []byte(out))
+}
+
+func TestOCSFFormatter_Events(t *testing.T) {
+	_, events := formatOCSF(t, createTestScanResultForFindings())
+	require.Len(t, events, 5)
+
+	for _, e := range events {
+		assert.Equal(t, 2003, e.ClassUID)
+		assert.Equal(t, 200301, e.TypeUID)
+		assert.Equal(t, int64(1705314600000), e.Time)
+		assert.Equal(t, "AWS", e.Cloud.Provider)
+		assert.Equal(t, "us-east-1", e.Cloud.Region)
+		assert.Equal(t, "123456789012", e.Cloud.Account.UID)
+	}
+
+	drifted := events[0]
+	assert.Equal(t, "DRIFT002", drifted.Compliance.Control)
+	assert.Equal(t, []string{"Configuration Drift"}, drifted.FindingInfo.Types)
+	assert.Equal(t, 3, drifted.SeverityID)
+	assert.Equal(t, "arn:aws:s3:::my-bucket", drifted.Resources[0].UID)
+	assert.Equal(t, "aws_s3_bucket.my_bucket", drifted.Resources[0].Data["terraform_address"])
+
+	critical := events[2]
+	assert.Equal(t, 5, critical.SeverityID)
+	assert.Equal(t, "Critical", critical.Severity)
+	assert.Equal(t, "S3-009", critical.Compliance.Control)
+	assert.Equal(t, 3, critical.Compliance.StatusID)
+	assert.Equal(t, "Fail", critical.Compliance.Status)
+
+	high := events[3]
+	assert.Equal(t, []string{"GDPR", "HIPAA", "ISO 27001", "PCI DSS", "SOC 2"}, high.Compliance.Requirements)
+	require.NotNil(t, high.Remediation)
+
+	warning := events[4]
+	assert.Equal(t, 2, warning.Compliance.StatusID)
+	assert.Equal(t, "Warning", warning.Compliance.Status)
+	assert.Equal(t, []string{"TAG-001"}, warning.Compliance.Requirements)
+}
+
+func TestOCSFFormatter_UIDsMatchASFF(t *testing.T) {
+	result := createTestScanResultForFindings()
+	_, events := formatOCSF(t, result)
+	_, doc := formatASFF(t, result)
+
+	require.Len(t, events, len(doc.Findings))
+	for i := range events {
+		assert.Equal(t, doc.Findings[i].Id, events[i].FindingInfo.UID)
+	}
+}
+
+func TestOCSFFormatter_NoFindings(t *testing.T) {
+	out, events := formatOCSF(t, output.ScanResult{Service: "S3"})
+	assert.Empty(t, events)
+	assert.Equal(t, "[]\n", out)
+}
+
+func TestOCSFFormatter_Name(t *testing.T) {
+	formatter := output.NewOCSFFormatter()
+	assert.Equal(t, "ocsf", formatter.Name())
+	assert.Equal(t, ".json", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatOCSF)
+	require.True(t, ok)
+	assert.Equal(t, "ocsf", registered.Name())
+}
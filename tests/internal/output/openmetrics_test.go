@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+)
+
+func formatOpenMetrics(t *testing.T, result output.ScanResult) (string, map[string]*dto.MetricFamily) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, output.NewOpenMetricsFormatter().Format(&buf, result))
+
+	// The node_exporter textfile collector and the Pushgateway read the
+	// output with this parser.
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+	families, err := parser.TextToMetricFamilies(strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	return buf.String(), families
+}
+
+// gaugeValue returns the value of the series of a family with the given
+// labels, failing if there is no such series.
+func gaugeValue(t *testing.T, families map[string]*dto.MetricFamily, name string, labels map[string]string) float64 {
+	t.Helper()
+	family, ok := families[name]
+	require.True(t, ok, "missing metric %s", name)
+	assert.Equal(t, dto.MetricType_GAUGE, family.GetType())
+	for _, m := range family.GetMetric() {
+		if m.TimestampMs != nil {
+			t.Errorf("%s has a timestamp", name)
+		}
+		got := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			got[l.GetName()] = l.GetValue()
+		}
+		match := true
+		for k, v := range labels {
+			if got[k] != v {
+				match = false
+			}
+		}
+		if match {
+			return m.GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("no %s series with labels %v", name, labels)
+	return 0
+}
+
+func TestOpenMetricsFormatter_Golden(t *testing.T) {
+	out, _ := formatOpenMetrics(t, createTestScanResultForJUnit())
+	assertGolden(t, "report.golden.prom", []byte(out))
+}
+
+func TestOpenMetricsFormatter_EscapesLabels(t *testing.T) {
+	result := createTestScanResult()
+	result.Region = "us-\"east\"\n\\1"
+	out, families := formatOpenMetrics(t, result)
+
+	assert.Contains(t, out, `region="us-\"east\"\n\\1"`)
+	assert.EqualValues(t, 2, gaugeValue(t, families, "cloudrift_drifted_resources", map[string]string{"region": result.Region}))
+}
+
+func TestOpenMetricsFormatter_Metrics(t *testing.T) {
+	result := createTestScanResultForJUnit()
+	out, families := formatOpenMetrics(t, result)
+
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+	base := map[string]string{"service": "s3", "account": "123456789012", "region": "us-east-1"}
+
+	assert.EqualValues(t, 5, gaugeValue(t, families, "cloudrift_resources", base))
+	assert.EqualValues(t, result.DriftCount, gaugeValue(t, families, "cloudrift_drifted_resources", base))
+	assert.EqualValues(t, 1, gaugeValue(t, families, "cloudrift_unknown_resources", base))
+	assert.EqualValues(t, 1, gaugeValue(t, families, "cloudrift_scan_incomplete", base))
+	assert.EqualValues(t, 1.5, gaugeValue(t, families, "cloudrift_scan_duration_seconds", base))
+	assert.EqualValues(t, 1705314600, gaugeValue(t, families, "cloudrift_scan_timestamp_seconds", base))
+
+	assert.EqualValues(t, 1, gaugeValue(t, families, "cloudrift_policy_violations", map[string]string{"policy_id": "S3-001", "severity": "high"}))
+	assert.EqualValues(t, 1, gaugeValue(t, families, "cloudrift_policy_violations", map[string]string{"policy_id": "S3-009", "severity": "critical"}))
+	// Evaluated policies without findings report zero.
+	assert.EqualValues(t, 0, gaugeValue(t, families, "cloudrift_policy_violations", map[string]string{"policy_id": "TAG-001", "severity": "low"}))
+
+	assert.EqualValues(t, 97.96, gaugeValue(t, families, "cloudrift_compliance_overall_percentage", base))
+	assert.EqualValues(t, 96.15, gaugeValue(t, families, "cloudrift_compliance_percentage", map[string]string{"framework": "hipaa"}))
+	assert.EqualValues(t, 100, gaugeValue(t, families, "cloudrift_compliance_category_percentage", map[string]string{"category": "tagging"}))
+}
+
+func TestOpenMetricsFormatter_SkippedPolicies(t *testing.T) {
+	_, families := formatOpenMetrics(t, createTestScanResult())
+
+	assert.Contains(t, families, "cloudrift_drifted_resources")
+	assert.NotContains(t, families, "cloudrift_policy_violations")
+	assert.NotContains(t, families, "cloudrift_compliance_percentage")
+}
+
+func TestOpenMetricsFormatter_Name(t *testing.T) {
+	formatter := output.NewOpenMetricsFormatter()
+	assert.Equal(t, "openmetrics", formatter.Name())
+	assert.Equal(t, ".prom", formatter.FileExtension())
+
+	registered, ok := output.Get(output.FormatOpenMetrics)
+	require.True(t, ok)
+	assert.Equal(t, "openmetrics", registered.Name())
+}
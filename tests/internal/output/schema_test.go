@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/inayathulla/cloudrift/internal/output/schemagen"
+)
+
+func formatJSON(t *testing.T, result output.ScanResult) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, output.NewJSONFormatter().Format(&buf, result))
+	return buf.Bytes()
+}
+
+func TestSchema_UpToDate(t *testing.T) {
+	generated, err := schemagen.Generate("../../..")
+	require.NoError(t, err)
+	assert.Equal(t, string(generated), string(output.Schema()),
+		"embedded schema is out of date; run go generate ./internal/output")
+}
+
+func TestSchema_Document(t *testing.T) {
+	var doc struct {
+		Schema     string                     `json:"$schema"`
+		Title      string                     `json:"title"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	require.NoError(t, json.Unmarshal(output.Schema(), &doc))
+
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", doc.Schema)
+	assert.Contains(t, doc.Title, output.SchemaVersion)
+	assert.Contains(t, doc.Required, "schema_version")
+	assert.Contains(t, doc.Required, "drifts")
+	assert.NotContains(t, doc.Required, "policy_result")
+
+	// Fields excluded from JSON are not in the schema.
+	assert.NotContains(t, doc.Properties, "Details")
+	assert.NotContains(t, string(output.Schema()), "PolicyRuleOutput")
+}
+
+func TestSchema_ValidatesFormatterFixtures(t *testing.T) {
+	fixtures := map[string]output.ScanResult{
+		"basic":       createTestScanResult(),
+		"compliance":  createTestScanResultWithCompliance(),
+		"errors":      createTestScanResultWithErrors(),
+		"unmanaged":   createTestScanResultWithUnmanaged(),
+		"remediation": createTestScanResultWithRemediation(),
+		"report":      createTestScanResultForReport(),
+		"findings":    createTestScanResultForFindings(),
+		"junit":       createTestScanResultForJUnit(),
+		"large":       createLargeScanResult(20),
+		"console":     createS3ConsoleResult(t),
+		"empty":       {},
+	}
+	for name, result := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, output.ValidateJSON(formatJSON(t, result)))
+		})
+	}
+}
+
+func TestJSONFormatter_SchemaVersion(t *testing.T) {
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(formatJSON(t, createTestScanResult()), &doc))
+	assert.Equal(t, output.SchemaVersion, doc["schema_version"])
+}
+
+func TestValidateJSON_Violations(t *testing.T) {
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(formatJSON(t, createTestScanResultWithCompliance()), &doc))
+
+	delete(doc, "timestamp")
+	doc["drift_count"] = "two"
+	doc["schema_version"] = "0.9.0"
+	doc["unexpected"] = true
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	err = output.ValidateJSON(data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timestamp")
+	assert.Contains(t, err.Error(), "drift_count")
+	assert.Contains(t, err.Error(), "schema_version")
+	assert.Contains(t, err.Error(), "unexpected")
+
+	assert.Error(t, output.ValidateJSON([]byte("not json")))
+}
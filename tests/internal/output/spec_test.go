@@ -0,0 +1,57 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputSpec(t *testing.T) {
+	tests := []struct {
+		in   string
+		want output.OutputSpec
+	}{
+		{"sarif:drift.sarif", output.OutputSpec{Format: output.FormatSARIF, Path: "drift.sarif"}},
+		{"JSON:out/report.json", output.OutputSpec{Format: output.FormatJSON, Path: "out/report.json"}},
+		{"template:drift.csv", output.OutputSpec{Format: output.FormatTemplate, Path: "drift.csv"}},
+		{`html:C:\reports\drift.html`, output.OutputSpec{Format: output.FormatHTML, Path: `C:\reports\drift.html`}},
+	}
+	for _, tt := range tests {
+		got, err := output.ParseOutputSpec(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestParseOutputSpec_Invalid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"sarif", "expected <format>:<path>"},
+		{"sarif:", "expected <format>:<path>"},
+		{":drift.sarif", "expected <format>:<path>"},
+		{"yaml:drift.yaml", `unsupported format "yaml"`},
+	}
+	for _, tt := range tests {
+		_, err := output.ParseOutputSpec(tt.in)
+		assert.ErrorContains(t, err, tt.want, tt.in)
+	}
+}
+
+func TestParseOutputSpecs(t *testing.T) {
+	specs, err := output.ParseOutputSpecs([]string{"sarif:drift.sarif", "json:report.json"})
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, output.FormatSARIF, specs[0].Format)
+	assert.Equal(t, "report.json", specs[1].Path)
+
+	specs, err = output.ParseOutputSpecs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, specs)
+
+	_, err = output.ParseOutputSpecs([]string{"sarif:out/drift.sarif", "json:./out/drift.sarif"})
+	assert.ErrorContains(t, err, "already an output")
+}
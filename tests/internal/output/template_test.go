@@ -0,0 +1,160 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderTemplate(t *testing.T, text string, result output.ScanResult) string {
+	t.Helper()
+	formatter, err := output.NewTemplateFormatter("test", text, ".txt")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, result))
+	return buf.String()
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	assert.Equal(t, []string{"csv", "table"}, output.BuiltinTemplates())
+}
+
+func TestTemplateFormatter_BuiltinCSV_Golden(t *testing.T) {
+	formatter, err := output.LoadTemplate("csv")
+	require.NoError(t, err)
+	assert.Equal(t, ".csv", formatter.FileExtension())
+	assert.Equal(t, "template", formatter.Name())
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultForReport()))
+	assertGolden(t, "report.golden.csv", buf.Bytes())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err, "output must be valid CSV")
+	// header, 3 drifted attributes, missing, unknown, 2 violations, 1 warning
+	require.Len(t, records, 9)
+	assert.Equal(t, "kind", records[0][0])
+	assert.Equal(t, []string{"drift", "drifted", "warning", "aws_s3_bucket", "aws_s3_bucket.my_bucket", "", "tags", "{Env=prod, Owner=<platform>}", "{Env=dev}", "", "infra/s3.tf:12"}, records[1])
+	assert.Equal(t, "S3-009", records[6][5])
+	assert.Equal(t, "warning", records[8][0])
+}
+
+func TestTemplateFormatter_BuiltinTable_Golden(t *testing.T) {
+	formatter, err := output.LoadTemplate("table")
+	require.NoError(t, err)
+	assert.Equal(t, ".txt", formatter.FileExtension())
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResultForReport()))
+	assertGolden(t, "report.golden.table.txt", buf.Bytes())
+}
+
+func TestTemplateFormatter_BuiltinsWithoutPolicies(t *testing.T) {
+	for _, name := range output.BuiltinTemplates() {
+		formatter, err := output.LoadTemplate(name)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, formatter.Format(&buf, output.ScanResult{Service: "EC2"}), name)
+	}
+}
+
+func TestTemplateFormatter_File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slack.json.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"text": {{json (printf "%s: %d drifted" .Service .DriftCount)}}}`), 0o644))
+
+	formatter, err := output.LoadTemplate(path)
+	require.NoError(t, err)
+	assert.Equal(t, ".json", formatter.FileExtension())
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(&buf, createTestScanResult()))
+	assert.Equal(t, `{"text": "S3: 2 drifted"}`, buf.String())
+}
+
+func TestTemplateFormatter_FileExtensionDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte("{{.Service}}"), 0o644))
+
+	formatter, err := output.LoadTemplate(path)
+	require.NoError(t, err)
+	assert.Equal(t, ".txt", formatter.FileExtension())
+}
+
+func TestTemplateFormatter_Errors(t *testing.T) {
+	_, err := output.LoadTemplate(filepath.Join(t.TempDir(), "missing.tmpl"))
+	assert.ErrorContains(t, err, "failed to read template")
+
+	_, err = output.NewTemplateFormatter("bad", "{{.Service", ".txt")
+	assert.ErrorContains(t, err, "failed to parse template")
+
+	formatter, err := output.NewTemplateFormatter("bad", `{{sortBy "Nope" .Drifts}}`, ".txt")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	assert.ErrorContains(t, formatter.Format(&buf, createTestScanResult()), `no field "Nope"`)
+}
+
+func TestTemplateFuncs_Severity(t *testing.T) {
+	result := createTestScanResultForReport()
+	out := renderTemplate(t, `{{range severity "high" .PolicyResult.Violations}}{{.PolicyID}} {{end}}`, result)
+	assert.Equal(t, "S3-001 S3-009 ", out)
+
+	out = renderTemplate(t, `{{range severity "critical" .PolicyResult.Violations}}{{.PolicyID}} {{end}}`, result)
+	assert.Equal(t, "S3-009 ", out)
+
+	// Drift severities are normalized: "warning" is medium.
+	out = renderTemplate(t, `{{len (severity "high" .Drifts)}} {{len (severity "medium" .Drifts)}}`, createTestScanResult())
+	assert.Equal(t, "1 2", out)
+
+	// A missing list is empty.
+	out = renderTemplate(t, `{{len (severity "low" .PolicyResult.Warnings)}}`, createTestScanResultWithCompliance())
+	assert.Equal(t, "0", out)
+}
+
+func TestTemplateFuncs_SortBy(t *testing.T) {
+	result := createTestScanResultForReport()
+	out := renderTemplate(t, `{{range sortBy "Severity" .PolicyResult.Violations}}{{.PolicyID}} {{end}}`, result)
+	assert.Equal(t, "S3-009 S3-001 ", out)
+
+	out = renderTemplate(t, `{{range sortBy "ResourceName" .Drifts}}{{.ResourceName}} {{end}}`, result)
+	assert.Equal(t, "in-sync-bucket missing-bucket my-bucket role-x ", out)
+
+	// An empty field sorts the elements themselves.
+	out = renderTemplate(t, `{{join "," (sortBy "" (index .PolicyResult.Violations 0).Frameworks)}}`, result)
+	assert.Equal(t, "gdpr,hipaa,iso_27001,pci_dss,soc2", out)
+}
+
+func TestTemplateFuncs_Formatting(t *testing.T) {
+	result := createTestScanResultWithCompliance()
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{join ", " (index .PolicyResult.Violations 0).Frameworks}}`, "hipaa, pci_dss, iso_27001, gdpr, soc2"},
+		{`{{json .PolicyResult.ComplianceResult.Categories.cost}}`, `{"percentage":100,"passed":3,"failed":0,"total":3}`},
+		{`[{{pad 6 "ab"}}]`, "[ab    ]"},
+		{`[{{padLeft 6 "ab"}}]`, "[    ab]"},
+		{`[{{pad 1 "abc"}}]`, "[abc]"},
+		{`{{csv "a,b"}} {{csv "plain"}} {{csv "say \"hi\""}}`, `"a,b" plain "say ""hi"""`},
+		{`{{upper .Service}} {{lower .Service}}`, "S3 s3"},
+		{`{{range drifted .Drifts}}{{label .}}={{status .}};{{end}}`, "my-bucket (aws_s3_bucket)=drifted;missing-bucket (aws_s3_bucket)=missing;"},
+		{`{{range diffs (index .Drifts 0)}}{{.Attribute}}:{{.Expected}}->{{.Actual}}{{end}}`, "versioning_enabled:true->false"},
+		{`{{value nil}}`, "<not set>"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, renderTemplate(t, tt.text, result), tt.text)
+	}
+}
+
+func TestTemplateFormatter_NotRegistered(t *testing.T) {
+	_, ok := output.Get(output.FormatTemplate)
+	assert.False(t, ok, "template format needs a template and is built with LoadTemplate")
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/inayathulla/cloudrift/internal/parser"
@@ -66,3 +69,164 @@ func TestLoadIAMPlan_SkipsDeletedResources(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 8, result.TotalCount())
 }
+
+func TestParseAllIAMResources_InlinePoliciesAttachmentsAndProfiles(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_iam_role.app",
+				"type": "aws_iam_role",
+				"change": {"actions": ["create"], "after": {
+					"name": "app",
+					"permissions_boundary": "arn:aws:iam::123456789012:policy/boundary",
+					"managed_policy_arns": ["arn:aws:iam::aws:policy/ReadOnlyAccess"],
+					"inline_policy": [{"name": "inline-read", "policy": "{\"Statement\":[]}"}]
+				}}
+			},
+			{
+				"address": "aws_iam_role_policy.app_write",
+				"type": "aws_iam_role_policy",
+				"change": {"actions": ["create"], "after": {"name": "write", "role": "app", "policy": "{\"Statement\":[]}"}}
+			},
+			{
+				"address": "aws_iam_role_policy_attachment.app_ssm",
+				"type": "aws_iam_role_policy_attachment",
+				"change": {"actions": ["create"], "after": {"role": "app", "policy_arn": "arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"}}
+			},
+			{
+				"address": "aws_iam_role_policy_attachment.app_readonly",
+				"type": "aws_iam_role_policy_attachment",
+				"change": {"actions": ["create"], "after": {"role": "app", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"}}
+			},
+			{
+				"address": "aws_iam_user.ci",
+				"type": "aws_iam_user",
+				"change": {"actions": ["create"], "after": {"name": "ci", "permissions_boundary": "arn:aws:iam::123456789012:policy/boundary"}}
+			},
+			{
+				"address": "aws_iam_user_policy.ci",
+				"type": "aws_iam_user_policy",
+				"change": {"actions": ["create"], "after": {"name": "deploy", "user": "ci", "policy": "{\"Statement\":[]}"}}
+			},
+			{
+				"address": "aws_iam_group.ops",
+				"type": "aws_iam_group",
+				"change": {"actions": ["create"], "after": {"name": "ops"}}
+			},
+			{
+				"address": "aws_iam_group_policy.ops",
+				"type": "aws_iam_group_policy",
+				"change": {"actions": ["create"], "after": {"name": "ops", "group": "ops", "policy": "{\"Statement\":[]}"}}
+			},
+			{
+				"address": "aws_iam_role_policy.unmanaged",
+				"type": "aws_iam_role_policy",
+				"change": {"actions": ["create"], "after": {"name": "x", "role": "not-in-plan", "policy": "{}"}}
+			},
+			{
+				"address": "aws_iam_instance_profile.app",
+				"type": "aws_iam_instance_profile",
+				"change": {"actions": ["create"], "after": {"name": "app-profile", "path": "/", "role": "app", "tags": {"env": "prod"}}}
+			}
+		]
+	}`
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte(planJSON), 0644))
+
+	result, err := parser.LoadIAMPlan(path)
+	require.NoError(t, err)
+
+	require.Len(t, result.Roles, 1)
+	role := result.Roles[0]
+	assert.Equal(t, "arn:aws:iam::123456789012:policy/boundary", role.PermissionsBoundary)
+	assert.ElementsMatch(t, []string{
+		"arn:aws:iam::aws:policy/ReadOnlyAccess",
+		"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+	}, role.AttachedPolicies)
+	assert.Equal(t, []string{"inline-read", "write"}, sortedKeys(role.InlinePolicies))
+
+	require.Len(t, result.Users, 1)
+	assert.Equal(t, "arn:aws:iam::123456789012:policy/boundary", result.Users[0].PermissionsBoundary)
+	assert.Contains(t, result.Users[0].InlinePolicies, "deploy")
+
+	require.Len(t, result.Groups, 1)
+	assert.Contains(t, result.Groups[0].InlinePolicies, "ops")
+
+	require.Len(t, result.InstanceProfiles, 1)
+	profile := result.InstanceProfiles[0]
+	assert.Equal(t, "app-profile", profile.InstanceProfileName)
+	assert.Equal(t, []string{"app"}, profile.Roles)
+	assert.Equal(t, "prod", profile.Tags["env"])
+	assert.Equal(t, 4, result.TotalCount())
+}
+
+func TestParseAllIAMResources_InlinePolicyUnknownAtPlanTime(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_iam_role.app",
+				"type": "aws_iam_role",
+				"change": {"actions": ["update"], "after": {"name": "app"}}
+			},
+			{
+				"address": "aws_iam_role_policy.generated",
+				"type": "aws_iam_role_policy",
+				"change": {
+					"actions": ["create"],
+					"after": {"name_prefix": "app-", "role": "app", "policy": "{\"Statement\":[]}"},
+					"after_unknown": {"id": true, "name": true}
+				}
+			},
+			{
+				"address": "aws_iam_user.ci",
+				"type": "aws_iam_user",
+				"change": {"actions": ["update"], "after": {"name": "ci"}}
+			},
+			{
+				"address": "aws_iam_user_policy.bucket",
+				"type": "aws_iam_user_policy",
+				"change": {
+					"actions": ["create"],
+					"after": {"name": "bucket", "user": "ci"},
+					"after_unknown": {"id": true, "policy": true}
+				}
+			},
+			{
+				"address": "aws_iam_group.ops",
+				"type": "aws_iam_group",
+				"change": {"actions": ["update"], "after": {"name": "ops"}}
+			},
+			{
+				"address": "aws_iam_group_policy.ops",
+				"type": "aws_iam_group_policy",
+				"change": {"actions": ["create"], "after": {"name": "ops", "group": "ops", "policy": "{\"Statement\":[]}"}}
+			}
+		]
+	}`
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte(planJSON), 0644))
+
+	result, err := parser.LoadIAMPlan(path)
+	require.NoError(t, err)
+
+	require.Len(t, result.Roles, 1)
+	assert.True(t, result.Roles[0].InlinePoliciesUnknown)
+	assert.Empty(t, result.Roles[0].InlinePolicies, "no policy is recorded under an empty name")
+
+	require.Len(t, result.Users, 1)
+	assert.True(t, result.Users[0].InlinePoliciesUnknown)
+	assert.Empty(t, result.Users[0].InlinePolicies, "no policy is recorded with an empty document")
+
+	require.Len(t, result.Groups, 1)
+	assert.False(t, result.Groups[0].InlinePoliciesUnknown)
+	assert.Contains(t, result.Groups[0].InlinePolicies, "ops")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
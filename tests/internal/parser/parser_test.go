@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/inayathulla/cloudrift/internal/models"
 	"github.com/inayathulla/cloudrift/internal/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,6 +51,39 @@ func TestParseS3Buckets_BasicBucket(t *testing.T) {
 	assert.Equal(t, "prod", buckets[0].Tags["Environment"])
 }
 
+func TestParseS3Buckets_WithGrants(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_s3_bucket.shared",
+				"type": "aws_s3_bucket",
+				"name": "shared",
+				"change": {
+					"actions": ["create"],
+					"after": {
+						"bucket": "shared-bucket",
+						"grant": [
+							{"type": "Group", "uri": "http://acs.amazonaws.com/groups/s3/LogDelivery", "permissions": ["WRITE", "READ_ACP"]},
+							{"type": "CanonicalUser", "id": "owner-id", "permissions": ["FULL_CONTROL"]}
+						]
+					}
+				}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	buckets, err := parser.LoadPlan(path)
+
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	assert.Equal(t, []models.AclGrant{
+		{Grantee: "group:LogDelivery", Permission: "READ_ACP"},
+		{Grantee: "group:LogDelivery", Permission: "WRITE"},
+		{Grantee: "id:owner-id", Permission: "FULL_CONTROL"},
+	}, buckets[0].AclGrants)
+}
+
 func TestParseS3Buckets_WithVersioning(t *testing.T) {
 	planJSON := `{
 		"resource_changes": [
@@ -214,6 +248,232 @@ func TestParseS3Buckets_WithLifecycle(t *testing.T) {
 	assert.Equal(t, 90, buckets[0].LifecycleRules[0].ExpirationDays)
 }
 
+func TestParseS3Buckets_InlineLifecycleV3(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_s3_bucket.logs",
+				"type": "aws_s3_bucket",
+				"name": "logs",
+				"change": {
+					"actions": ["create"],
+					"after": {
+						"bucket": "logs-bucket",
+						"lifecycle_rule": [{
+							"id": "archive",
+							"enabled": true,
+							"prefix": "logs/",
+							"tags": {"tier": "cold"},
+							"abort_incomplete_multipart_upload_days": 3,
+							"expiration": [{"days": 365, "date": null, "expired_object_delete_marker": false}],
+							"noncurrent_version_expiration": [{"days": 30}],
+							"transition": [
+								{"days": 90, "date": null, "storage_class": "GLACIER"},
+								{"days": 30, "date": null, "storage_class": "STANDARD_IA"}
+							],
+							"noncurrent_version_transition": [{"days": 7, "storage_class": "GLACIER"}]
+						}]
+					}
+				}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	buckets, err := parser.LoadPlan(path)
+
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	require.Len(t, buckets[0].LifecycleRules, 1)
+	assert.Equal(t, models.LifecycleRuleSummary{
+		ID:                       "archive",
+		Status:                   "Enabled",
+		Prefix:                   "logs/",
+		FilterTags:               map[string]string{"tier": "cold"},
+		ExpirationDays:           365,
+		NoncurrentExpirationDays: 30,
+		Transitions: []models.LifecycleTransition{
+			{Days: 30, StorageClass: "STANDARD_IA"},
+			{Days: 90, StorageClass: "GLACIER"},
+		},
+		NoncurrentTransitions:        []models.LifecycleTransition{{Days: 7, StorageClass: "GLACIER"}},
+		AbortIncompleteMultipartDays: 3,
+	}, buckets[0].LifecycleRules[0])
+}
+
+func TestParseS3Buckets_LifecycleConfigurationResource(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_s3_bucket.data",
+				"type": "aws_s3_bucket",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {"bucket": "data-bucket"}}
+			},
+			{
+				"address": "aws_s3_bucket_lifecycle_configuration.data",
+				"type": "aws_s3_bucket_lifecycle_configuration",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"rule": [
+						{
+							"id": "archive",
+							"status": "Enabled",
+							"filter": [{"prefix": "", "tag": [], "object_size_greater_than": null, "object_size_less_than": null,
+								"and": [{"prefix": "data/", "tags": {"tier": "cold"}, "object_size_greater_than": 1024, "object_size_less_than": 0}]}],
+							"expiration": [],
+							"noncurrent_version_expiration": [{"noncurrent_days": 30, "newer_noncurrent_versions": "2"}],
+							"transition": [{"days": 30, "date": null, "storage_class": "STANDARD_IA"}],
+							"noncurrent_version_transition": [{"noncurrent_days": 7, "newer_noncurrent_versions": null, "storage_class": "GLACIER"}],
+							"abort_incomplete_multipart_upload": [{"days_after_initiation": 7}]
+						},
+						{
+							"id": "sunset",
+							"status": "Disabled",
+							"filter": [{"prefix": "tmp/"}],
+							"expiration": [{"date": "2030-01-01T00:00:00Z", "days": 0, "expired_object_delete_marker": false}]
+						}
+					]
+				}}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	buckets, err := parser.LoadPlan(path)
+
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	rules := buckets[0].LifecycleRules
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, models.LifecycleRuleSummary{
+		ID:                           "archive",
+		Status:                       "Enabled",
+		Prefix:                       "data/",
+		FilterTags:                   map[string]string{"tier": "cold"},
+		ObjectSizeGreaterThan:        1024,
+		NoncurrentExpirationDays:     30,
+		NewerNoncurrentVersions:      2,
+		Transitions:                  []models.LifecycleTransition{{Days: 30, StorageClass: "STANDARD_IA"}},
+		NoncurrentTransitions:        []models.LifecycleTransition{{Days: 7, StorageClass: "GLACIER"}},
+		AbortIncompleteMultipartDays: 7,
+	}, rules[0])
+
+	assert.Equal(t, "sunset", rules[1].ID)
+	assert.Equal(t, "Disabled", rules[1].Status)
+	assert.Equal(t, "tmp/", rules[1].Prefix)
+	assert.Equal(t, "2030-01-01", rules[1].ExpirationDate)
+}
+
+func TestParseS3Buckets_StandaloneBucketResources(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_s3_bucket.data",
+				"type": "aws_s3_bucket",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {"bucket": "data-bucket"}}
+			},
+			{
+				"address": "aws_s3_bucket_policy.data",
+				"type": "aws_s3_bucket_policy",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"policy": "{\"Version\":\"2012-10-17\",\"Statement\":[]}"
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_ownership_controls.data",
+				"type": "aws_s3_bucket_ownership_controls",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"rule": [{"object_ownership": "BucketOwnerEnforced"}]
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_server_side_encryption_configuration.data",
+				"type": "aws_s3_bucket_server_side_encryption_configuration",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"rule": [{
+						"apply_server_side_encryption_by_default": [{"sse_algorithm": "aws:kms", "kms_master_key_id": "abcd"}],
+						"bucket_key_enabled": true
+					}]
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_cors_configuration.data",
+				"type": "aws_s3_bucket_cors_configuration",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"cors_rule": [{"allowed_methods": ["GET"], "allowed_origins": ["*"], "max_age_seconds": 60}]
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_replication_configuration.data",
+				"type": "aws_s3_bucket_replication_configuration",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"role": "arn:aws:iam::123456789012:role/replication",
+					"rule": [{
+						"id": "all",
+						"status": "Enabled",
+						"filter": [{"prefix": "logs/"}],
+						"destination": [{"bucket": "arn:aws:s3:::replica", "storage_class": "STANDARD_IA"}],
+						"delete_marker_replication": [{"status": "Disabled"}]
+					}]
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_object_lock_configuration.data",
+				"type": "aws_s3_bucket_object_lock_configuration",
+				"name": "data",
+				"change": {"actions": ["create"], "after": {
+					"bucket": "data-bucket",
+					"rule": [{"default_retention": [{"mode": "COMPLIANCE", "days": 7}]}]
+				}}
+			},
+			{
+				"address": "aws_s3_bucket_policy.other",
+				"type": "aws_s3_bucket_policy",
+				"name": "other",
+				"change": {"actions": ["create"], "after": {"bucket": "not-in-plan", "policy": "{}"}}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	buckets, err := parser.LoadPlan(path)
+
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	b := buckets[0]
+	assert.Equal(t, `{"Version":"2012-10-17","Statement":[]}`, b.Policy)
+	assert.Equal(t, "BucketOwnerEnforced", b.ObjectOwnership)
+	assert.Equal(t, "aws:kms", b.EncryptionAlgorithm)
+	assert.Equal(t, "abcd", b.KMSKeyID)
+	assert.True(t, b.BucketKeyEnabled)
+	require.Len(t, b.CORSRules, 1)
+	assert.Equal(t, []string{"*"}, b.CORSRules[0].AllowedOrigins)
+	assert.Equal(t, 60, b.CORSRules[0].MaxAgeSeconds)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/replication", b.Replication.Role)
+	assert.Equal(t, []models.ReplicationRule{{
+		ID:                "all",
+		Status:            "Enabled",
+		Prefix:            "logs/",
+		DestinationBucket: "arn:aws:s3:::replica",
+		StorageClass:      "STANDARD_IA",
+	}}, b.Replication.Rules)
+	assert.Equal(t, models.ObjectLockConfig{Enabled: true, Mode: "COMPLIANCE", Days: 7}, b.ObjectLock)
+}
+
 func TestParseS3Buckets_MultipleBuckets(t *testing.T) {
 	planJSON := `{
 		"resource_changes": [
@@ -382,10 +642,40 @@ func TestParseEC2Instances_WithRootBlockDevice(t *testing.T) {
 	require.Len(t, instances, 1)
 	assert.Equal(t, "gp3", instances[0].RootBlockDevice.VolumeType)
 	assert.Equal(t, 100, instances[0].RootBlockDevice.VolumeSize)
-	assert.True(t, instances[0].RootBlockDevice.Encrypted)
+	require.NotNil(t, instances[0].RootBlockDevice.Encrypted)
+	assert.True(t, *instances[0].RootBlockDevice.Encrypted)
 	assert.True(t, instances[0].RootBlockDevice.DeleteOnTermination)
 }
 
+func TestParseEC2Instances_RootBlockDeviceEncryptionUnset(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_instance.web",
+				"type": "aws_instance",
+				"name": "web",
+				"change": {
+					"actions": ["create"],
+					"after": {
+						"ami": "ami-12345678",
+						"root_block_device": [{"volume_type": "gp3"}]
+					},
+					"after_unknown": {
+						"root_block_device": [{"encrypted": true}]
+					}
+				}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	instances, err := parser.LoadEC2Plan(path)
+
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Nil(t, instances[0].RootBlockDevice.Encrypted, "encryption is left to the account default")
+}
+
 func TestParseEC2Instances_WithAllAttributes(t *testing.T) {
 	planJSON := `{
 		"resource_changes": [
@@ -430,6 +720,83 @@ func TestParseEC2Instances_WithAllAttributes(t *testing.T) {
 	assert.False(t, inst.SourceDestCheck)
 }
 
+func TestParseEC2Instances_WithSecurityAndStorageBlocks(t *testing.T) {
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_instance.app",
+				"type": "aws_instance",
+				"name": "app",
+				"change": {
+					"actions": ["create"],
+					"after": {
+						"ami": "ami-12345678",
+						"instance_type": "t3.micro",
+						"disable_api_termination": true,
+						"disable_api_stop": true,
+						"user_data": "#!/bin/bash\necho hi\n",
+						"metadata_options": [{
+							"http_tokens": "required",
+							"http_endpoint": "enabled",
+							"http_put_response_hop_limit": 1
+						}],
+						"ebs_block_device": [
+							{"device_name": "/dev/sdg", "volume_type": "gp3", "volume_size": 50},
+							{"device_name": "/dev/sdf", "volume_type": "io2", "volume_size": 100, "iops": 5000}
+						],
+						"network_interface": [{
+							"network_interface_id": "eni-1",
+							"device_index": 0,
+							"delete_on_termination": false
+						}]
+					}
+				}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	instances, err := parser.LoadEC2Plan(path)
+
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	inst := instances[0]
+	assert.True(t, inst.DisableAPITermination)
+	assert.True(t, inst.DisableAPIStop)
+	assert.Equal(t, models.HashUserData([]byte("#!/bin/bash\necho hi\n")), inst.UserDataHash)
+	assert.Equal(t, "required", inst.MetadataOptions.HTTPTokens)
+	assert.Equal(t, 1, inst.MetadataOptions.HTTPPutResponseHopLimit)
+	require.Len(t, inst.EBSBlockDevices, 2)
+	assert.Equal(t, "/dev/sdf", inst.EBSBlockDevices[0].DeviceName, "sorted by device name")
+	assert.Equal(t, 5000, inst.EBSBlockDevices[0].IOPS)
+	require.Len(t, inst.NetworkInterfaces, 1)
+	assert.Equal(t, "eni-1", inst.NetworkInterfaces[0].NetworkInterfaceID)
+}
+
+func TestParseEC2Instances_UserDataHashPassthrough(t *testing.T) {
+	hash := models.HashUserData([]byte("echo hi"))
+	planJSON := `{
+		"resource_changes": [
+			{
+				"address": "aws_instance.app",
+				"type": "aws_instance",
+				"name": "app",
+				"change": {
+					"actions": ["update"],
+					"after": {"instance_type": "t3.micro", "user_data": "` + hash + `"}
+				}
+			}
+		]
+	}`
+
+	path := createTempPlanFile(t, planJSON)
+	instances, err := parser.LoadEC2Plan(path)
+
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, hash, instances[0].UserDataHash)
+}
+
 func TestParseEC2Instances_MultipleInstances(t *testing.T) {
 	planJSON := `{
 		"resource_changes": [
@@ -452,6 +452,34 @@ deny[msg] {
 	assert.Len(t, result.Violations, 2) // bucket1 and bucket3 fail
 }
 
+func TestEngine_EvaluateAllCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	policyContent := `
+package test.all
+
+deny[msg] {
+	input.resource.type == "aws_s3_bucket"
+	msg := "Denied"
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "all.rego"), []byte(policyContent), 0644))
+
+	engine, err := policy.NewEngine(tmpDir)
+	require.NoError(t, err)
+
+	inputs := []*policy.PolicyInput{
+		{Resource: policy.ResourceInput{Type: "aws_s3_bucket", Address: "bucket1"}},
+		{Resource: policy.ResourceInput{Type: "aws_s3_bucket", Address: "bucket2"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := engine.EvaluateAll(ctx, inputs)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+}
+
 // Test multiple policies
 func TestEngine_MultiplePolicies(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -545,3 +573,63 @@ func TestDriftInput(t *testing.T) {
 	assert.False(t, input.Resource.Drift.Missing)
 	assert.Len(t, input.Resource.Drift.Diffs, 1)
 }
+
+func TestEngine_Policies(t *testing.T) {
+	tmpDir := t.TempDir()
+	custom := `
+package custom.naming
+
+deny[result] {
+	input.resource.type == "aws_s3_bucket"
+	result := {
+		"policy_id": "ORG-001",
+		"policy_name": "Bucket Naming",
+		"msg": "bad name",
+		"severity": "medium",
+		"remediation": "Rename to \"<team>-<purpose>\"",
+		"frameworks": ["soc2"],
+	}
+}
+`
+	plain := `
+package custom.plain
+
+warn[msg] {
+	msg := "always"
+}
+`
+	helper := `
+package custom.lib
+
+is_bucket { input.resource.type == "aws_s3_bucket" }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "naming.rego"), []byte(custom), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "plain.rego"), []byte(plain), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "lib.rego"), []byte(helper), 0644))
+
+	engine, err := policy.LoadPoliciesWithBuiltins(tmpDir)
+	require.NoError(t, err)
+	policies := engine.Policies()
+
+	byID := make(map[string]policy.PolicyInfo)
+	for i, p := range policies {
+		byID[p.ID] = p
+		if i > 0 {
+			assert.Less(t, policies[i-1].ID, p.ID, "policies should be sorted by ID")
+		}
+	}
+
+	// Built-in and custom policies are both listed
+	assert.Len(t, policies, len(policy.LoadBuiltinRegistry().Policies)+2)
+	assert.Equal(t, "security", byID["S3-001"].Category)
+	assert.Equal(t, policy.PolicyInfo{
+		ID:          "ORG-001",
+		Name:        "Bucket Naming",
+		Severity:    "medium",
+		Remediation: `Rename to "<team>-<purpose>"`,
+		Frameworks:  []string{"soc2"},
+	}, byID["ORG-001"])
+	// Without policy_id, a policy is listed under its package path
+	assert.Contains(t, byID, "custom.plain")
+	assert.NotContains(t, byID, "custom.lib")
+}
@@ -238,3 +238,12 @@ func TestLoadBuiltinRegistry_Idempotent(t *testing.T) {
 	assert.Equal(t, reg1.CategoryTotals, reg2.CategoryTotals)
 	assert.Equal(t, reg1.FrameworkTotals, reg2.FrameworkTotals)
 }
+
+func TestLoadBuiltinRegistry_RuleMetadata(t *testing.T) {
+	reg := policy.LoadBuiltinRegistry()
+
+	s3 := reg.Policies["S3-001"]
+	assert.Equal(t, "S3 Encryption Required", s3.Name)
+	assert.Equal(t, "high", s3.Severity)
+	assert.Contains(t, s3.Remediation, "server_side_encryption_configuration")
+}
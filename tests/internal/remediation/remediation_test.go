@@ -0,0 +1,244 @@
+package remediation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/remediation"
+)
+
+func ec2Fixture() ([]models.EC2Instance, *models.EC2LiveState) {
+	plan := []models.EC2Instance{
+		{
+			TerraformAddress: "aws_instance.web",
+			InstanceID:       "i-web",
+			InstanceType:     "t3.micro",
+			AMI:              "ami-123",
+			SecurityGroupIDs: []string{"sg-1"},
+			Tags:             map[string]string{"Name": "web", "Env": "prod"},
+		},
+		{
+			TerraformAddress: "aws_instance.db",
+			InstanceID:       "i-db",
+			InstanceType:     "t3.large",
+			SecurityGroupIDs: []string{"sg-2"},
+			Tags:             map[string]string{"Name": "db"},
+		},
+	}
+	live := &models.EC2LiveState{Instances: []models.EC2Instance{
+		{
+			InstanceID:         "i-web",
+			InstanceType:       "t3.large",
+			AMI:                "ami-123",
+			SecurityGroupIDs:   []string{"sg-1"},
+			IAMInstanceProfile: "arn:aws:iam::123456789012:instance-profile/web-profile",
+			Tags:               map[string]string{"Name": "web", "Env": "staging", "aws:autoscaling:groupName": "asg"},
+		},
+		{
+			InstanceID:       "i-db",
+			InstanceType:     "t3.large",
+			SecurityGroupIDs: []string{"sg-2"},
+			Tags:             map[string]string{"Name": "db"},
+		},
+	}}
+	return plan, live
+}
+
+func TestBuild_EC2(t *testing.T) {
+	plan, live := ec2Fixture()
+
+	patches, err := remediation.Build(plan, live)
+	require.NoError(t, err)
+
+	// The db instance has no drift, so only web gets a patch
+	require.Len(t, patches, 1)
+	p := patches[0]
+	assert.Equal(t, "aws_instance.web", p.Address)
+	assert.Equal(t, "aws_instance", p.ResourceType)
+	assert.Equal(t, []remediation.Change{
+		{Attribute: "instance_type", Value: "t3.large"},
+		{Attribute: "tags", Value: map[string]string{"Name": "web", "Env": "staging"}},
+	}, p.Changes)
+}
+
+func TestBuild_SkipsMissingAndFailed(t *testing.T) {
+	plan := []models.S3Bucket{
+		{Id: "aws_s3_bucket.gone", Name: "gone", Tags: map[string]string{"Env": "prod"}},
+		{Id: "aws_s3_bucket.denied", Name: "denied", Tags: map[string]string{"Env": "prod"}},
+		{Id: "aws_s3_bucket.logs", Name: "logs", Tags: map[string]string{"Env": "prod"}},
+	}
+	live := &models.S3LiveState{
+		Buckets: []models.S3Bucket{
+			{Name: "denied"},
+			{Name: "logs", Tags: map[string]string{"Env": "dev"}},
+		},
+		Errors: []models.FetchError{{Service: "s3", ResourceType: "aws_s3_bucket", Resource: "denied", ErrorCode: "AccessDenied"}},
+	}
+
+	patches, err := remediation.Build(plan, live)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	assert.Equal(t, "aws_s3_bucket.logs", patches[0].Address)
+	assert.Equal(t, []remediation.Change{{Attribute: "tags", Value: map[string]string{"Env": "dev"}}}, patches[0].Changes)
+}
+
+func TestBuild_IAM(t *testing.T) {
+	plan := &models.IAMPlanResources{Roles: []models.IAMRole{{
+		TerraformAddress:   "aws_iam_role.deploy",
+		RoleName:           "deploy",
+		Path:               "/",
+		MaxSessionDuration: 3600,
+		Description:        "CI deploy role",
+	}}}
+	live := &models.IAMLiveState{Roles: []models.IAMRole{{
+		RoleName:           "deploy",
+		Path:               "/",
+		MaxSessionDuration: 7200,
+		Description:        "changed in console",
+	}}}
+
+	patches, err := remediation.Build(plan, live)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	assert.Equal(t, []remediation.Change{
+		{Attribute: "description", Value: "changed in console"},
+		{Attribute: "max_session_duration", Value: 7200},
+	}, patches[0].Changes)
+}
+
+func TestBuild_TypeMismatch(t *testing.T) {
+	_, err := remediation.Build([]models.S3Bucket{}, &models.EC2LiveState{})
+	assert.Error(t, err)
+
+	_, err = remediation.Build("nope", nil)
+	assert.Error(t, err)
+}
+
+func TestHCL(t *testing.T) {
+	plan, live := ec2Fixture()
+	patches, err := remediation.Build(plan, live)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# aws_instance.web
+resource "aws_instance" "web" {
+  instance_type = "t3.large"
+  tags = {
+    Env  = "staging"
+    Name = "web"
+  }
+}
+`, string(remediation.HCL(patches)))
+	assert.Empty(t, remediation.HCL(nil))
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	mainTF := `# Web tier
+resource "aws_instance" "web" {
+  ami           = var.ami # pinned by the release job
+  instance_type = "t3.micro"
+
+  tags = {
+    Name = "web"
+    Env  = "prod"
+  }
+}
+
+resource "aws_instance" "db" {
+  instance_type="t3.large"
+  ami = var.ami
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mainTF), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.tf"), []byte("variable \"ami\" {}\n"), 0o644))
+
+	patches := []remediation.Patch{
+		{Address: "aws_instance.web", ResourceType: "aws_instance", Changes: []remediation.Change{
+			{Attribute: "instance_type", Value: "t3.large"},
+			{Attribute: "monitoring", Value: true},
+		}},
+		{Address: "aws_instance.api", ResourceType: "aws_instance", Changes: []remediation.Change{
+			{Attribute: "instance_type", Value: "t3.large"},
+		}},
+		{Address: "module.app.aws_instance.web", ResourceType: "aws_instance", Changes: []remediation.Change{
+			{Attribute: "instance_type", Value: "t3.large"},
+		}},
+		{Address: "aws_instance.worker[0]", ResourceType: "aws_instance", Changes: []remediation.Change{
+			{Attribute: "instance_type", Value: "t3.large"},
+		}},
+	}
+
+	res, err := remediation.Apply(dir, patches)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws_instance.web"}, res.Applied)
+	assert.Equal(t, []string{filepath.Join(dir, "main.tf")}, res.Files)
+	assert.Len(t, res.Skipped, 3)
+	assert.Contains(t, res.Skipped, "aws_instance.api")
+	assert.Contains(t, res.Skipped, "module.app.aws_instance.web")
+	assert.Contains(t, res.Skipped, "aws_instance.worker[0]")
+
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	// Changed arguments are replaced in place; comments, other arguments
+	// and other blocks are preserved, including their formatting
+	assert.Equal(t, `# Web tier
+resource "aws_instance" "web" {
+  ami           = var.ami # pinned by the release job
+  instance_type = "t3.large"
+
+  tags = {
+    Name = "web"
+    Env  = "prod"
+  }
+  monitoring = true
+}
+
+resource "aws_instance" "db" {
+  instance_type="t3.large"
+  ami = var.ami
+}
+`, string(got))
+}
+
+func TestApply_MultilineValue(t *testing.T) {
+	dir := t.TempDir()
+	mainTF := `resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  tags = { Env = "prod" }
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(mainTF), 0o644))
+
+	_, err := remediation.Apply(dir, []remediation.Patch{{Address: "aws_s3_bucket.logs", ResourceType: "aws_s3_bucket", Changes: []remediation.Change{
+		{Attribute: "tags", Value: map[string]string{"Env": "prod", "Owner": "ops"}},
+		{Attribute: "grants", Value: []string{"read"}},
+	}}})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	require.NoError(t, err)
+	// Written values are indented inside the block; untouched arguments
+	// keep their formatting
+	assert.Equal(t, `resource "aws_s3_bucket" "logs" {
+  bucket="logs"
+  tags = {
+    Env   = "prod"
+    Owner = "ops"
+  }
+  grants = ["read"]
+}
+`, string(got))
+}
+
+func TestApply_InvalidHCL(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource \"aws_instance\" {\n"), 0o644))
+
+	_, err := remediation.Apply(dir, []remediation.Patch{{Address: "aws_instance.web"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inayathulla/cloudrift/internal/models"
+	"github.com/inayathulla/cloudrift/internal/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	snap := snapshot.New("123456789012", "us-east-1")
+	require.NoError(t, snap.Add("s3", &models.S3LiveState{
+		Buckets: []models.S3Bucket{
+			{
+				Name:                "my-bucket",
+				Acl:                 "private",
+				Tags:                map[string]string{"env": "prod"},
+				VersioningEnabled:   true,
+				EncryptionAlgorithm: "AES256",
+				LifecycleRules: []models.LifecycleRuleSummary{
+					{ID: "expire", Status: "Enabled", ExpirationDays: 30},
+				},
+			},
+		},
+		Errors: []models.FetchError{
+			{Service: "s3", ResourceType: "aws_s3_bucket", Resource: "denied", Operation: "GetBucketAcl", ErrorCode: "AccessDenied"},
+		},
+	}))
+	require.NoError(t, snap.Add("ec2", &models.EC2LiveState{
+		Instances: []models.EC2Instance{
+			{InstanceID: "i-123", InstanceType: "t3.micro", Tags: map[string]string{"Name": "web"}},
+		},
+	}))
+	require.NoError(t, snap.Add("iam", &models.IAMLiveState{
+		Roles: []models.IAMRole{{RoleName: "app-role", MaxSessionDuration: 3600}},
+	}))
+
+	path := filepath.Join(t.TempDir(), "live.json")
+	require.NoError(t, snapshot.Save(path, snap))
+
+	loaded, err := snapshot.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.CurrentVersion, loaded.Version)
+	assert.Equal(t, "123456789012", loaded.AccountID)
+	assert.Equal(t, "us-east-1", loaded.Region)
+	assert.Equal(t, []string{"ec2", "iam", "s3"}, loaded.ServiceNames())
+
+	rawS3, err := loaded.LiveState("s3")
+	require.NoError(t, err)
+	s3State, ok := rawS3.(*models.S3LiveState)
+	require.True(t, ok)
+	buckets := s3State.Buckets
+	require.Len(t, buckets, 1)
+	assert.Equal(t, "my-bucket", buckets[0].Name)
+	assert.True(t, buckets[0].VersioningEnabled)
+	assert.Equal(t, 30, buckets[0].LifecycleRules[0].ExpirationDays)
+	require.Len(t, s3State.Errors, 1)
+	assert.Equal(t, "AccessDenied", s3State.Errors[0].ErrorCode)
+
+	rawEC2, err := loaded.LiveState("ec2")
+	require.NoError(t, err)
+	ec2State, ok := rawEC2.(*models.EC2LiveState)
+	require.True(t, ok)
+	assert.Equal(t, "web", ec2State.Instances[0].Name())
+
+	rawIAM, err := loaded.LiveState("iam")
+	require.NoError(t, err)
+	iamState, ok := rawIAM.(*models.IAMLiveState)
+	require.True(t, ok)
+	assert.Equal(t, "app-role", iamState.Roles[0].RoleName)
+}
+
+func TestSnapshot_MissingService(t *testing.T) {
+	snap := snapshot.New("", "")
+	require.NoError(t, snap.Add("s3", &models.S3LiveState{}))
+
+	_, err := snap.LiveState("ec2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ec2")
+}
+
+func TestSnapshot_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "services": {}}`), 0644))
+
+	_, err := snapshot.Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported snapshot version")
+}
+
+func TestSnapshot_InvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.json")
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0644))
+
+	_, err := snapshot.Load(path)
+	assert.Error(t, err)
+}
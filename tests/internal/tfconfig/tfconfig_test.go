@@ -0,0 +1,115 @@
+package tfconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inayathulla/cloudrift/internal/tfconfig"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.tf"), `terraform {
+  required_version = ">= 1.5"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+module "app" {
+  source = "./modules/app"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`)
+	writeFile(t, filepath.Join(dir, "iam.tf"), `data "aws_iam_policy_document" "assume" {
+}
+
+  resource "aws_iam_role" "deploy" {
+  name = "deploy"
+  tags = {
+    Team = "platform"
+  }
+}
+`)
+	writeFile(t, filepath.Join(dir, "modules", "app", "main.tf"), `resource "aws_instance" "web" {
+  count         = 2
+  instance_type = "t3.micro"
+}
+`)
+
+	idx, err := tfconfig.Load(dir)
+	require.NoError(t, err)
+	base := filepath.ToSlash(dir)
+
+	assert.Equal(t, tfconfig.Location{File: base + "/main.tf", StartLine: 5, StartColumn: 1, EndLine: 7}, idx["aws_s3_bucket.logs"])
+	assert.Equal(t, tfconfig.Location{File: base + "/iam.tf", StartLine: 4, StartColumn: 3, EndLine: 9}, idx["aws_iam_role.deploy"])
+	assert.Equal(t, base+"/iam.tf", idx["data.aws_iam_policy_document.assume"].File)
+	assert.Equal(t, tfconfig.Location{File: base + "/modules/app/main.tf", StartLine: 1, StartColumn: 1, EndLine: 4}, idx["module.app.aws_instance.web"])
+
+	// Remote modules are not followed
+	assert.Len(t, idx, 4)
+
+	loc, ok := idx.Lookup("module.app.aws_instance.web[1]")
+	assert.True(t, ok)
+	assert.Equal(t, 1, loc.StartLine)
+	assert.Equal(t, base+"/main.tf:5", idx["aws_s3_bucket.logs"].String())
+
+	_, ok = idx.Lookup("aws_s3_bucket.missing")
+	assert.False(t, ok)
+	_, ok = idx.Lookup("")
+	assert.False(t, ok)
+}
+
+func TestLoad_RelativeDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "infra", "main.tf"), "resource \"aws_instance\" \"web\" {}\n")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	idx, err := tfconfig.Load("./infra")
+	require.NoError(t, err)
+	assert.Equal(t, "infra/main.tf", idx["aws_instance.web"].File)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.tf"), "resource \"aws_instance\" \"web\" {\n")
+	_, err := tfconfig.Load(dir)
+	assert.Error(t, err)
+
+	// A module that sources itself
+	dir = t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.tf"), "module \"self\" {\n  source = \"./\"\n}\n")
+	_, err = tfconfig.Load(dir)
+	assert.ErrorContains(t, err, "cycle")
+}
+
+func TestStripInstanceKeys(t *testing.T) {
+	tests := map[string]string{
+		"aws_instance.web":                        "aws_instance.web",
+		"aws_instance.web[0]":                     "aws_instance.web",
+		`aws_s3_bucket.b["logs.example.com"]`:     "aws_s3_bucket.b",
+		`module.app["a]b"].aws_instance.web[2]`:   "module.app.aws_instance.web",
+		`module.app["say \"hi\""].aws_iam_role.r`: "module.app.aws_iam_role.r",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, tfconfig.StripInstanceKeys(in), in)
+	}
+}